@@ -0,0 +1,18 @@
+variable "adverity_password" {
+  type      = string
+  sensitive = true
+  ephemeral = true
+}
+
+ephemeral "adverity_api_token" "automation" {
+  instance_url = "https://example.datatap.adverity.com"
+  username     = "automation@example.com"
+  password     = var.adverity_password
+}
+
+# The token is never stored in the state or plan.
+provider "adverity" {
+  alias        = "automation"
+  instance_url = "https://example.datatap.adverity.com"
+  auth_token   = ephemeral.adverity_api_token.automation.token
+}
@@ -0,0 +1,13 @@
+# Preview the date range a run on 2025-03-05 would fetch
+output "fetch_range" {
+  value = provider::adverity::schedule_fetch_range(
+    {
+      cron_preset          = "CRON_EVERY_DAY"
+      time_range           = "custom"
+      delta_type           = 2 # Weeks
+      delta_interval       = 2
+      delta_interval_start = 1
+    },
+    "2025-03-05T03:00:00Z",
+  )
+}
@@ -0,0 +1,20 @@
+# Preview the next 5 runs of a schedule
+output "next_runs" {
+  value = provider::adverity::schedule_next_runs(
+    {
+      cron_expression = "30 3 * * 1-5"
+      time_range      = "custom"
+    },
+    5,
+    "2025-01-01T00:00:00Z",
+  )
+}
+
+# Preview the next runs of the schedule with the key "daily" of an existing datastream
+output "datastream_next_runs" {
+  value = provider::adverity::schedule_next_runs(
+    one([for s in adverity_datastream.datastream.schedule : s if s.key == "daily"]),
+    3,
+    timestamp(),
+  )
+}
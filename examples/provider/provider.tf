@@ -2,3 +2,68 @@ provider "adverity" {
   instance_url = "https://example.datatap.adverity.com"
   auth_token   = "your-auth-token-goes-here"
 }
+
+# Alternatively, obtain a token with a username and password.
+provider "adverity" {
+  alias        = "credentials"
+  instance_url = "https://example.datatap.adverity.com"
+  username     = "user@example.com"
+  password     = "your-password-goes-here"
+}
+
+# Or read the token with a credential helper, which is run again when the token is rejected.
+provider "adverity" {
+  alias              = "helper"
+  instance_url       = "https://example.datatap.adverity.com"
+  auth_token_command = ["op", "read", "op://Automation/Adverity/token"]
+}
+
+# Or manage several instances, e.g. one per region, with a single provider.
+# Resources select a named instance with their instance attribute.
+provider "adverity" {
+  alias = "regions"
+
+  instances {
+    name         = "emea"
+    instance_url = "https://emea.datatap.adverity.com"
+    auth_token   = var.emea_auth_token
+  }
+
+  instances {
+    name         = "us"
+    instance_url = "https://us.datatap.adverity.com"
+    auth_token   = var.us_auth_token
+  }
+}
+
+# Behind a corporate proxy which inspects TLS traffic, send the requests
+# through the proxy and trust its CA.
+provider "adverity" {
+  alias        = "proxy"
+  instance_url = "https://example.datatap.adverity.com"
+  auth_token   = "your-auth-token-goes-here"
+  proxy_url    = "http://proxy.example.com:3128"
+  ca_cert_file = "/etc/ssl/certs/corporate-ca.pem"
+
+  # Additional headers sent with every request, e.g. for an API gateway.
+  extra_headers = {
+    "X-Tenant" = "emea"
+  }
+}
+
+# During development, point the provider at a local mock server over plain HTTP.
+provider "adverity" {
+  alias               = "local"
+  instance_url        = "http://localhost:8080"
+  auth_token          = "your-auth-token-goes-here"
+  allow_insecure_http = true
+}
+
+# For workspaces with many datastreams, refresh the datastreams, destinations
+# and destination mappings from lists instead of one request per object.
+provider "adverity" {
+  alias            = "large"
+  instance_url     = "https://example.datatap.adverity.com"
+  auth_token       = "your-auth-token-goes-here"
+  refresh_strategy = "batch"
+}
@@ -1,3 +1,6 @@
 # Authorization can be imported by specifying the authorization type id and the authorization id, separated by a colon.
 terraform import adverity_authorization.example 43:812
 
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_authorization.example emea/43:812
@@ -15,7 +15,41 @@ resource "adverity_authorization" "bigquery_authorization" {
 
   authorization_type_id = 284 # BigQuery
 
-  parameters = {
+  # Secret values are not shown in the plan output
+  sensitive_parameters = {
     base64_encoded_credentials = filebase64("path/to/credentials.json")
   }
 }
+
+# Credentials in write-only parameters are never stored in the state (Terraform 1.11 or later).
+# Increment parameters_wo_version to send them again, e.g. after rotating the credentials.
+resource "adverity_authorization" "snowflake" {
+  name     = "snowflake"
+  stack_id = 1
+
+  authorization_type_id = 535 # Snowflake
+
+  parameters = {
+    account  = "example-account"
+    username = "ADVERITY"
+  }
+
+  parameters_wo = {
+    password = var.snowflake_password
+  }
+  parameters_wo_version = 1
+}
+
+# Resources of the deprecated adverity_connection type are migrated with a moved block (Terraform 1.8 or later).
+# The state is converted without sending any request to Adverity.
+moved {
+  from = adverity_connection.google_ads
+  to   = adverity_authorization.google_ads
+}
+
+resource "adverity_authorization" "google_ads" {
+  name     = "google-ads"
+  stack_id = 1
+
+  authorization_type_id = 10 # Google Ads
+}
@@ -0,0 +1,25 @@
+resource "adverity_connection" "bigquery" {
+  name     = "bigquery"
+  stack_id = 1
+
+  connection_type_id = 284 # BigQuery
+
+  parameters = {
+    base64_encoded_credentials = filebase64("path/to/credentials.json")
+  }
+}
+
+resource "adverity_bigquery_destination" "bigquery" {
+  name     = "bigquery"
+  auth_id  = adverity_connection.bigquery.id
+  stack_id = 1
+
+  headers_formatting = "snake_lower"
+
+  project           = "example-project"
+  dataset           = "example-dataset"
+  location          = "EU"
+  partition_by      = "day"
+  partition_column  = "date"
+  clustering_fields = ["campaign"]
+}
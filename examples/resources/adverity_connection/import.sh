@@ -1,3 +1,6 @@
 # Connection can be imported by specifying the connection type id and the connection id, separated by a colon.
 terraform import adverity_connection.example 43:812
 
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_connection.example emea/43:812
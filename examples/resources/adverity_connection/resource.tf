@@ -1,4 +1,4 @@
-# Deprecated - use adverity_authorization instead
+# Deprecated - use adverity_authorization instead, existing resources are migrated with a moved block (Terraform 1.8 or later)
 resource "adverity_connection" "sprinklr" {
   name     = "sprinklr"
   stack_id = 1
@@ -10,7 +10,7 @@ resource "adverity_connection" "sprinklr" {
   }
 }
 
-# Deprecated - use adverity_authorization instead
+# Deprecated - use adverity_authorization instead, existing resources are migrated with a moved block (Terraform 1.8 or later)
 resource "adverity_connection" "bigquery" {
   name     = "bigquery"
   stack_id = 1
@@ -1,3 +1,6 @@
 # Datastream can be imported by specifying the datastream type id and the datastream id, separated by a colon.
 terraform import adverity_datastream.example 43:812
 
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_datastream.example emea/43:812
@@ -25,9 +25,10 @@ resource "adverity_datastream" "datastream" {
   }
 
   schedule {
+    key               = "daily"
     cron_preset       = "CRON_EVERY_DAY"
     cron_start_of_day = "03:33:33"
-    time_range_preset = 0 # Custom
+    time_range        = "custom"
     fixed_start       = "2025-01-01"
   }
 }
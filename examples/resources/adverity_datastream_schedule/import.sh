@@ -0,0 +1,5 @@
+# Datastream schedules can be imported by specifying the datastream type id and the datastream id, separated by a colon.
+terraform import adverity_datastream_schedule.example 43:812
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_datastream_schedule.example emea/43:812
@@ -0,0 +1,31 @@
+resource "adverity_datastream" "datastream" {
+  name     = "sprinklr"
+  auth_id  = adverity_authorization.sprinklr.id
+  stack_id = 1
+
+  datastream_type_id = 576 # Sprinklr
+
+  datatype = "Live"
+
+  # Without schedule blocks, the schedules are left to the adverity_datastream_schedule resource below
+}
+
+resource "adverity_datastream_schedule" "schedule" {
+  datastream_type_id = adverity_datastream.datastream.datastream_type_id
+  datastream_id      = adverity_datastream.datastream.id
+
+  schedule {
+    key               = "daily"
+    cron_preset       = "CRON_EVERY_DAY"
+    cron_start_of_day = "03:33:33"
+    time_range        = "custom"
+    fixed_start       = "2025-01-01"
+  }
+
+  schedule {
+    key             = "weekdays"
+    cron_expression = "30 3 * * 1-5" # At 03:30 on every weekday
+    time_range      = "custom"
+    fixed_start     = "2025-01-01"
+  }
+}
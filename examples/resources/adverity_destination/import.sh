@@ -1,3 +1,6 @@
 # Destination can be imported by specifying the destination type id and the destination id, separated by a colon.
 terraform import adverity_destination.example 43:812
 
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_destination.example emea/43:812
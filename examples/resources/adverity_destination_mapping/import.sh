@@ -1,3 +1,6 @@
 # Destination mapping can be imported by specifying the destination type id, destination id, and mapping id, separated by colons.
 terraform import adverity_destination_mapping.example 43:67:812
 
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_destination_mapping.example emea/43:67:812
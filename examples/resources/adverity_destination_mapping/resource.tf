@@ -16,11 +16,12 @@ resource "adverity_destination" "bigquery" {
 
   destination_type_id = 253 # BigQuery
 
+  headers_formatting = "snake_lower" # replace spaces by underscores and convert letters to lowercase
+
   parameters = {
-    schema_mapping     = true
-    project            = "example-project"
-    dataset            = "example-dataset"
-    headers_formatting = 3 # replace spaces by underscores and convert letters to lowercase
+    schema_mapping = true
+    project        = "example-project"
+    dataset        = "example-dataset"
   }
 }
 
@@ -0,0 +1,28 @@
+resource "adverity_google_ads_datastream" "campaigns" {
+  name     = "Google Ads campaigns"
+  stack_id = 1
+  auth_id  = 1
+
+  accounts    = ["1234567890"]
+  report_type = "campaign"
+  fields = [
+    "campaign.name",
+    "segments.date",
+    "metrics.impressions",
+    "metrics.clicks",
+    "metrics.cost_micros",
+  ]
+  include_zero_impressions = false
+}
+
+resource "adverity_datastream_schedule" "campaigns" {
+  datastream_type_id = adverity_google_ads_datastream.campaigns.datastream_type_id
+  datastream_id      = adverity_google_ads_datastream.campaigns.id
+
+  schedule {
+    key             = "daily"
+    cron_expression = "0 6 * * *"
+    time_range      = "custom"
+    fixed_start     = "2025-01-01"
+  }
+}
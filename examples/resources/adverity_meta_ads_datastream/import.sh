@@ -0,0 +1,5 @@
+# The datastream can be imported by specifying the datastream id, optionally prefixed by the datastream type id and a colon.
+terraform import adverity_meta_ads_datastream.example 812
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_meta_ads_datastream.example emea/812
@@ -0,0 +1,20 @@
+resource "adverity_meta_ads_datastream" "ads" {
+  name     = "Meta Ads insights"
+  stack_id = 1
+  auth_id  = 1
+
+  ad_accounts = ["act_1234567890"]
+  level       = "ad"
+  fields      = ["ad_name", "impressions", "clicks", "spend"]
+  breakdowns  = ["country"]
+
+  action_attribution_windows = ["7d_click", "1d_view"]
+
+  filtering = [
+    {
+      field    = "ad.effective_status"
+      operator = "IN"
+      value    = ["ACTIVE"]
+    }
+  ]
+}
@@ -0,0 +1,5 @@
+# The destination can be imported by specifying the destination id, optionally prefixed by the destination type id and a colon.
+terraform import adverity_snowflake_destination.example 812
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_snowflake_destination.example emea/812
@@ -0,0 +1,11 @@
+resource "adverity_snowflake_destination" "snowflake" {
+  name     = "snowflake"
+  auth_id  = 1
+  stack_id = 1
+
+  database   = "MARKETING"
+  schema     = "ADVERITY"
+  warehouse  = "LOADING"
+  role       = "ADVERITY_LOADER"
+  table_type = "transient"
+}
@@ -1,2 +1,5 @@
 # Workspace can be imported by specifying it's slug.
-terraform import adverity_workspace.example abc
\ No newline at end of file
+terraform import adverity_workspace.example abc
+
+# Objects of a named provider instance are imported by prefixing the ID with the instance name and a slash.
+terraform import adverity_workspace.example emea/abc
@@ -4,18 +4,24 @@
 package adverity
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 )
 
 type AuthorizationConfig struct {
-	Name       *string      `json:"name,omitempty"`
-	StackID    *int64       `json:"stack,omitempty"`
-	Parameters *[]Parameter `json:"-"`
+	Name                *string      `json:"name,omitempty"`
+	StackID             *int64       `json:"stack,omitempty"`
+	Parameters          *[]Parameter `json:"-"`
+	SensitiveParameters *[]Parameter `json:"-"`
 }
 
 func (c *AuthorizationConfig) MarshalJSON() ([]byte, error) {
-	return FlattenedMarshal(c, c.Parameters)
+	return FlattenedMarshal(c, c.Parameters, c.SensitiveParameters)
+}
+
+func (c *AuthorizationConfig) SensitiveKeys() []string {
+	return ParameterKeys(c.SensitiveParameters)
 }
 
 type AuthorizationResponse struct {
@@ -28,30 +34,32 @@ type AuthorizationResponse struct {
 	IsAuthorized  bool   `json:"is_authorized"`
 }
 
-func (c *Client) CreateAuthorization(connectionTypeId int, req *AuthorizationConfig) (*AuthorizationResponse, error) {
+func (r *AuthorizationResponse) unlogged() bool { return true }
+
+func (c *Client) CreateAuthorization(ctx context.Context, connectionTypeId int, req *AuthorizationConfig) (*AuthorizationResponse, error) {
 	r, _ := url.JoinPath("connection-types", strconv.Itoa(connectionTypeId), "connections", "/")
 	p, _ := url.Parse(r)
 
-	return Create[AuthorizationConfig, AuthorizationResponse](c, p, req, nil)
+	return Create[AuthorizationConfig, AuthorizationResponse](ctx, c, p, req, nil)
 }
 
-func (c *Client) ReadAuthorization(connectionTypeId, connectionId int) (*AuthorizationResponse, error) {
+func (c *Client) ReadAuthorization(ctx context.Context, connectionTypeId, connectionId int) (*AuthorizationResponse, error) {
 	r, _ := url.JoinPath("connection-types", strconv.Itoa(connectionTypeId), "connections", strconv.Itoa(connectionId), "/")
 	p, _ := url.Parse(r)
 
-	return Read[AuthorizationResponse](c, p, nil)
+	return Read[AuthorizationResponse](ctx, c, p, nil)
 }
 
-func (c *Client) UpdateAuthorization(connectionTypeId, connectionId int, req *AuthorizationConfig) (*AuthorizationResponse, error) {
+func (c *Client) UpdateAuthorization(ctx context.Context, connectionTypeId, connectionId int, req *AuthorizationConfig) (*AuthorizationResponse, error) {
 	r, _ := url.JoinPath("connection-types", strconv.Itoa(connectionTypeId), "connections", strconv.Itoa(connectionId), "/")
 	p, _ := url.Parse(r)
 
-	return Update[AuthorizationConfig, AuthorizationResponse](c, p, req, nil)
+	return Update[AuthorizationConfig, AuthorizationResponse](ctx, c, p, req, nil)
 }
 
-func (c *Client) DeleteAuthorization(connectionTypeId, connectionId int) (*AuthorizationResponse, error) {
+func (c *Client) DeleteAuthorization(ctx context.Context, connectionTypeId, connectionId int) (*AuthorizationResponse, error) {
 	r, _ := url.JoinPath("connection-types", strconv.Itoa(connectionTypeId), "connections", strconv.Itoa(connectionId), "/")
 	p, _ := url.Parse(r)
 
-	return Delete[AuthorizationResponse](c, p, nil)
+	return Delete[AuthorizationResponse](ctx, c, p, nil)
 }
@@ -4,6 +4,7 @@
 package adverity
 
 import (
+	"context"
 	"net/url"
 )
 
@@ -27,14 +28,14 @@ type authorizationTypeQueryResponse struct {
 	Results  []AuthorizationType `json:"results"`
 }
 
-func (c *Client) QueryAuthorizationTypes(searchTerm string) ([]AuthorizationType, error) {
+func (c *Client) QueryAuthorizationTypes(ctx context.Context, searchTerm string) ([]AuthorizationType, error) {
 	r, _ := url.JoinPath("connection-types", "/")
 	p, _ := url.Parse(r)
 
 	q := &url.Values{}
 	q.Add("search", searchTerm)
 
-	resp, err := Read[authorizationTypeQueryResponse](c, p, q)
+	resp, err := Read[authorizationTypeQueryResponse](ctx, c, p, q)
 	if err != nil {
 		return nil, err
 	}
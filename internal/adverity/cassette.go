@@ -0,0 +1,274 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a cassette records or replays requests.
+type CassetteMode int
+
+const (
+	// CassetteReplay answers the requests with the recorded responses without sending them.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends the requests and records them together with their responses.
+	CassetteRecord
+)
+
+// redactedKeys are the JSON keys whose values are replaced in recorded bodies.
+var redactedKeys = []string{"password", "token", "access_token", "refresh_token", "client_secret", "secret", "api_key", "private_key"}
+
+// redactedValue replaces the values of the redacted keys.
+const redactedValue = "REDACTED"
+
+// clientTokenPattern matches the client tokens of NewClientToken, which are replaced by a fixed token
+// in recorded bodies, so the requests of a replay match the recorded ones.
+var clientTokenPattern = regexp.MustCompile(clientTokenPrefix + `[A-Z2-7]{26}`)
+
+// Interaction is a recorded request and its response. Headers are not recorded, so no
+// credentials end up in a cassette, and paths are relative to the API endpoint, so a cassette
+// is independent of the instance it was recorded with.
+type Interaction struct {
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	Status       int             `json:"status"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+}
+
+// Cassette stores the interactions of a recorded session in a JSON file, e.g. to replay
+// the responses of Adverity in regression tests without an instance.
+type Cassette struct {
+	name string
+	mode CassetteMode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     int
+}
+
+// NewCassette loads the cassette from the file name for replaying, or starts a new cassette
+// for recording, which replaces the file when it is saved.
+func NewCassette(name string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{name: name, mode: mode}
+	if mode == CassetteRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", name, err)
+	}
+	c.interactions = file.Interactions
+
+	return c, nil
+}
+
+// RoundTripper returns a transport which records the requests sent by next, or replays them
+// without next, depending on the mode. Use it with WithRoundTripper.
+func (c *Cassette) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if c.mode == CassetteRecord {
+			return c.record(req, next)
+		}
+		return c.replay(req)
+	})
+}
+
+// Remaining returns the number of recorded interactions which were not replayed yet.
+func (c *Cassette) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.interactions) - c.replayed
+}
+
+// Save writes the recorded interactions to the file of the cassette.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mode != CassetteRecord {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(map[string]any{"interactions": c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.name, append(data, '\n'), 0o600)
+}
+
+func (c *Cassette) record(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, Interaction{
+		Method:       req.Method,
+		Path:         relativePath(req),
+		RequestBody:  sanitizeBody(requestBody),
+		Status:       resp.StatusCode,
+		ResponseBody: sanitizeBody(responseBody),
+	})
+
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replayed >= len(c.interactions) {
+		return nil, fmt.Errorf("cassette %s: unexpected request %s %s, all %d interactions were replayed", c.name, req.Method, relativePath(req), len(c.interactions))
+	}
+
+	// Requests must be sent in the recorded order with the recorded bodies
+	want := c.interactions[c.replayed]
+	got := Interaction{Method: req.Method, Path: relativePath(req), RequestBody: sanitizeBody(requestBody)}
+	if got.Method != want.Method || got.Path != want.Path || !equalJSON(got.RequestBody, want.RequestBody) {
+		return nil, fmt.Errorf("cassette %s: request %d is %s %s %s, recorded %s %s %s", c.name, c.replayed+1,
+			got.Method, got.Path, got.RequestBody, want.Method, want.Path, want.RequestBody)
+	}
+	c.replayed++
+
+	return &http.Response{
+		StatusCode:    want.Status,
+		Status:        fmt.Sprintf("%d %s", want.Status, http.StatusText(want.Status)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(responseBody(want.ResponseBody))),
+		ContentLength: -1,
+		Request:       req,
+	}, nil
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// readBody reads a request or response body and replaces it with a buffer, so it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// relativePath returns the path and query of a request relative to the API endpoint.
+func relativePath(req *http.Request) string {
+	p := req.URL.Path
+	if i := strings.Index(p, "/api/"); i >= 0 {
+		p = p[i+len("/api/"):]
+	}
+	if req.URL.RawQuery != "" {
+		p += "?" + req.URL.RawQuery
+	}
+	return p
+}
+
+// sanitizeBody redacts secrets in a JSON body. Bodies which are not JSON are stored as JSON string.
+func sanitizeBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		text, _ := json.Marshal(string(body))
+		return text
+	}
+	sanitized, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return nil
+	}
+	return sanitized
+}
+
+// redactValue replaces the values of the redacted keys in all objects of a decoded JSON value.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if slices.Contains(redactedKeys, strings.ToLower(key)) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		return clientTokenPattern.ReplaceAllString(v, clientTokenPrefix+redactedValue)
+	}
+	return value
+}
+
+// responseBody returns the body to replay, the content of bodies which were stored as JSON string.
+func responseBody(body json.RawMessage) []byte {
+	var text string
+	if err := json.Unmarshal(body, &text); err == nil {
+		return []byte(text)
+	}
+	return body
+}
+
+// equalJSON reports whether two JSON values are equal regardless of the order of object keys.
+func equalJSON(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,131 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":7,"name":"Google Ads","password":"hunter2"}`))
+	}))
+	defer server.Close()
+
+	name := filepath.Join(t.TempDir(), "cassettes", "authorization.json")
+	params := []Parameter{{Key: "password", Value: "hunter2"}}
+	authName := "Google Ads"
+	create := func(c *Client) (*AuthorizationResponse, error) {
+		return c.CreateAuthorization(t.Context(), 10, &AuthorizationConfig{Name: &authName, Parameters: &params})
+	}
+
+	// Record a session with a real server
+	recorder, err := NewCassette(name, CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(t.Context(), server.URL, WithToken("secret-token"), WithRoundTripper(recorder.RoundTripper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := create(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "secret-token", server.URL} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	// Replay the session without a server
+	player, err := NewCassette(name, CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = NewClient(t.Context(), "https://replay.invalid", WithToken("other-token"), WithRoundTripper(player.RoundTripper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := create(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != 7 || resp.Name != authName {
+		t.Errorf("CreateAuthorization() = %+v, want the recorded response", resp)
+	}
+	if player.Remaining() != 0 {
+		t.Errorf("remaining interactions = %d, want 0", player.Remaining())
+	}
+
+	// Requests beyond the recorded session fail
+	if _, err := create(c); err == nil || !strings.Contains(err.Error(), "all 1 interactions were replayed") {
+		t.Errorf("CreateAuthorization() error = %v, want an unexpected request", err)
+	}
+}
+
+func TestCassetteReplayMismatch(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "workspace.json")
+	cassette := `{"interactions": [{"method": "PATCH", "path": "stacks/marketing/", "request_body": {"name": "Marketing"}, "status": 200, "response_body": {"id": 2, "name": "Marketing"}}]}`
+	if err := os.WriteFile(name, []byte(cassette), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := NewCassette(name, CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(t.Context(), "https://replay.invalid", WithRoundTripper(player.RoundTripper))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := "Sales"
+	if _, err := c.UpdateWorkspace(t.Context(), "marketing", &WorkspaceConfig{Name: &other}); err == nil || !strings.Contains(err.Error(), "recorded PATCH stacks/marketing/") {
+		t.Errorf("UpdateWorkspace() error = %v, want a mismatch with the recorded request", err)
+	}
+	if player.Remaining() != 1 {
+		t.Errorf("remaining interactions = %d, want 1", player.Remaining())
+	}
+}
+
+// Client tokens differ with each run, so they are replayed as a fixed token.
+func TestCassetteReplayClientToken(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "datastream.json")
+	cassette := `{"interactions": [{"method": "POST", "path": "datastream-types/20/datastreams/", "request_body": {"name": "Campaigns", "description": "Daily\n\nterraform-create-REDACTED"}, "status": 201, "response_body": {"id": 3, "name": "Campaigns", "description": "Daily\n\nterraform-create-REDACTED"}}]}`
+	if err := os.WriteFile(name, []byte(cassette), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := NewCassette(name, CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(t.Context(), "https://replay.invalid", WithRoundTripper(player.RoundTripper))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := NewClientToken()
+	if token == NewClientToken() || !clientTokenPattern.MatchString(token) {
+		t.Fatalf("NewClientToken() = %q, want a random token matching %s", token, clientTokenPattern)
+	}
+	datastreamName, description := "Campaigns", "Daily\n\n"+token
+	if _, err := c.CreateDatastream(t.Context(), 20, &DatastreamCreateConfig{Name: &datastreamName, Description: &description}); err != nil {
+		t.Errorf("CreateDatastream() error = %v, want the request with another client token to match", err)
+	}
+}
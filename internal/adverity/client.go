@@ -5,27 +5,94 @@ package adverity
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"maps"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"slices"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Client holds http.Client, endpoint and token.
 type Client struct {
 	httpClient *http.Client
+	transport  *http.Transport
 	endpoint   *url.URL
-	token      string
+
+	tokenMu sync.Mutex
+	token   string
+
+	username string
+	password string
+
+	userAgent string
+	headers   map[string]string
+
+	// refreshToken obtains a new token when a request is rejected as unauthorized, nil if the token cannot be refreshed.
+	refreshToken func(ctx context.Context) (string, error)
+
+	metadataMu sync.Mutex
+	metadata   map[string]map[string]FieldMetadata
+
+	// objectLocks serializes multi-request operations on the same datastream or destination
+	objectLocks keyedMutex
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client) error
+
+// WithToken authenticates the requests with an API token.
+func WithToken(token string) ClientOption {
+	return func(c *Client) error {
+		c.token = token
+		return nil
+	}
+}
+
+// WithCredentials obtains an API token with a username and password when the client is created,
+// unless a token is set as well.
+func WithCredentials(username, password string) ClientOption {
+	return func(c *Client) error {
+		if username == "" || password == "" {
+			return fmt.Errorf("both username and password are required")
+		}
+		c.username = username
+		c.password = password
+		return nil
+	}
 }
 
-// NewClient constructs a new Client with given endpoint and token.
-func NewClient(instanceUrl, authToken *string) (*Client, error) {
-	baseUrl, err := url.Parse(*instanceUrl)
+// WithUserAgent sets the User-Agent header of the requests.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeaders adds headers to the requests, e.g. tenant-specific headers. They cannot replace the
+// Authorization, Content-Type, User-Agent and X-Request-ID headers set by the client.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) error {
+		c.headers = maps.Clone(headers)
+		return nil
+	}
+}
+
+// NewClient constructs a new Client with given endpoint and options.
+// Without a token or credentials, requests are sent unauthenticated (e.g. to obtain a token).
+func NewClient(ctx context.Context, instanceUrl string, opts ...ClientOption) (*Client, error) {
+	baseUrl, err := url.Parse(instanceUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -38,11 +105,42 @@ func NewClient(instanceUrl, authToken *string) (*Client, error) {
 		return nil, err
 	}
 
-	log.Printf("Building API client for %s", apiEndpoint.String())
+	// The transport honors the proxy environment variables (e.g. HTTPS_PROXY) unless a proxy is configured
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport %T", http.DefaultTransport)
+	}
+	transport := defaultTransport.Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	tflog.Debug(ctx, "Building Adverity API client", map[string]any{"endpoint": apiEndpoint.String()})
 	c := Client{
-		httpClient: &http.Client{Timeout: 30 * time.Second, Jar: jar},
+		httpClient: &http.Client{Timeout: 30 * time.Second, Jar: jar, Transport: transport},
+		transport:  transport,
 		endpoint:   apiEndpoint,
-		token:      *authToken,
+		metadata:   make(map[string]map[string]FieldMetadata),
+	}
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.token == "" && c.refreshToken != nil {
+		token, err := c.refreshToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain an API token: %w", err)
+		}
+		c.token = token
+	}
+
+	if c.token == "" && c.username != "" {
+		token, err := c.CreateToken(ctx, c.username, c.password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain an API token for user %s: %w", c.username, err)
+		}
+		c.token = token.Token
 	}
 
 	return &c, nil
@@ -53,25 +151,30 @@ func (c *Client) buildURL(path *url.URL) *url.URL {
 	return c.endpoint.ResolveReference(path)
 }
 
-func (c *Client) Create(path *url.URL, payload io.Reader, query *url.Values) ([]byte, error) {
-	return c.doRequest(http.MethodPost, path, payload, query, nil)
+func (c *Client) Create(ctx context.Context, path *url.URL, payload io.Reader, query *url.Values) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodPost, path, payload, query, nil)
+}
+
+func (c *Client) Read(ctx context.Context, path *url.URL, query *url.Values) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodGet, path, nil, query, nil)
 }
 
-func (c *Client) Read(path *url.URL, query *url.Values) ([]byte, error) {
-	return c.doRequest(http.MethodGet, path, nil, query, nil)
+func (c *Client) Update(ctx context.Context, path *url.URL, payload io.Reader, query *url.Values) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodPatch, path, payload, query, nil)
 }
 
-func (c *Client) Update(path *url.URL, payload io.Reader, query *url.Values) ([]byte, error) {
-	return c.doRequest(http.MethodPatch, path, payload, query, nil)
+func (c *Client) Delete(ctx context.Context, path *url.URL, query *url.Values) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodDelete, path, nil, query, nil)
 }
 
-func (c *Client) Delete(path *url.URL, query *url.Values) ([]byte, error) {
-	return c.doRequest(http.MethodDelete, path, nil, query, nil)
+func (c *Client) Options(ctx context.Context, path *url.URL, query *url.Values) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodOptions, path, nil, query, nil)
 }
 
-//nolint:unparam // authToken may be used to overwrite a token set in Client
-func (c *Client) doRequest(method string, path *url.URL, payload io.Reader, query *url.Values, authToken *string) ([]byte, error) {
-	token := c.token
+// doRequest sends a request. authToken overwrites the token set in Client, an empty token sends the request unauthenticated.
+// If the token of the client is rejected and can be refreshed, the request is sent again once with a new token.
+func (c *Client) doRequest(ctx context.Context, method string, path *url.URL, payload io.Reader, query *url.Values, authToken *string) ([]byte, error) {
+	token := c.currentToken()
 
 	if authToken != nil {
 		token = *authToken
@@ -85,76 +188,203 @@ func (c *Client) doRequest(method string, path *url.URL, payload io.Reader, quer
 		u.RawQuery = query.Encode()
 	}
 
-	// Create the request
-	req, err := http.NewRequest(method, u.String(), payload)
-	if err != nil {
-		return nil, err
-	}
-
 	// Check allowed methods
 	allowedMethods := []string{http.MethodOptions, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
 	if !slices.Contains(allowedMethods, method) {
 		return nil, fmt.Errorf("unsupported method: %s, allowed: %v", method, allowedMethods)
 	}
 
-	// Add headers (e.g., auth token)
-	req.Header.Set("Authorization", fmt.Sprintf("Token %s", token))
+	// Buffer the payload, so the request can be sent again after refreshing the token
+	var data []byte
 	if payload != nil {
+		var err error
+		data, err = io.ReadAll(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.send(ctx, method, u, data, payload != nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.statusCode == http.StatusUnauthorized && authToken == nil && c.refreshToken != nil {
+		tflog.Info(ctx, "Refreshing the rejected Adverity API token", map[string]any{"request_id": resp.requestID})
+		if token, err = c.refresh(ctx, token); err != nil {
+			return nil, fmt.Errorf("failed to refresh the API token: %w", err)
+		}
+		resp, err = c.send(ctx, method, u, data, payload != nil, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle HTTP errors
+	if !slices.Contains(expectedStatusCodes, resp.statusCode) {
+		return nil, &APIError{StatusCode: resp.statusCode, Body: resp.body, RequestID: resp.requestID}
+	}
+
+	return resp.body, nil
+}
+
+// response is the result of a single request.
+type response struct {
+	statusCode int
+	body       []byte
+	// requestID is the X-Request-ID sent with the request, to correlate it with the logs of Adverity.
+	requestID string
+}
+
+// send executes a single request with a new request ID and returns the response.
+func (c *Client) send(ctx context.Context, method string, u *url.URL, payload []byte, hasPayload bool, token string) (*response, error) {
+	requestID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate a request id: %w", err)
+	}
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Add headers (e.g., auth token), the extra headers cannot replace the headers of the client
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	req.Header.Set("X-Request-ID", requestID)
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Token %s", token))
+	}
+	if hasPayload {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
 	// Execute the request
+	fields := map[string]any{"method": method, "url": u.String(), "request_id": requestID}
+	tflog.Debug(ctx, "Sending Adverity API request", fields)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("request id %s: %w", requestID, err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("request id %s: %w", requestID, err)
 	}
 
-	// Handle HTTP errors
-	expectedStatusCodes := []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent}
-	if !slices.Contains(expectedStatusCodes, resp.StatusCode) {
-		return nil, fmt.Errorf("status: %d, body: %s, expected: %v", resp.StatusCode, body, expectedStatusCodes)
+	fields["status"] = resp.StatusCode
+	tflog.Debug(ctx, "Received Adverity API response", fields)
+
+	return &response{statusCode: resp.StatusCode, body: body, requestID: requestID}, nil
+}
+
+// currentToken returns the token of the client.
+func (c *Client) currentToken() string {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.token
+}
+
+// refresh replaces a rejected token and returns the new token. If another request refreshed
+// the token in the meantime, the token is not refreshed again.
+func (c *Client) refresh(ctx context.Context, rejected string) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.token != rejected {
+		return c.token, nil
 	}
 
-	return body, nil
+	token, err := c.refreshToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+
+	return token, nil
+}
+
+func Create[ReqT any, RespT any](ctx context.Context, c *Client, path *url.URL, resource *ReqT, query *url.Values) (*RespT, error) {
+	return execute[ReqT, RespT](ctx, c, http.MethodPost, path, resource, query)
 }
 
-func Create[ReqT any, RespT any](c *Client, path *url.URL, resource *ReqT, query *url.Values) (*RespT, error) {
-	return execute[ReqT, RespT](c, http.MethodPost, path, resource, query)
+func Read[RespT any](ctx context.Context, c *Client, path *url.URL, query *url.Values) (*RespT, error) {
+	return execute[any, RespT](ctx, c, http.MethodGet, path, nil, query)
 }
 
-func Read[RespT any](c *Client, path *url.URL, query *url.Values) (*RespT, error) {
-	return execute[any, RespT](c, http.MethodGet, path, nil, query)
+func Update[ReqT any, RespT any](ctx context.Context, c *Client, path *url.URL, resource *ReqT, query *url.Values) (*RespT, error) {
+	return execute[ReqT, RespT](ctx, c, http.MethodPatch, path, resource, query)
 }
 
-func Update[ReqT any, RespT any](c *Client, path *url.URL, resource *ReqT, query *url.Values) (*RespT, error) {
-	return execute[ReqT, RespT](c, http.MethodPatch, path, resource, query)
+func Delete[RespT any](ctx context.Context, c *Client, path *url.URL, query *url.Values) (*RespT, error) {
+	return execute[any, RespT](ctx, c, http.MethodDelete, path, nil, query)
 }
 
-func Delete[RespT any](c *Client, path *url.URL, query *url.Values) (*RespT, error) {
-	return execute[any, RespT](c, http.MethodDelete, path, nil, query)
+func Options[RespT any](ctx context.Context, c *Client, path *url.URL, query *url.Values) (*RespT, error) {
+	return execute[any, RespT](ctx, c, http.MethodOptions, path, nil, query)
 }
 
-func execute[ReqT any, RespT any](c *Client, method string, path *url.URL, resource *ReqT, query *url.Values) (*RespT, error) {
-	log.Printf("%s %s", method, path.String())
+// listResponse is a page of a list endpoint.
+type listResponse[T any] struct {
+	Count   int64  `json:"count"`
+	Next    string `json:"next"`
+	Results []T    `json:"results"`
+}
+
+// unlogged returns whether the listed objects are not logged (see unloggedResponse).
+func (l *listResponse[T]) unlogged() bool {
+	u, ok := any(new(T)).(unloggedResponse)
+	return ok && u.unlogged()
+}
 
+// List returns the objects of a list endpoint, requesting the pages one after another.
+func List[T any](ctx context.Context, c *Client, path *url.URL, query *url.Values) ([]T, error) {
+	q := url.Values{}
+	if query != nil {
+		q = maps.Clone(*query)
+	}
+
+	var results []T
+	for page := 1; ; page++ {
+		q.Set("page", strconv.Itoa(page))
+		resp, err := Read[listResponse[T]](ctx, c, path, &q)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return results, nil
+		}
+		results = append(results, resp.Results...)
+		if resp.Next == "" {
+			return results, nil
+		}
+	}
+}
+
+func execute[ReqT any, RespT any](ctx context.Context, c *Client, method string, path *url.URL, resource *ReqT, query *url.Values) (*RespT, error) {
 	var r io.Reader
+	var sensitiveKeys []string
 
 	if resource != nil {
 		payload, err := json.Marshal(resource)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal %T: %w", *resource, err)
 		}
-		log.Printf("request payload: %s", payload)
+		// Sensitive values are neither logged in the request nor in the response, which may echo them
+		if s, ok := any(resource).(SensitivePayload); ok {
+			sensitiveKeys = s.SensitiveKeys()
+		}
+		tflog.Debug(ctx, "Adverity API request payload", map[string]any{"payload": string(redactJSON(payload, sensitiveKeys))})
 		r = bytes.NewReader(payload)
 	}
 
-	body, err := c.doRequest(method, path, r, query, nil)
+	body, err := c.doRequest(ctx, method, path, r, query, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +398,11 @@ func execute[ReqT any, RespT any](c *Client, method string, path *url.URL, resou
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal into %T: %w", *resp, err)
 	}
-	log.Printf("response body: %s", body)
+	if u, ok := any(resp).(unloggedResponse); ok && u.unlogged() {
+		tflog.Debug(ctx, "Adverity API response body", map[string]any{"body": "(not logged, the body may contain sensitive values)"})
+	} else {
+		tflog.Debug(ctx, "Adverity API response body", map[string]any{"body": string(redactJSON(body, sensitiveKeys))})
+	}
 
 	return resp, nil
 }
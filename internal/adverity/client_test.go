@@ -0,0 +1,157 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestClientHeaders(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		if len(headers) > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(t.Context(), server.URL,
+		WithToken("secret"),
+		WithUserAgent("terraform-provider-adverity/1.0.0 terraform/1.12.0"),
+		WithHeaders(map[string]string{"X-Tenant": "emea", "Authorization": "Token other"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := url.Parse("workspaces/")
+	if _, err := c.Read(t.Context(), p, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Read(t.Context(), p, nil)
+
+	if len(headers) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(headers))
+	}
+	for name, want := range map[string]string{
+		"User-Agent":    "terraform-provider-adverity/1.0.0 terraform/1.12.0",
+		"X-Tenant":      "emea",
+		"Authorization": "Token secret",
+	} {
+		if got := headers[0].Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+
+	first, second := headers[0].Get("X-Request-ID"), headers[1].Get("X-Request-ID")
+	if first == "" || first == second {
+		t.Errorf("X-Request-ID = %q and %q, want a unique id per request", first, second)
+	}
+	if err == nil || !strings.Contains(err.Error(), "request id: "+second) {
+		t.Errorf("Read() error = %v, want the request id %s", err, second)
+	}
+}
+
+func TestMayHaveSucceeded(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/gateway/":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/missing/":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/slow/":
+			<-release
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c, err := NewClient(t.Context(), server.URL, WithToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]bool{"gateway/": true, "missing/": false, "slow/": true} {
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		p, _ := url.Parse(path)
+		_, err := c.Create(ctx, p, strings.NewReader(`{}`), nil)
+		cancel()
+		if err == nil {
+			t.Fatalf("Create(%s) succeeded, want an error", path)
+		}
+		if got := MayHaveSucceeded(err); got != want {
+			t.Errorf("MayHaveSucceeded(%v) = %t, want %t", err, got, want)
+		}
+	}
+
+	// The status code of unexpected responses is available to the callers
+	p, _ := url.Parse("missing/")
+	_, err = c.Read(t.Context(), p, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.RequestID == "" {
+		t.Errorf("Read() error = %#v, want an APIError with status 404", err)
+	}
+}
+
+// The bodies of authorization and destination responses are not logged, since reads send no payload naming their sensitive keys.
+func TestExecuteLogsNoSensitiveResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/targets/") {
+			_, _ = w.Write([]byte(`{"count": 1, "next": null, "results": [{"id": 1, "password": "secret"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 1, "name": "Campaigns", "password": "secret"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(t.Context(), server.URL, WithToken("token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		read    func(ctx context.Context) error
+		wantLog bool
+	}{
+		"authorization": {
+			read: func(ctx context.Context) error { _, err := c.ReadAuthorization(ctx, 10, 1); return err },
+		},
+		"destination": {
+			read: func(ctx context.Context) error { _, err := c.ReadDestination(ctx, 30, 1); return err },
+		},
+		"destination list": {
+			read: func(ctx context.Context) error { _, err := c.ListDestinations(ctx, 1); return err },
+		},
+		"datastream": {
+			read:    func(ctx context.Context) error { _, err := c.ReadDatastream(ctx, 20, 1); return err },
+			wantLog: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs bytes.Buffer
+			if err := test.read(tflogtest.RootLogger(t.Context(), &logs)); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(logs.String(), "Campaigns") || strings.Contains(logs.String(), "secret"); got != test.wantLog {
+				t.Errorf("response body logged = %v, want %v: %s", got, test.wantLog, logs.String())
+			}
+		})
+	}
+}
@@ -4,17 +4,30 @@
 package adverity
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"reflect"
+	"strings"
 )
 
+// clientTokenPrefix is the prefix of the client tokens, see NewClientToken.
+const clientTokenPrefix = "terraform-create-"
+
+// NewClientToken returns a random token for a create request, which the request stores in the created object
+// (e.g. in the description of a datastream), so the object can be told apart from other objects with the same
+// name if the request fails.
+func NewClientToken() string {
+	return clientTokenPrefix + rand.Text()
+}
+
 type Parameter struct {
 	Key   string      `json:"key"`
 	Value interface{} `json:"value"`
 }
 
-// FlattenedMarshal flattens a slice of Parameter into the base struct.
-func FlattenedMarshal(base interface{}, params *[]Parameter) ([]byte, error) {
+// FlattenedMarshal flattens slices of Parameter into the base struct.
+// Parameters of later slices overwrite parameters of earlier slices with the same key.
+func FlattenedMarshal(base interface{}, params ...*[]Parameter) ([]byte, error) {
 	// Use reflection to get the underlying value
 	v := reflect.ValueOf(base)
 
@@ -40,11 +53,94 @@ func FlattenedMarshal(base interface{}, params *[]Parameter) ([]byte, error) {
 	}
 
 	// Add parameters to map
-	if params != nil {
-		for _, p := range *params {
+	for _, ps := range params {
+		if ps == nil {
+			continue
+		}
+		for _, p := range *ps {
 			merged[p.Key] = p.Value
 		}
 	}
 
 	return json.Marshal(merged)
 }
+
+// SensitivePayload is implemented by payloads with parameters whose values must not be logged.
+type SensitivePayload interface {
+	SensitiveKeys() []string
+}
+
+// unloggedResponse is implemented by responses whose bodies are not logged, since any of their parameters
+// may hold a secret (e.g. the credentials of an authorization or destination), whose keys depend on the type
+// and are unknown for reads, which send no payload with the sensitive keys.
+type unloggedResponse interface {
+	unlogged() bool
+}
+
+// ParameterKeys returns the keys of parameters.
+func ParameterKeys(params *[]Parameter) []string {
+	if params == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(*params))
+	for _, p := range *params {
+		keys = append(keys, p.Key)
+	}
+
+	return keys
+}
+
+// redactJSON replaces the values of the given top-level keys of a JSON object for logging.
+// Bodies which are not JSON objects are not logged at all if any key is sensitive.
+func redactJSON(body []byte, keys []string) []byte {
+	if len(keys) == 0 {
+		return body
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return []byte("(redacted, the body may contain sensitive values)")
+	}
+
+	redacted, _ := json.Marshal("(sensitive value)")
+	for _, key := range keys {
+		if _, ok := object[key]; ok {
+			object[key] = redacted
+		}
+	}
+
+	b, err := json.Marshal(object)
+	if err != nil {
+		return []byte("(redacted, the body may contain sensitive values)")
+	}
+
+	return b
+}
+
+// PayloadFields returns the JSON field names of a payload struct, excluding the flattened parameters.
+func PayloadFields(payload interface{}) []string {
+	t := reflect.TypeOf(payload)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// unmarshalWithFields decodes a response object into v and keeps all of its top-level fields in fields,
+// including the flattened parameters, which have no field in the response struct.
+func unmarshalWithFields(b []byte, v any, fields *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, fields)
+}
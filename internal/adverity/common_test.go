@@ -0,0 +1,62 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFlattenedMarshalSensitiveParameters(t *testing.T) {
+	name := "bigquery"
+	config := &AuthorizationConfig{
+		Name:                &name,
+		Parameters:          &[]Parameter{{Key: "project", Value: "example"}},
+		SensitiveParameters: &[]Parameter{{Key: "password", Value: "secret"}},
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"name":"bigquery","password":"secret","project":"example"}`; got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+
+	if got := string(redactJSON(b, config.SensitiveKeys())); strings.Contains(got, "secret") || !strings.Contains(got, "example") {
+		t.Errorf("redactJSON() = %s, want the sensitive value redacted", got)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	tests := map[string]struct {
+		body string
+		keys []string
+		want string
+	}{
+		"no sensitive keys": {
+			body: `[1,2]`,
+			want: `[1,2]`,
+		},
+		"object": {
+			body: `{"password":"secret","name":"example"}`,
+			keys: []string{"password", "token"},
+			want: `{"name":"example","password":"(sensitive value)"}`,
+		},
+		"not an object": {
+			body: `["secret"]`,
+			keys: []string{"password"},
+			want: `(redacted, the body may contain sensitive values)`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := string(redactJSON([]byte(tt.body), tt.keys)); got != tt.want {
+				t.Errorf("redactJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
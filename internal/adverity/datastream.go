@@ -4,7 +4,10 @@
 package adverity
 
 import (
+	"context"
+	"encoding/json"
 	"net/url"
+	"slices"
 	"strconv"
 )
 
@@ -103,39 +106,68 @@ type DatastreamResponse struct {
 	IsInsightsMediaplan bool       `json:"is_insights_mediaplan"`
 	ManageExtractNames  bool       `json:"manage_extract_names"`
 	ExtractNameKeys     string     `json:"extract_name_keys"`
+	// Fields holds all top-level fields of the response, including the parameters of the datastream type.
+	Fields map[string]json.RawMessage `json:"-"`
 }
 
-func (c *Client) CreateDatastream(datastreamTypeId int, req *DatastreamCreateConfig) (*DatastreamResponse, error) {
+func (r *DatastreamResponse) UnmarshalJSON(b []byte) error {
+	type response DatastreamResponse
+	return unmarshalWithFields(b, (*response)(r), &r.Fields)
+}
+
+func (c *Client) CreateDatastream(ctx context.Context, datastreamTypeId int, req *DatastreamCreateConfig) (*DatastreamResponse, error) {
 	r, _ := url.JoinPath("datastream-types", strconv.Itoa(datastreamTypeId), "datastreams", "/")
 	p, _ := url.Parse(r)
 
-	return Create[DatastreamCreateConfig, DatastreamResponse](c, p, req, nil)
+	return Create[DatastreamCreateConfig, DatastreamResponse](ctx, c, p, req, nil)
+}
+
+// ListDatastreams returns the datastreams of a workspace.
+func (c *Client) ListDatastreams(ctx context.Context, stackId int) ([]DatastreamResponse, error) {
+	r, _ := url.JoinPath("datastreams", "/")
+	p, _ := url.Parse(r)
+
+	q := &url.Values{}
+	q.Add("stack", strconv.Itoa(stackId))
+
+	return List[DatastreamResponse](ctx, c, p, q)
+}
+
+// FindDatastreams returns the datastreams of the type with the name in a workspace.
+func (c *Client) FindDatastreams(ctx context.Context, stackId, datastreamTypeId int, name string) ([]DatastreamResponse, error) {
+	datastreams, err := c.ListDatastreams(ctx, stackId)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(datastreams, func(d DatastreamResponse) bool {
+		return d.DatastreamTypeID != int64(datastreamTypeId) || d.Name != name
+	}), nil
 }
 
-func (c *Client) ReadDatastream(datastreamTypeId, datastreamId int) (*DatastreamResponse, error) {
+func (c *Client) ReadDatastream(ctx context.Context, datastreamTypeId, datastreamId int) (*DatastreamResponse, error) {
 	r, _ := url.JoinPath("datastream-types", strconv.Itoa(datastreamTypeId), "datastreams", strconv.Itoa(datastreamId), "/")
 	p, _ := url.Parse(r)
 
-	return Read[DatastreamResponse](c, p, nil)
+	return Read[DatastreamResponse](ctx, c, p, nil)
 }
 
-func (c *Client) UpdateDatastream(datastreamTypeId, datastreamId int, req *DatastreamUpdateConfig) (*DatastreamResponse, error) {
+func (c *Client) UpdateDatastream(ctx context.Context, datastreamTypeId, datastreamId int, req *DatastreamUpdateConfig) (*DatastreamResponse, error) {
 	r, _ := url.JoinPath("datastream-types", strconv.Itoa(datastreamTypeId), "datastreams", strconv.Itoa(datastreamId), "/")
 	p, _ := url.Parse(r)
 
-	return Update[DatastreamUpdateConfig, DatastreamResponse](c, p, req, nil)
+	return Update[DatastreamUpdateConfig, DatastreamResponse](ctx, c, p, req, nil)
 }
 
-func (c *Client) DeleteDatastream(datastreamTypeId, datastreamId int) (*DatastreamResponse, error) {
+func (c *Client) DeleteDatastream(ctx context.Context, datastreamTypeId, datastreamId int) (*DatastreamResponse, error) {
 	r, _ := url.JoinPath("datastream-types", strconv.Itoa(datastreamTypeId), "datastreams", strconv.Itoa(datastreamId), "/")
 	p, _ := url.Parse(r)
 
-	return Delete[DatastreamResponse](c, p, nil)
+	return Delete[DatastreamResponse](ctx, c, p, nil)
 }
 
-func (c *Client) UpdateDatastreamSchedule(datastreamId int, req *DatastreamScheduleConfig) (*DatastreamResponse, error) {
+func (c *Client) UpdateDatastreamSchedule(ctx context.Context, datastreamId int, req *DatastreamScheduleConfig) (*DatastreamResponse, error) {
 	r, _ := url.JoinPath("datastreams", strconv.Itoa(datastreamId), "/")
 	p, _ := url.Parse(r)
 
-	return Update[DatastreamScheduleConfig, DatastreamResponse](c, p, req, nil)
+	return Update[DatastreamScheduleConfig, DatastreamResponse](ctx, c, p, req, nil)
 }
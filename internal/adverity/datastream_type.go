@@ -4,6 +4,7 @@
 package adverity
 
 import (
+	"context"
 	"net/url"
 )
 
@@ -28,14 +29,14 @@ type datastreamTypeQueryResponse struct {
 	Results  []DatastreamType `json:"results"`
 }
 
-func (c *Client) QueryDatastreamTypes(searchTerm string) ([]DatastreamType, error) {
+func (c *Client) QueryDatastreamTypes(ctx context.Context, searchTerm string) ([]DatastreamType, error) {
 	r, _ := url.JoinPath("datastream-types", "/")
 	p, _ := url.Parse(r)
 
 	q := &url.Values{}
 	q.Add("search", searchTerm)
 
-	resp, err := Read[datastreamTypeQueryResponse](c, p, q)
+	resp, err := Read[datastreamTypeQueryResponse](ctx, c, p, q)
 	if err != nil {
 		return nil, err
 	}
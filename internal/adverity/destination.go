@@ -4,7 +4,10 @@
 package adverity
 
 import (
+	"context"
+	"encoding/json"
 	"net/url"
+	"slices"
 	"strconv"
 )
 
@@ -18,10 +21,15 @@ type DestinationConfig struct {
 	ColumnNamesToLowerCase *bool        `json:"column_names_to_lowercase,omitempty"`
 	HeadersFormatting      *int64       `json:"headers_formatting,omitempty"`
 	Parameters             *[]Parameter `json:"-"`
+	SensitiveParameters    *[]Parameter `json:"-"`
 }
 
 func (c *DestinationConfig) MarshalJSON() ([]byte, error) {
-	return FlattenedMarshal(c, c.Parameters)
+	return FlattenedMarshal(c, c.Parameters, c.SensitiveParameters)
+}
+
+func (c *DestinationConfig) SensitiveKeys() []string {
+	return ParameterKeys(c.SensitiveParameters)
 }
 
 type DestinationResponse struct {
@@ -38,13 +46,22 @@ type DestinationResponse struct {
 	HeadersFormatting       int64  `json:"headers_formatting"`
 	StackID                 int64  `json:"stack"`
 	AuthID                  int64  `json:"auth"`
+	// Fields holds all top-level fields of the response, including the parameters of the destination type.
+	Fields map[string]json.RawMessage `json:"-"`
+}
+
+func (r *DestinationResponse) unlogged() bool { return true }
+
+func (r *DestinationResponse) UnmarshalJSON(b []byte) error {
+	type response DestinationResponse
+	return unmarshalWithFields(b, (*response)(r), &r.Fields)
 }
 
-func (c *Client) CreateDestination(destinationTypeId int, req *DestinationConfig) (*DestinationResponse, error) {
+func (c *Client) CreateDestination(ctx context.Context, destinationTypeId int, req *DestinationConfig) (*DestinationResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Create[DestinationConfig, DestinationResponse](c, p, req, nil)
+	resp, err := Create[DestinationConfig, DestinationResponse](ctx, c, p, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -52,11 +69,43 @@ func (c *Client) CreateDestination(destinationTypeId int, req *DestinationConfig
 	return resp, nil
 }
 
-func (c *Client) ReadDestination(destinationTypeId, destinationId int) (*DestinationResponse, error) {
+// ListDestinations returns the destinations of a workspace. The rows do not report the type of the destinations.
+func (c *Client) ListDestinations(ctx context.Context, stackId int) ([]DestinationResponse, error) {
+	r, _ := url.JoinPath("targets", "/")
+	p, _ := url.Parse(r)
+
+	q := &url.Values{}
+	q.Add("stack", strconv.Itoa(stackId))
+
+	return List[DestinationResponse](ctx, c, p, q)
+}
+
+// ListDestinationsOfType returns the destinations of a type in a workspace. Unlike ListDestinations, the
+// destinations are known to be of the type, although the rows may lack parameters of the type.
+func (c *Client) ListDestinationsOfType(ctx context.Context, destinationTypeId, stackId int) ([]DestinationResponse, error) {
+	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", "/")
+	p, _ := url.Parse(r)
+
+	q := &url.Values{}
+	q.Add("stack", strconv.Itoa(stackId))
+
+	return List[DestinationResponse](ctx, c, p, q)
+}
+
+// FindDestinations returns the destinations with the name in a workspace.
+func (c *Client) FindDestinations(ctx context.Context, stackId int, name string) ([]DestinationResponse, error) {
+	destinations, err := c.ListDestinations(ctx, stackId)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(destinations, func(d DestinationResponse) bool { return d.Name != name }), nil
+}
+
+func (c *Client) ReadDestination(ctx context.Context, destinationTypeId, destinationId int) (*DestinationResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Read[DestinationResponse](c, p, nil)
+	resp, err := Read[DestinationResponse](ctx, c, p, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +113,11 @@ func (c *Client) ReadDestination(destinationTypeId, destinationId int) (*Destina
 	return resp, nil
 }
 
-func (c *Client) UpdateDestination(destinationTypeId, destinationId int, req *DestinationConfig) (*DestinationResponse, error) {
+func (c *Client) UpdateDestination(ctx context.Context, destinationTypeId, destinationId int, req *DestinationConfig) (*DestinationResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Update[DestinationConfig, DestinationResponse](c, p, req, nil)
+	resp, err := Update[DestinationConfig, DestinationResponse](ctx, c, p, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +125,11 @@ func (c *Client) UpdateDestination(destinationTypeId, destinationId int, req *De
 	return resp, nil
 }
 
-func (c *Client) DeleteDestination(destinationTypeId, destinationId int) (*DestinationResponse, error) {
+func (c *Client) DeleteDestination(ctx context.Context, destinationTypeId, destinationId int) (*DestinationResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Delete[DestinationResponse](c, p, nil)
+	resp, err := Delete[DestinationResponse](ctx, c, p, nil)
 	if err != nil {
 		return nil, err
 	}
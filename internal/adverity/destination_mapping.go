@@ -4,6 +4,7 @@
 package adverity
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 )
@@ -27,11 +28,11 @@ type DestinationMappingResponse struct {
 	TableName     string `json:"table_name"`
 }
 
-func (c *Client) CreateDestinationMapping(destinationTypeId, destinationId int, req *DestinationMappingConfig) (*DestinationMappingResponse, error) {
+func (c *Client) CreateDestinationMapping(ctx context.Context, destinationTypeId, destinationId int, req *DestinationMappingConfig) (*DestinationMappingResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "mappings", "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Create[DestinationMappingConfig, DestinationMappingResponse](c, p, req, nil)
+	resp, err := Create[DestinationMappingConfig, DestinationMappingResponse](ctx, c, p, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -39,11 +40,19 @@ func (c *Client) CreateDestinationMapping(destinationTypeId, destinationId int,
 	return resp, nil
 }
 
-func (c *Client) ReadDestinationMapping(destinationTypeId, destinationId, destinationMappingId int) (*DestinationMappingResponse, error) {
+// ListDestinationMappings returns the mappings of a destination.
+func (c *Client) ListDestinationMappings(ctx context.Context, destinationTypeId, destinationId int) ([]DestinationMappingResponse, error) {
+	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "mappings", "/")
+	p, _ := url.Parse(r)
+
+	return List[DestinationMappingResponse](ctx, c, p, nil)
+}
+
+func (c *Client) ReadDestinationMapping(ctx context.Context, destinationTypeId, destinationId, destinationMappingId int) (*DestinationMappingResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "mappings", strconv.Itoa(destinationMappingId), "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Read[DestinationMappingResponse](c, p, nil)
+	resp, err := Read[DestinationMappingResponse](ctx, c, p, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -51,11 +60,11 @@ func (c *Client) ReadDestinationMapping(destinationTypeId, destinationId, destin
 	return resp, nil
 }
 
-func (c *Client) UpdateDestinationMapping(destinationTypeId, destinationId, destinationMappingId int, req *DestinationMappingConfig) (*DestinationMappingResponse, error) {
+func (c *Client) UpdateDestinationMapping(ctx context.Context, destinationTypeId, destinationId, destinationMappingId int, req *DestinationMappingConfig) (*DestinationMappingResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "mappings", strconv.Itoa(destinationMappingId), "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Update[DestinationMappingConfig, DestinationMappingResponse](c, p, req, nil)
+	resp, err := Update[DestinationMappingConfig, DestinationMappingResponse](ctx, c, p, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -63,11 +72,11 @@ func (c *Client) UpdateDestinationMapping(destinationTypeId, destinationId, dest
 	return resp, nil
 }
 
-func (c *Client) DeleteDestinationMapping(destinationTypeId, destinationId, destinationMappingId int) (*DestinationMappingResponse, error) {
+func (c *Client) DeleteDestinationMapping(ctx context.Context, destinationTypeId, destinationId, destinationMappingId int) (*DestinationMappingResponse, error) {
 	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "mappings", strconv.Itoa(destinationMappingId), "/")
 	p, _ := url.Parse(r)
 
-	resp, err := Delete[DestinationMappingResponse](c, p, nil)
+	resp, err := Delete[DestinationMappingResponse](ctx, c, p, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@
 package adverity
 
 import (
+	"context"
 	"net/url"
 )
 
@@ -22,14 +23,14 @@ type destinationTypeQueryResponse struct {
 	Results  []DestinationType `json:"results"`
 }
 
-func (c *Client) QueryDestinationTypes(searchTerm string) ([]DestinationType, error) {
+func (c *Client) QueryDestinationTypes(ctx context.Context, searchTerm string) ([]DestinationType, error) {
 	r, _ := url.JoinPath("target-types", "/")
 	p, _ := url.Parse(r)
 
 	q := &url.Values{}
 	q.Add("search", searchTerm)
 
-	resp, err := Read[destinationTypeQueryResponse](c, p, q)
+	resp, err := Read[destinationTypeQueryResponse](ctx, c, p, q)
 	if err != nil {
 		return nil, err
 	}
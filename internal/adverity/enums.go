@@ -0,0 +1,107 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Enum maps the numeric codes of an Adverity setting to readable names.
+type Enum map[int64]string
+
+// Name returns the readable name of a numeric code.
+func (e Enum) Name(code int64) (string, bool) {
+	name, ok := e[code]
+	return name, ok
+}
+
+// Code returns the numeric code of a readable name.
+func (e Enum) Code(name string) (int64, bool) {
+	for code, n := range e {
+		if n == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// Names returns the readable names ordered by their numeric codes.
+func (e Enum) Names() []string {
+	names := make([]string, 0, len(e))
+	for _, code := range slices.Sorted(maps.Keys(e)) {
+		names = append(names, e[code])
+	}
+	return names
+}
+
+// CheckChoice returns an error if the field offers choices but none of them is the code, i.e. the
+// instance may not support it. Fields without choices are not checked.
+func (e Enum) CheckChoice(field FieldMetadata, code int64) error {
+	if len(field.Choices) == 0 {
+		return nil
+	}
+
+	choices := make([]string, 0, len(field.Choices))
+	for _, choice := range field.Choices {
+		value := fmt.Sprint(choice.Value)
+		if value == strconv.FormatInt(code, 10) {
+			return nil
+		}
+		choices = append(choices, fmt.Sprintf("%s (%s)", value, choice.DisplayName))
+	}
+
+	return fmt.Errorf("the code %d is not offered, expected one of %s", code, strings.Join(choices, ", "))
+}
+
+// HeadersFormattings maps how a destination formats the column headers. Only the code used by
+// the examples of earlier provider versions is mapped, other codes are set with the
+// headers_formatting parameter.
+var HeadersFormattings = Enum{
+	3: "snake_lower", // replace spaces by underscores and convert letters to lowercase
+}
+
+// Cron types of a schedule. The meaning of cron_interval_start depends on the cron type:
+// the hour of day the interval starts at for CronTypeHour, the ISO weekday (1 = Monday,
+// 7 = Sunday) for CronTypeWeek and the day of month for CronTypeMonth.
+// The time of day is set by cron_start_of_day (only minutes and seconds for CronTypeHour).
+const (
+	CronTypeHour    = "hour"
+	CronTypeDay     = "day"
+	CronTypeWeekday = "weekday"
+	CronTypeWeek    = "week"
+	CronTypeMonth   = "month"
+)
+
+// CronPreset holds the cron fields Adverity derives from a cron preset.
+type CronPreset struct {
+	Type     string
+	Interval int64
+}
+
+// CronPresets maps the cron presets offered by Adverity to their cron fields.
+var CronPresets = map[string]CronPreset{
+	"CRON_EVERY_HOUR":     {Type: CronTypeHour, Interval: 1},
+	"CRON_EVERY_3_HOURS":  {Type: CronTypeHour, Interval: 3},
+	"CRON_EVERY_6_HOURS":  {Type: CronTypeHour, Interval: 6},
+	"CRON_EVERY_12_HOURS": {Type: CronTypeHour, Interval: 12},
+	"CRON_EVERY_DAY":      {Type: CronTypeDay, Interval: 1},
+	"CRON_EVERY_WEEKDAY":  {Type: CronTypeWeekday, Interval: 1},
+	"CRON_EVERY_WEEK":     {Type: CronTypeWeek, Interval: 1},
+	"CRON_EVERY_MONTH":    {Type: CronTypeMonth, Interval: 1},
+}
+
+// TimeRangePresetCustom fetches the range defined by the delta and fixed fields of a schedule
+// (see the examples of earlier provider versions).
+const TimeRangePresetCustom int64 = 0
+
+// TimeRanges maps the time range presets of a schedule. Adverity does not describe the fields of
+// schedules in the OPTIONS metadata, so only the custom time range is mapped and other presets are
+// set with time_range_preset.
+var TimeRanges = Enum{
+	TimeRangePresetCustom: "custom",
+}
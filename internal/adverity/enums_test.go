@@ -0,0 +1,77 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	e := Enum{0: "none", 3: "snake_lower"}
+
+	if name, ok := e.Name(3); !ok || name != "snake_lower" {
+		t.Errorf("Name(3) = %q, %v, want snake_lower", name, ok)
+	}
+	if _, ok := e.Name(4); ok {
+		t.Error("Name(4) is ok, want an unknown code")
+	}
+	if code, ok := e.Code("none"); !ok || code != 0 {
+		t.Errorf("Code(none) = %d, %v, want 0", code, ok)
+	}
+	if _, ok := e.Code("None"); ok {
+		t.Error("Code(None) is ok, want names to be case sensitive")
+	}
+	if got, want := e.Names(), []string{"none", "snake_lower"}; !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestEnumCheckChoice(t *testing.T) {
+	field := FieldMetadata{
+		Type: "choice",
+		// values are decoded from JSON as float64
+		Choices: []FieldChoice{{Value: float64(0), DisplayName: "No formatting"}, {Value: float64(3), DisplayName: "Snake and lower case"}},
+	}
+
+	tests := map[string]struct {
+		field   FieldMetadata
+		code    int64
+		wantErr string
+	}{
+		"offered choice": {
+			field: field,
+			code:  3,
+		},
+		"missing choice": {
+			field:   field,
+			code:    2,
+			wantErr: "expected one of 0 (No formatting), 3 (Snake and lower case)",
+		},
+		"string values": {
+			field: FieldMetadata{Type: "choice", Choices: []FieldChoice{{Value: "3", DisplayName: "Snake and lower case"}}},
+			code:  3,
+		},
+		"field without choices": {
+			field: FieldMetadata{Type: "integer"},
+			code:  5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := HeadersFormattings.CheckChoice(test.field, test.code)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckChoice() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("CheckChoice() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
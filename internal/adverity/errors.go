@@ -0,0 +1,38 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// expectedStatusCodes are the status codes of successful responses.
+var expectedStatusCodes = []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent}
+
+// APIError is returned for responses with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	// RequestID is the X-Request-ID sent with the request, to correlate it with the logs of Adverity.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s, expected: %v, request id: %s", e.StatusCode, e.Body, expectedStatusCodes, e.RequestID)
+}
+
+// MayHaveSucceeded reports whether a failed request may have been processed by Adverity anyway, because
+// it timed out or failed with a server error. A create request failing like this may have created the object.
+func MayHaveSucceeded(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,782 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fakeserver implements an in-memory stand-in for the Adverity API, so the provider
+// can be tested offline. It implements the endpoints used by the client: stacks, connection
+// types and connections, datastream types and datastreams (including schedules), target types,
+// targets and mappings, the lists of datastreams, targets (also per type) and mappings, OPTIONS
+// metadata and API tokens.
+//
+// The server mimics the behavior of Adverity the provider depends on: datastreams created
+// without schedules get a default schedule, schedules are not returned in a stable order, and
+// the cron fields of schedules are derived from their presets.
+package fakeserver
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+// DefaultToken is the API token accepted by a new server.
+const DefaultToken = "fake-token"
+
+// RootStackID is the ID of the workspace every new server contains, e.g. as parent of new workspaces.
+const RootStackID int64 = 1
+
+// Type is a connection, datastream or target type offered by the server.
+type Type struct {
+	ID   int64
+	Name string
+	Slug string
+	// Fields are returned by OPTIONS requests for the objects of the type. Required fields
+	// are enforced when objects are created. Without fields, any parameters are accepted.
+	Fields map[string]adverity.FieldMetadata
+}
+
+// object is a stored API object. Its fields are returned as they are.
+type object struct {
+	// parent is the type of a connection, datastream or target, or the target of a mapping.
+	parent int64
+	fields map[string]any
+}
+
+// Server is a fake Adverity API served over TLS. Use URL as instance URL of the client
+// and trust the certificate returned by CertificatePEM.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	lastID int64
+	token  string
+	users  map[string]string
+	// userTokens are the API tokens obtained by the users, by token.
+	userTokens   map[string]string
+	issuedTokens int
+
+	connectionTypes map[int64]Type
+	datastreamTypes map[int64]Type
+	targetTypes     map[int64]Type
+
+	stacks      map[int64]*object
+	connections map[int64]*object
+	datastreams map[int64]*object
+	targets     map[int64]*object
+	mappings    map[int64]*object
+
+	requests []string
+}
+
+// New starts a server with a root workspace and no types. Close it when done.
+func New() *Server {
+	s := &Server{
+		lastID:          RootStackID,
+		token:           DefaultToken,
+		users:           make(map[string]string),
+		userTokens:      make(map[string]string),
+		connectionTypes: make(map[int64]Type),
+		datastreamTypes: make(map[int64]Type),
+		targetTypes:     make(map[int64]Type),
+		stacks:          make(map[int64]*object),
+		connections:     make(map[int64]*object),
+		datastreams:     make(map[int64]*object),
+		targets:         make(map[int64]*object),
+		mappings:        make(map[int64]*object),
+	}
+	s.stacks[RootStackID] = &object{fields: map[string]any{
+		"id":        RootStackID,
+		"name":      "Root",
+		"slug":      "root",
+		"parent_id": 0,
+	}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// CertificatePEM returns the PEM encoded certificate of the server.
+func (s *Server) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+}
+
+// SetToken replaces the API token accepted by the server, e.g. to expire the token of a client.
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// AddUser adds a user who can obtain an API token with a password. The token is accepted
+// like the token of the server until it is revoked.
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// AddConnectionType adds a connection type, whose connections are also called authorizations.
+func (s *Server) AddConnectionType(t Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connectionTypes[t.ID] = t
+}
+
+// AddDatastreamType adds a datastream type.
+func (s *Server) AddDatastreamType(t Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.datastreamTypes[t.ID] = t
+}
+
+// AddTargetType adds a target type, whose targets are called destinations by the provider.
+func (s *Server) AddTargetType(t Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.targetTypes[t.ID] = t
+}
+
+// Requests returns the method and path of all requests received so far, e.g. "PATCH /api/stacks/root/".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Modify changes the fields of a stored object outside of the API, e.g. to simulate changes
+// made in the Adverity UI. The kind is one of stack, connection, datastream, target or mapping.
+func (s *Server) Modify(kind string, id int64, fields map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.collection(kind)[id]
+	if !ok {
+		return fmt.Errorf("%s %d not found", kind, id)
+	}
+	maps.Copy(o.fields, fields)
+
+	return nil
+}
+
+// Remove deletes a stored object outside of the API. The kind is one of stack, connection,
+// datastream, target or mapping.
+func (s *Server) Remove(kind string, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects := s.collection(kind)
+	if _, ok := objects[id]; !ok {
+		return fmt.Errorf("%s %d not found", kind, id)
+	}
+	delete(objects, id)
+
+	return nil
+}
+
+// Exists reports whether a stored object exists. The kind is one of stack, connection,
+// datastream, target or mapping.
+func (s *Server) Exists(kind string, id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.collection(kind)[id]
+	return ok
+}
+
+func (s *Server) collection(kind string) map[int64]*object {
+	switch kind {
+	case "stack":
+		return s.stacks
+	case "connection":
+		return s.connections
+	case "datastream":
+		return s.datastreams
+	case "target":
+		return s.targets
+	case "mapping":
+		return s.mappings
+	}
+	panic("unknown kind " + kind)
+}
+
+// apiError is an error response of the API.
+type apiError struct {
+	status int
+	body   any
+}
+
+func notFound() *apiError {
+	return &apiError{status: http.StatusNotFound, body: map[string]any{"detail": "Not found."}}
+}
+
+func methodNotAllowed(method string) *apiError {
+	return &apiError{status: http.StatusMethodNotAllowed, body: map[string]any{"detail": fmt.Sprintf("Method %q not allowed.", method)}}
+}
+
+func badRequest(body any) *apiError {
+	return &apiError{status: http.StatusBadRequest, body: body}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	status, body, apiErr := s.route(r)
+	if apiErr != nil {
+		status, body = apiErr.status, apiErr.body
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// route dispatches a request by its path and returns the status code and body of the response.
+func (s *Server) route(r *http.Request) (int, any, *apiError) {
+	p, ok := strings.CutPrefix(r.URL.Path, "/api/")
+	if !ok || !strings.HasSuffix(p, "/") {
+		return 0, nil, notFound()
+	}
+	segments := strings.Split(strings.TrimSuffix(p, "/"), "/")
+
+	if r.Method == http.MethodPost && p == "auth/token/" {
+		return s.createToken(r)
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Token ")
+	if _, ok := s.userTokens[token]; !ok && (token == "" || token != s.token) {
+		return 0, nil, &apiError{status: http.StatusUnauthorized, body: map[string]any{"detail": "Invalid token."}}
+	}
+	if r.Method == http.MethodDelete && p == "auth/token/" {
+		return s.revokeToken(token)
+	}
+
+	var payload map[string]any
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			return 0, nil, badRequest(map[string]any{"detail": "JSON parse error - " + err.Error()})
+		}
+	}
+
+	switch segments[0] {
+	case "stacks":
+		return s.routeStacks(r.Method, segments[1:], payload)
+	case "connection-types":
+		return s.routeTyped(r, segments[1:], s.connectionTypes, "connections", s.connections, payload)
+	case "datastream-types":
+		return s.routeTyped(r, segments[1:], s.datastreamTypes, "datastreams", s.datastreams, payload)
+	case "target-types":
+		if len(segments) >= 5 && segments[2] == "targets" && segments[4] == "mappings" {
+			return s.routeMappings(r, segments[1:], payload)
+		}
+		return s.routeTyped(r, segments[1:], s.targetTypes, "targets", s.targets, payload)
+	case "datastreams":
+		if len(segments) == 1 && r.Method == http.MethodGet {
+			return s.list(r, "datastreams", s.datastreams, stackField("datastreams"))
+		}
+		if len(segments) != 2 {
+			return 0, nil, notFound()
+		}
+		if r.Method != http.MethodPatch {
+			return 0, nil, methodNotAllowed(r.Method)
+		}
+		return s.updateSchedules(segments[1], payload)
+	case "targets":
+		if len(segments) != 1 {
+			return 0, nil, notFound()
+		}
+		if r.Method != http.MethodGet {
+			return 0, nil, methodNotAllowed(r.Method)
+		}
+		return s.list(r, "targets", s.targets, stackField("targets"))
+	}
+
+	return 0, nil, notFound()
+}
+
+func (s *Server) createToken(r *http.Request) (int, any, *apiError) {
+	var req adverity.TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return 0, nil, badRequest(map[string]any{"detail": "JSON parse error - " + err.Error()})
+	}
+	if password, ok := s.users[req.Username]; !ok || password != req.Password {
+		return 0, nil, badRequest(map[string]any{"non_field_errors": []string{"Unable to log in with provided credentials."}})
+	}
+
+	// Like Adverity, a user has one token until it is revoked
+	for token, username := range s.userTokens {
+		if username == req.Username {
+			return http.StatusOK, adverity.TokenResponse{Token: token}, nil
+		}
+	}
+	s.issuedTokens++
+	token := fmt.Sprintf("%s-%s-%d", DefaultToken, req.Username, s.issuedTokens)
+	s.userTokens[token] = req.Username
+	return http.StatusOK, adverity.TokenResponse{Token: token}, nil
+}
+
+// revokeToken deletes the token the request is authenticated with.
+func (s *Server) revokeToken(token string) (int, any, *apiError) {
+	if _, ok := s.userTokens[token]; ok {
+		delete(s.userTokens, token)
+	} else {
+		s.token = ""
+	}
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) routeStacks(method string, segments []string, payload map[string]any) (int, any, *apiError) {
+	if len(segments) == 0 {
+		if method != http.MethodPost {
+			return 0, nil, methodNotAllowed(method)
+		}
+		return s.createStack(payload)
+	}
+	if len(segments) != 1 {
+		return 0, nil, notFound()
+	}
+
+	id, o := s.stackBySlug(segments[0])
+	if o == nil {
+		return 0, nil, notFound()
+	}
+
+	switch method {
+	case http.MethodGet:
+		return http.StatusOK, o.fields, nil
+	case http.MethodPatch:
+		if err := s.checkStack(payload, "parent_id"); err != nil {
+			return 0, nil, err
+		}
+		maps.Copy(o.fields, payload)
+		return http.StatusOK, o.fields, nil
+	case http.MethodDelete:
+		delete(s.stacks, id)
+		return http.StatusNoContent, nil, nil
+	}
+
+	return 0, nil, methodNotAllowed(method)
+}
+
+func (s *Server) createStack(payload map[string]any) (int, any, *apiError) {
+	name, _ := payload["name"].(string)
+	if name == "" {
+		return 0, nil, badRequest(map[string]any{"name": []string{"This field is required."}})
+	}
+	if err := s.checkStack(payload, "parent_id"); err != nil {
+		return 0, nil, err
+	}
+
+	s.lastID++
+	fields := map[string]any{"id": s.lastID, "parent_id": RootStackID}
+	maps.Copy(fields, payload)
+	fields["slug"] = s.uniqueSlug(name)
+	s.stacks[s.lastID] = &object{fields: fields}
+
+	return http.StatusCreated, fields, nil
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// uniqueSlug derives the slug of a new stack from its name.
+func (s *Server) uniqueSlug(name string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	for i := 2; ; i++ {
+		if _, o := s.stackBySlug(slug); o == nil {
+			return slug
+		}
+		slug = strings.TrimSuffix(slug, "-"+strconv.Itoa(i-1)) + "-" + strconv.Itoa(i)
+	}
+}
+
+func (s *Server) stackBySlug(slug string) (int64, *object) {
+	for id, o := range s.stacks {
+		if o.fields["slug"] == slug {
+			return id, o
+		}
+	}
+	return 0, nil
+}
+
+// checkStack ensures the stack referenced by a field of the payload exists, if the field is set.
+func (s *Server) checkStack(payload map[string]any, field string) *apiError {
+	return s.checkReference(payload, field, s.stacks, "workspace")
+}
+
+// checkReference ensures the object referenced by a field of the payload exists, if the field is set.
+func (s *Server) checkReference(payload map[string]any, field string, objects map[int64]*object, name string) *apiError {
+	value, ok := payload[field]
+	if !ok {
+		return nil
+	}
+	id, ok := value.(float64)
+	if _, exists := objects[int64(id)]; !ok || !exists {
+		return badRequest(map[string]any{field: []string{fmt.Sprintf("Invalid pk \"%v\" - %s does not exist.", value, name)}})
+	}
+	return nil
+}
+
+// routeTyped serves the objects of a type, e.g. connection-types/<type>/connections/<id>/.
+func (s *Server) routeTyped(r *http.Request, segments []string, typesByID map[int64]Type, plural string, objects map[int64]*object, payload map[string]any) (int, any, *apiError) {
+	if len(segments) == 0 {
+		if r.Method != http.MethodGet {
+			return 0, nil, methodNotAllowed(r.Method)
+		}
+		return http.StatusOK, s.queryTypes(typesByID, plural, r.URL.Query().Get("search")), nil
+	}
+
+	typeID, err := strconv.ParseInt(segments[0], 10, 64)
+	t, ok := typesByID[typeID]
+	if err != nil || !ok || len(segments) < 2 || segments[1] != plural || len(segments) > 3 {
+		return 0, nil, notFound()
+	}
+
+	if len(segments) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			ofType := make(map[int64]*object)
+			for id, o := range objects {
+				if o.parent == typeID {
+					ofType[id] = o
+				}
+			}
+			return s.list(r, plural, ofType, stackField(plural))
+		case http.MethodOptions:
+			return http.StatusOK, adverity.MetadataResponse{Name: t.Name, Actions: map[string]map[string]adverity.FieldMetadata{"POST": t.Fields}}, nil
+		case http.MethodPost:
+			if apiErr := s.validate(t, plural, payload); apiErr != nil {
+				return 0, nil, apiErr
+			}
+			s.lastID++
+			o := &object{parent: typeID, fields: newObject(plural, s.lastID, typeID)}
+			objects[s.lastID] = o
+			s.update(plural, o, payload)
+			if plural == "datastreams" && len(scheduleList(o)) == 0 {
+				o.fields["schedules"] = s.schedules(nil, []any{defaultSchedule()})
+			}
+			return http.StatusCreated, response(plural, o), nil
+		}
+		return 0, nil, methodNotAllowed(r.Method)
+	}
+
+	id, err := strconv.ParseInt(segments[2], 10, 64)
+	o, ok := objects[id]
+	if err != nil || !ok || o.parent != typeID {
+		return 0, nil, notFound()
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, response(plural, o), nil
+	case http.MethodPatch:
+		if apiErr := s.validateReferences(plural, payload); apiErr != nil {
+			return 0, nil, apiErr
+		}
+		s.update(plural, o, payload)
+		return http.StatusOK, response(plural, o), nil
+	case http.MethodDelete:
+		delete(objects, id)
+		if plural == "targets" {
+			for mappingID, m := range s.mappings {
+				if m.parent == id {
+					delete(s.mappings, mappingID)
+				}
+			}
+		}
+		return http.StatusNoContent, nil, nil
+	}
+
+	return 0, nil, methodNotAllowed(r.Method)
+}
+
+// stackField returns the field holding the workspace of the objects, which datastreams name differently.
+func stackField(plural string) string {
+	if plural == "datastreams" {
+		return "stack_id"
+	}
+	return "stack"
+}
+
+// listPageSize is the number of objects per page of a list.
+const listPageSize = 100
+
+// list serves a page of the objects, sorted by ID. If stackField is set, only the objects whose
+// field matches the stack query parameter are listed, like the stack filter of Adverity.
+func (s *Server) list(r *http.Request, plural string, objects map[int64]*object, stackField string) (int, any, *apiError) {
+	query := r.URL.Query()
+	stack, _ := strconv.ParseInt(query.Get("stack"), 10, 64)
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	results := make([]map[string]any, 0)
+	for _, id := range slices.Sorted(maps.Keys(objects)) {
+		o := objects[id]
+		if stackField != "" && query.Has("stack") && fmt.Sprint(o.fields[stackField]) != strconv.FormatInt(stack, 10) {
+			continue
+		}
+		results = append(results, response(plural, o))
+	}
+
+	count := len(results)
+	results = results[min((page-1)*listPageSize, count):min(page*listPageSize, count)]
+	var next any
+	if page*listPageSize < count {
+		query.Set("page", strconv.Itoa(page+1))
+		next = s.URL + r.URL.Path + "?" + query.Encode()
+	}
+
+	return http.StatusOK, map[string]any{"count": count, "next": next, "previous": nil, "results": results}, nil
+}
+
+func (s *Server) queryTypes(typesByID map[int64]Type, plural, search string) map[string]any {
+	results := make([]map[string]any, 0)
+	for _, id := range slices.Sorted(maps.Keys(typesByID)) {
+		t := typesByID[id]
+		if search != "" && !strings.Contains(strings.ToLower(t.Name+" "+t.Slug), strings.ToLower(search)) {
+			continue
+		}
+		base := s.URL + "/api/" + strings.TrimSuffix(plural, "s") + "-types/" + strconv.FormatInt(t.ID, 10) + "/"
+		results = append(results, map[string]any{
+			"id":   t.ID,
+			"name": t.Name,
+			"slug": t.Slug,
+			"url":  base,
+			plural: base + plural + "/",
+		})
+	}
+	return map[string]any{"count": len(results), "next": nil, "previous": nil, "results": results}
+}
+
+// validate checks the required fields of the type and the references of a new object.
+func (s *Server) validate(t Type, plural string, payload map[string]any) *apiError {
+	missing := make(map[string]any)
+	for name, field := range t.Fields {
+		if _, ok := payload[name]; field.Required && !field.ReadOnly && !ok {
+			missing[name] = []string{"This field is required."}
+		}
+	}
+	if len(missing) > 0 {
+		return badRequest(missing)
+	}
+	return s.validateReferences(plural, payload)
+}
+
+// validateReferences ensures the workspace, authorization and datastream referenced by a payload exist.
+func (s *Server) validateReferences(plural string, payload map[string]any) *apiError {
+	if err := s.checkStack(payload, "stack"); err != nil {
+		return err
+	}
+	if plural != "connections" {
+		if err := s.checkReference(payload, "auth", s.connections, "authorization"); err != nil {
+			return err
+		}
+	}
+	return s.checkReference(payload, "datastream", s.datastreams, "datastream")
+}
+
+// newObject returns the fields of a new object with the defaults set by Adverity.
+func newObject(plural string, id, typeID int64) map[string]any {
+	switch plural {
+	case "connections":
+		return map[string]any{"id": id, "app": typeID, "is_authorized": false, "metadata_slack": 0}
+	case "datastreams":
+		return map[string]any{
+			"id":                    id,
+			"datastream_type_id":    typeID,
+			"slug":                  "datastream-" + strconv.FormatInt(id, 10),
+			"description":           "",
+			"datatype":              "Live",
+			"enabled":               true,
+			"retention_type":        0,
+			"retention_number":      0,
+			"overwrite_key_columns": false,
+			"overwrite_datastream":  false,
+			"overwrite_filename":    false,
+			"is_insights_mediaplan": false,
+			"manage_extract_names":  false,
+			"extract_name_keys":     "",
+			"schedules":             []map[string]any{},
+		}
+	case "targets":
+		return map[string]any{
+			"id":                        id,
+			"schema_mapping":            false,
+			"force_string":              false,
+			"format_headers":            false,
+			"column_names_to_lowercase": false,
+			"headers_formatting":        0,
+		}
+	}
+	return map[string]any{"id": id}
+}
+
+// update applies the payload of a create or update request to an object.
+func (s *Server) update(plural string, o *object, payload map[string]any) {
+	for key, value := range payload {
+		switch {
+		case plural == "datastreams" && key == "stack":
+			o.fields["stack_id"] = value
+		case plural == "datastreams" && key == "schedules":
+			list, _ := value.([]any)
+			o.fields["schedules"] = s.schedules(scheduleList(o), list)
+		default:
+			o.fields[key] = value
+		}
+	}
+}
+
+// response returns the representation of an object.
+func response(plural string, o *object) map[string]any {
+	if plural != "datastreams" {
+		return o.fields
+	}
+
+	// Adverity does not return the schedules in a stable order, so return the newest first
+	fields := maps.Clone(o.fields)
+	schedules := slices.Clone(scheduleList(o))
+	slices.Reverse(schedules)
+	fields["schedules"] = schedules
+
+	return fields
+}
+
+// scheduleList returns the schedules of a datastream.
+func scheduleList(o *object) []map[string]any {
+	schedules, _ := o.fields["schedules"].([]map[string]any)
+	return schedules
+}
+
+// defaultSchedule is the schedule Adverity adds to datastreams created without schedules.
+func defaultSchedule() any {
+	return map[string]any{"cron_preset": "CRON_EVERY_DAY", "time_range_preset": float64(2)}
+}
+
+// schedules replaces the schedules of a datastream. Schedules with the ID of an existing
+// schedule keep it, all other schedules get a new ID. The fields Adverity derives from the
+// cron preset and time range preset are set.
+func (s *Server) schedules(existing []map[string]any, list []any) []map[string]any {
+	schedules := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		schedule := maps.Clone(fields)
+
+		id, _ := schedule["id"].(float64)
+		if !slices.ContainsFunc(existing, func(e map[string]any) bool { return e["id"] == int64(id) }) {
+			s.lastID++
+			id = float64(s.lastID)
+		}
+		schedule["id"] = int64(id)
+
+		if preset, ok := adverity.CronPresets[fmt.Sprint(schedule["cron_preset"])]; ok {
+			schedule["cron_type"] = preset.Type
+			schedule["cron_interval"] = preset.Interval
+		}
+
+		schedules = append(schedules, schedule)
+	}
+	return schedules
+}
+
+// updateSchedules serves datastreams/<id>/, which updates the schedules and the enabled flag of a datastream.
+func (s *Server) updateSchedules(segment string, payload map[string]any) (int, any, *apiError) {
+	id, err := strconv.ParseInt(segment, 10, 64)
+	o, ok := s.datastreams[id]
+	if err != nil || !ok {
+		return 0, nil, notFound()
+	}
+
+	if list, ok := payload["schedules"].([]any); ok {
+		o.fields["schedules"] = s.schedules(scheduleList(o), list)
+	}
+	if enabled, ok := payload["enabled"].(bool); ok {
+		o.fields["enabled"] = enabled
+	}
+
+	// Like Adverity, the endpoint does not return all fields of the datastream
+	fields := response("datastreams", o)
+	delete(fields, "stack_id")
+
+	return http.StatusOK, fields, nil
+}
+
+// routeMappings serves target-types/<type>/targets/<target>/mappings/<id>/.
+func (s *Server) routeMappings(r *http.Request, segments []string, payload map[string]any) (int, any, *apiError) {
+	typeID, typeErr := strconv.ParseInt(segments[0], 10, 64)
+	targetID, targetErr := strconv.ParseInt(segments[2], 10, 64)
+	target, ok := s.targets[targetID]
+	if typeErr != nil || targetErr != nil || !ok || target.parent != typeID || len(segments) > 5 {
+		return 0, nil, notFound()
+	}
+
+	if len(segments) == 4 {
+		switch r.Method {
+		case http.MethodGet:
+			mappings := make(map[int64]*object)
+			for id, o := range s.mappings {
+				if o.parent == targetID {
+					mappings[id] = o
+				}
+			}
+			return s.list(r, "mappings", mappings, "")
+		case http.MethodOptions:
+			fields := map[string]adverity.FieldMetadata{
+				"id":         {Type: "integer", ReadOnly: true, Label: "ID"},
+				"datastream": {Type: "field", Required: true, Label: "Datastream"},
+				"enabled":    {Type: "boolean", Label: "Enabled"},
+				"table_name": {Type: "string", Label: "Table name"},
+			}
+			return http.StatusOK, adverity.MetadataResponse{Name: "Mapping List", Actions: map[string]map[string]adverity.FieldMetadata{"POST": fields}}, nil
+		case http.MethodPost:
+			if _, ok := payload["datastream"]; !ok {
+				return 0, nil, badRequest(map[string]any{"datastream": []string{"This field is required."}})
+			}
+			if err := s.checkReference(payload, "datastream", s.datastreams, "datastream"); err != nil {
+				return 0, nil, err
+			}
+			s.lastID++
+			fields := map[string]any{"id": s.lastID, "target": targetID, "enabled": true, "table_name": ""}
+			maps.Copy(fields, payload)
+			s.mappings[s.lastID] = &object{parent: targetID, fields: fields}
+			return http.StatusCreated, fields, nil
+		}
+		return 0, nil, methodNotAllowed(r.Method)
+	}
+
+	id, err := strconv.ParseInt(segments[4], 10, 64)
+	o, ok := s.mappings[id]
+	if err != nil || !ok || o.parent != targetID {
+		return 0, nil, notFound()
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, o.fields, nil
+	case http.MethodPatch:
+		if err := s.checkReference(payload, "datastream", s.datastreams, "datastream"); err != nil {
+			return 0, nil, err
+		}
+		maps.Copy(o.fields, payload)
+		return http.StatusOK, o.fields, nil
+	case http.MethodDelete:
+		delete(s.mappings, id)
+		return http.StatusNoContent, nil, nil
+	}
+
+	return 0, nil, methodNotAllowed(r.Method)
+}
@@ -0,0 +1,277 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeserver
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+func newTestServer(t *testing.T) (*Server, *adverity.Client) {
+	t.Helper()
+	s := New()
+	t.Cleanup(s.Close)
+	s.AddConnectionType(Type{ID: 10, Name: "Google Ads", Slug: "google-ads"})
+	s.AddDatastreamType(Type{ID: 20, Name: "Google Ads Insights", Slug: "google-ads-insights"})
+	s.AddTargetType(Type{ID: 30, Name: "Google BigQuery", Slug: "bigquery", Fields: map[string]adverity.FieldMetadata{
+		"name":    {Type: "string", Required: true},
+		"project": {Type: "string", Required: true},
+	}})
+
+	c, err := adverity.NewClient(t.Context(), s.URL, adverity.WithToken(DefaultToken), adverity.WithCACertPEM([]byte(s.CertificatePEM())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+func TestWorkspaceLifecycle(t *testing.T) {
+	s, c := newTestServer(t)
+
+	name := "Marketing EMEA"
+	parent := RootStackID
+	created, err := c.CreateWorkspace(t.Context(), &adverity.WorkspaceConfig{Name: &name, ParentID: &parent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Slug != "marketing-emea" || created.ParentID != RootStackID {
+		t.Errorf("CreateWorkspace() = %+v, want slug marketing-emea and the root as parent", created)
+	}
+
+	// A second workspace with the same name gets a unique slug
+	duplicate, err := c.CreateWorkspace(t.Context(), &adverity.WorkspaceConfig{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duplicate.Slug != "marketing-emea-2" {
+		t.Errorf("slug = %q, want %q", duplicate.Slug, "marketing-emea-2")
+	}
+
+	renamed := "Marketing"
+	updated, err := c.UpdateWorkspace(t.Context(), created.Slug, &adverity.WorkspaceConfig{Name: &renamed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != renamed || updated.Slug != created.Slug {
+		t.Errorf("UpdateWorkspace() = %+v, want the new name and the same slug", updated)
+	}
+
+	if _, err := c.DeleteWorkspace(t.Context(), created.Slug); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadWorkspace(t.Context(), created.Slug); err == nil || !strings.Contains(err.Error(), "status: 404") {
+		t.Errorf("ReadWorkspace() error = %v, want status 404", err)
+	}
+	if s.Exists("stack", created.ID) {
+		t.Error("deleted workspace still exists")
+	}
+}
+
+func TestDatastreamSchedules(t *testing.T) {
+	_, c := newTestServer(t)
+
+	name := "Campaigns"
+	stack := RootStackID
+	datastream, err := c.CreateDatastream(t.Context(), 20, &adverity.DatastreamCreateConfig{Name: &name, StackID: &stack})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if datastream.StackID != RootStackID || datastream.DatastreamTypeID != 20 || !datastream.Enabled {
+		t.Errorf("CreateDatastream() = %+v, want the root stack, type 20 and enabled", datastream)
+	}
+
+	// Adverity adds a default schedule with the cron fields derived from its preset
+	if len(datastream.Schedules) != 1 {
+		t.Fatalf("schedules = %+v, want the default schedule", datastream.Schedules)
+	}
+	if s := datastream.Schedules[0]; s.ID == nil || s.CronType == nil || *s.CronType != adverity.CronTypeDay {
+		t.Errorf("default schedule = %+v, want the derived cron fields", s)
+	}
+
+	// Existing schedules keep their ID and the schedules are returned newest first
+	defaultID := *datastream.Schedules[0].ID
+	hourly := "CRON_EVERY_HOUR"
+	schedules := []adverity.Schedule{{ID: &defaultID, CronPreset: datastream.Schedules[0].CronPreset}, {CronPreset: &hourly}}
+	datastream, err = c.UpdateDatastreamSchedule(t.Context(), int(datastream.ID), &adverity.DatastreamScheduleConfig{Schedules: &schedules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datastream.Schedules) != 2 || *datastream.Schedules[1].ID != defaultID || *datastream.Schedules[0].CronInterval != 1 {
+		t.Errorf("schedules = %+v, want the new hourly schedule before the default schedule", datastream.Schedules)
+	}
+
+	// Datastreams are only found with their type
+	if _, err := c.ReadDatastream(t.Context(), 21, int(datastream.ID)); err == nil || !strings.Contains(err.Error(), "status: 404") {
+		t.Errorf("ReadDatastream() with another type error = %v, want status 404", err)
+	}
+}
+
+func TestDestinationMappings(t *testing.T) {
+	s, c := newTestServer(t)
+
+	// Required fields of the type are enforced
+	name := "Warehouse"
+	stack := RootStackID
+	if _, err := c.CreateDestination(t.Context(), 30, &adverity.DestinationConfig{Name: &name, StackID: &stack}); err == nil || !strings.Contains(err.Error(), "project") {
+		t.Errorf("CreateDestination() without project error = %v, want the missing field", err)
+	}
+
+	params := []adverity.Parameter{{Key: "project", Value: "analytics"}}
+	destination, err := c.CreateDestination(t.Context(), 30, &adverity.DestinationConfig{Name: &name, StackID: &stack, Parameters: &params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destination.Project != "analytics" {
+		t.Errorf("project = %q, want %q", destination.Project, "analytics")
+	}
+
+	dsName := "Campaigns"
+	datastream, err := c.CreateDatastream(t.Context(), 20, &adverity.DatastreamCreateConfig{Name: &dsName, StackID: &stack})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := "campaigns"
+	mapping, err := c.CreateDestinationMapping(t.Context(), 30, int(destination.ID), &adverity.DestinationMappingConfig{DatastreamId: &datastream.ID, TableName: &table})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.DestinationID != destination.ID || !mapping.Enabled || mapping.TableName != table {
+		t.Errorf("CreateDestinationMapping() = %+v, want an enabled mapping to the table", mapping)
+	}
+
+	// Changes outside of the API are returned by the next read
+	if err := s.Modify("mapping", mapping.ID, map[string]any{"enabled": false}); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err = c.ReadDestinationMapping(t.Context(), 30, int(destination.ID), int(mapping.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Enabled {
+		t.Error("mapping is enabled, want the modification")
+	}
+
+	// Deleting the destination deletes its mappings
+	if _, err := c.DeleteDestination(t.Context(), 30, int(destination.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if s.Exists("mapping", mapping.ID) {
+		t.Error("mapping of the deleted destination still exists")
+	}
+}
+
+func TestLists(t *testing.T) {
+	_, c := newTestServer(t)
+
+	workspace := "Sales"
+	other, err := c.CreateWorkspace(t.Context(), &adverity.WorkspaceConfig{Name: &workspace})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// More datastreams than fit on a page are listed with several requests
+	name := "Campaigns"
+	root := RootStackID
+	for range listPageSize + 5 {
+		if _, err := c.CreateDatastream(t.Context(), 20, &adverity.DatastreamCreateConfig{Name: &name, StackID: &root}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.CreateDatastream(t.Context(), 20, &adverity.DatastreamCreateConfig{Name: &name, StackID: &other.ID}); err != nil {
+		t.Fatal(err)
+	}
+	datastreams, err := c.ListDatastreams(t.Context(), int(RootStackID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datastreams) != listPageSize+5 {
+		t.Errorf("ListDatastreams() returned %d datastreams, want %d", len(datastreams), listPageSize+5)
+	}
+	if ds := datastreams[0]; ds.StackID != RootStackID || ds.DatastreamTypeID != 20 || len(ds.Schedules) != 1 {
+		t.Errorf("ListDatastreams()[0] = %+v, want the datastream with its default schedule", ds)
+	}
+
+	params := []adverity.Parameter{{Key: "project", Value: "analytics"}}
+	destination, err := c.CreateDestination(t.Context(), 30, &adverity.DestinationConfig{Name: &name, StackID: &other.ID, Parameters: &params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	destinations, err := c.ListDestinations(t.Context(), int(other.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destinations) != 1 || destinations[0].ID != destination.ID {
+		t.Errorf("ListDestinations() = %+v, want the destination of the workspace", destinations)
+	}
+
+	for _, ds := range datastreams[:2] {
+		if _, err := c.CreateDestinationMapping(t.Context(), 30, int(destination.ID), &adverity.DestinationMappingConfig{DatastreamId: &ds.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mappings, err := c.ListDestinationMappings(t.Context(), 30, int(destination.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) != 2 || mappings[1].DatastreamID != datastreams[1].ID {
+		t.Errorf("ListDestinationMappings() = %+v, want the mappings of both datastreams", mappings)
+	}
+}
+
+func TestTypesAndTokens(t *testing.T) {
+	s, c := newTestServer(t)
+
+	results, err := c.QueryDatastreamTypes(t.Context(), "google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Slug != "google-ads-insights" {
+		t.Errorf("QueryDatastreamTypes() = %+v, want the Google Ads Insights type", results)
+	}
+
+	fields, err := c.ReadDestinationFields(t.Context(), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fields["project"].Required {
+		t.Errorf("fields = %+v, want the required project field", fields)
+	}
+
+	s.AddUser("jane", "secret")
+	token, err := c.CreateToken(t.Context(), "jane", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token == DefaultToken {
+		t.Errorf("token = %q, want a token of the user", token.Token)
+	}
+	if again, err := c.CreateToken(t.Context(), "jane", "secret"); err != nil || again.Token != token.Token {
+		t.Errorf("CreateToken() again = %v, %v, want the same token %q", again, err, token.Token)
+	}
+	if _, err := c.CreateToken(t.Context(), "jane", "wrong"); err == nil {
+		t.Error("CreateToken() with a wrong password succeeded, want an error")
+	}
+
+	// A revoked token is rejected, and the user obtains a new one
+	if err := c.RevokeToken(t.Context(), token.Token); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RevokeToken(t.Context(), token.Token); err == nil || !strings.Contains(err.Error(), "status: 401") {
+		t.Errorf("RevokeToken() of a revoked token error = %v, want status 401", err)
+	}
+	if renewed, err := c.CreateToken(t.Context(), "jane", "secret"); err != nil || renewed.Token == token.Token {
+		t.Errorf("CreateToken() after revoking = %v, %v, want a new token", renewed, err)
+	}
+	if _, err := c.QueryDatastreamTypes(t.Context(), ""); err != nil {
+		t.Errorf("QueryDatastreamTypes() with the token of the server error = %v", err)
+	}
+
+	s.SetToken("rotated")
+	if _, err := c.QueryDatastreamTypes(t.Context(), ""); err == nil || !strings.Contains(err.Error(), "status: 401") {
+		t.Errorf("QueryDatastreamTypes() with an old token error = %v, want status 401", err)
+	}
+}
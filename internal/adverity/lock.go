@@ -0,0 +1,62 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"fmt"
+	"sync"
+)
+
+// keyedMutex is a mutex per key. Locks of unused keys are removed, so the keys do not accumulate.
+// The zero value is an unlocked keyedMutex.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu sync.Mutex
+	// refs counts the goroutines holding or waiting for the lock
+	refs int
+}
+
+// lock locks the key and returns the function unlocking it again.
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		k.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// LockDatastream serializes operations on a datastream which need more than one request, e.g. updating
+// the schedules and the datastream, across goroutines. It returns the function unlocking the datastream.
+// Operations locking a datastream and a destination lock the datastream first.
+func (c *Client) LockDatastream(datastreamId int) func() {
+	return c.objectLocks.lock(fmt.Sprintf("datastream/%d", datastreamId))
+}
+
+// LockDestination serializes operations on a destination and its mappings across goroutines.
+// It returns the function unlocking the destination.
+func (c *Client) LockDestination(destinationId int) func() {
+	return c.objectLocks.lock(fmt.Sprintf("destination/%d", destinationId))
+}
@@ -0,0 +1,103 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLockDatastream adds schedules to a datastream from parallel goroutines, each reading the schedules
+// and sending them with the new one. Without the lock, schedules of concurrent updates are lost.
+// Run with -race to detect unsynchronized access as well.
+func TestLockDatastream(t *testing.T) {
+	var mu sync.Mutex
+	schedules := []Schedule{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if r.Method == http.MethodPatch {
+			var payload DatastreamScheduleConfig
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Schedules == nil {
+				mu.Unlock()
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			schedules = *payload.Schedules
+		}
+		body, _ := json.Marshal(DatastreamResponse{ID: 8, Schedules: schedules})
+		mu.Unlock()
+
+		// Widen the window between reading and updating the schedules
+		time.Sleep(time.Millisecond)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(t.Context(), server.URL, WithToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const updates = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, updates)
+	for range updates {
+		wg.Go(func() {
+			unlock := c.LockDatastream(8)
+			defer unlock()
+
+			datastream, err := c.ReadDatastream(t.Context(), 20, 8)
+			if err != nil {
+				errs <- err
+				return
+			}
+			preset := "CRON_EVERY_DAY"
+			updated := append(datastream.Schedules, Schedule{CronPreset: &preset})
+			if _, err := c.UpdateDatastreamSchedule(t.Context(), 8, &DatastreamScheduleConfig{Schedules: &updated}); err != nil {
+				errs <- err
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(schedules) != updates {
+		t.Errorf("datastream has %d schedules, want %d", len(schedules), updates)
+	}
+	if len(c.objectLocks.locks) != 0 {
+		t.Errorf("%d locks are left after unlocking", len(c.objectLocks.locks))
+	}
+}
+
+func TestLockIndependentObjects(t *testing.T) {
+	c, err := NewClient(t.Context(), "https://example.invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unlock := c.LockDatastream(8)
+	defer unlock()
+
+	// Other datastreams and destinations with the same id are not blocked
+	done := make(chan struct{})
+	go func() {
+		c.LockDatastream(9)()
+		c.LockDestination(8)()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("locking other objects is blocked by datastream 8")
+	}
+}
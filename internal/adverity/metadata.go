@@ -0,0 +1,89 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// FieldMetadata describes a field accepted by an endpoint as returned by an OPTIONS request.
+type FieldMetadata struct {
+	Type      string                   `json:"type"`
+	Required  bool                     `json:"required"`
+	ReadOnly  bool                     `json:"read_only"`
+	Label     string                   `json:"label"`
+	HelpText  string                   `json:"help_text"`
+	MinValue  *float64                 `json:"min_value"`
+	MaxValue  *float64                 `json:"max_value"`
+	MaxLength *int64                   `json:"max_length"`
+	Choices   []FieldChoice            `json:"choices"`
+	Child     *FieldMetadata           `json:"child"`
+	Children  map[string]FieldMetadata `json:"children"`
+}
+
+// FieldChoice is a value accepted by a choice field.
+type FieldChoice struct {
+	Value       interface{} `json:"value"`
+	DisplayName string      `json:"display_name"`
+}
+
+// MetadataResponse is the response of an OPTIONS request.
+type MetadataResponse struct {
+	Name    string                              `json:"name"`
+	Actions map[string]map[string]FieldMetadata `json:"actions"`
+}
+
+// readFields returns the fields accepted when creating an object at the given path.
+// They only depend on the type of the object, so they are cached for the lifetime of the client.
+func (c *Client) readFields(ctx context.Context, path *url.URL) (map[string]FieldMetadata, error) {
+	c.metadataMu.Lock()
+	defer c.metadataMu.Unlock()
+
+	if fields, ok := c.metadata[path.String()]; ok {
+		return fields, nil
+	}
+
+	resp, err := Options[MetadataResponse](ctx, c, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]FieldMetadata
+	if resp != nil {
+		fields = resp.Actions["POST"]
+	}
+	c.metadata[path.String()] = fields
+
+	return fields, nil
+}
+
+func (c *Client) ReadAuthorizationFields(ctx context.Context, connectionTypeId int) (map[string]FieldMetadata, error) {
+	r, _ := url.JoinPath("connection-types", strconv.Itoa(connectionTypeId), "connections", "/")
+	p, _ := url.Parse(r)
+
+	return c.readFields(ctx, p)
+}
+
+func (c *Client) ReadDatastreamFields(ctx context.Context, datastreamTypeId int) (map[string]FieldMetadata, error) {
+	r, _ := url.JoinPath("datastream-types", strconv.Itoa(datastreamTypeId), "datastreams", "/")
+	p, _ := url.Parse(r)
+
+	return c.readFields(ctx, p)
+}
+
+func (c *Client) ReadDestinationFields(ctx context.Context, destinationTypeId int) (map[string]FieldMetadata, error) {
+	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", "/")
+	p, _ := url.Parse(r)
+
+	return c.readFields(ctx, p)
+}
+
+func (c *Client) ReadDestinationMappingFields(ctx context.Context, destinationTypeId, destinationId int) (map[string]FieldMetadata, error) {
+	r, _ := url.JoinPath("target-types", strconv.Itoa(destinationTypeId), "targets", strconv.Itoa(destinationId), "mappings", "/")
+	p, _ := url.Parse(r)
+
+	return c.readFields(ctx, p)
+}
@@ -0,0 +1,123 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenCommandTimeout limits the runtime of a token command, e.g. waiting for an interactive unlock.
+const tokenCommandTimeout = 2 * time.Minute
+
+type TokenRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type TokenResponse struct {
+	Token string `json:"token"`
+}
+
+// CreateToken exchanges a username and password for an API token. The request is sent without the
+// token of the client, and neither the credentials nor the token are logged.
+func (c *Client) CreateToken(ctx context.Context, username, password string) (*TokenResponse, error) {
+	p, _ := url.Parse("auth/token/")
+
+	payload, err := json.Marshal(TokenRequest{Username: username, Password: password})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %T: %w", TokenRequest{}, err)
+	}
+
+	noToken := ""
+	body, err := c.doRequest(ctx, http.MethodPost, p, bytes.NewReader(payload), nil, &noToken)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(TokenResponse)
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal into %T: %w", *resp, err)
+	}
+	if resp.Token == "" {
+		return nil, fmt.Errorf("the response contains no token")
+	}
+
+	return resp, nil
+}
+
+// RevokeToken deletes an API token obtained with CreateToken, so it is rejected by subsequent requests. The
+// request is authenticated with the token itself. Adverity issues one token per user, so this also revokes
+// the token for other uses of the same user, and the next CreateToken issues a new one. The DELETE request is
+// not part of the documented API of Adverity.
+func (c *Client) RevokeToken(ctx context.Context, token string) error {
+	p, _ := url.Parse("auth/token/")
+
+	_, err := c.doRequest(ctx, http.MethodDelete, p, nil, nil, &token)
+	return err
+}
+
+// WithTokenFile authenticates the requests with an API token read from a file.
+func WithTokenFile(name string) ClientOption {
+	return func(c *Client) error {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read the API token file: %w", err)
+		}
+		token := strings.TrimSpace(string(b))
+		if token == "" {
+			return fmt.Errorf("the API token file %s is empty", name)
+		}
+		c.token = token
+		return nil
+	}
+}
+
+// WithTokenCommand authenticates the requests with an API token printed by a command (e.g. a Vault or
+// 1Password CLI). The command is run again to refresh the token when a request is rejected as unauthorized.
+func WithTokenCommand(args []string) ClientOption {
+	return func(c *Client) error {
+		if len(args) == 0 || args[0] == "" {
+			return fmt.Errorf("the API token command is empty")
+		}
+		c.refreshToken = func(ctx context.Context) (string, error) {
+			return runTokenCommand(ctx, args)
+		}
+		return nil
+	}
+}
+
+// runTokenCommand runs a command and returns its trimmed output as token.
+func runTokenCommand(ctx context.Context, args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+
+	tflog.Debug(ctx, "Running the API token command", map[string]any{"command": args[0]})
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("the API token command %s failed: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("the API token command %s failed: %w", args[0], err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("the API token command %s printed no token", args[0])
+	}
+
+	return token, nil
+}
@@ -0,0 +1,125 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoRequestRefreshesRejectedToken(t *testing.T) {
+	var payloads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, string(b))
+		if r.Header.Get("Authorization") != "Token fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(t.Context(), server.URL, WithToken("expired"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed := 0
+	c.refreshToken = func(context.Context) (string, error) {
+		refreshed++
+		return "fresh", nil
+	}
+
+	p, _ := url.Parse("workspaces/")
+	body, err := c.Create(t.Context(), p, strings.NewReader(`{"name":"example"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"id":1}` {
+		t.Errorf("body = %s, want %s", body, `{"id":1}`)
+	}
+	if refreshed != 1 {
+		t.Errorf("refreshed %d times, want 1", refreshed)
+	}
+	if len(payloads) != 2 || payloads[0] != payloads[1] || payloads[1] != `{"name":"example"}` {
+		t.Errorf("payloads = %q, want the payload sent twice", payloads)
+	}
+
+	// The refreshed token is used by subsequent requests
+	if _, err := c.Read(t.Context(), p, nil); err != nil {
+		t.Fatal(err)
+	}
+	if refreshed != 1 {
+		t.Errorf("refreshed %d times, want 1", refreshed)
+	}
+}
+
+func TestDoRequestWithoutRefresh(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(t.Context(), server.URL, WithToken("expired"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := url.Parse("workspaces/")
+	if _, err := c.Read(t.Context(), p, nil); err == nil || !strings.Contains(err.Error(), "status: 401") {
+		t.Errorf("Read() error = %v, want status 401", err)
+	}
+	if requests != 1 {
+		t.Errorf("sent %d requests, want 1", requests)
+	}
+}
+
+func TestWithTokenFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(name, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClient(t.Context(), "https://example.datatap.adverity.com", WithTokenFile(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.token != "secret" {
+		t.Errorf("token = %q, want %q", c.token, "secret")
+	}
+
+	if err := os.WriteFile(name, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(t.Context(), "https://example.datatap.adverity.com", WithTokenFile(name)); err == nil {
+		t.Error("NewClient() with an empty token file succeeded, want an error")
+	}
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	token, err := runTokenCommand(t.Context(), []string{"sh", "-c", "echo ' secret '"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "secret" {
+		t.Errorf("token = %q, want %q", token, "secret")
+	}
+
+	if _, err := runTokenCommand(t.Context(), []string{"sh", "-c", "echo denied >&2; exit 1"}); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("runTokenCommand() error = %v, want the output of the failed command", err)
+	}
+
+	if _, err := runTokenCommand(t.Context(), []string{"true"}); err == nil {
+		t.Error("runTokenCommand() without output succeeded, want an error")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// WithRoundTripper wraps the transport of the client, e.g. to record or replay the requests with a
+// Cassette. The wrapped transport keeps the proxy and TLS settings of the client.
+func WithRoundTripper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) error {
+		c.httpClient.Transport = wrap(c.httpClient.Transport)
+		return nil
+	}
+}
+
+// WithProxy sends the requests through an HTTP(S) proxy instead of the proxy from the environment.
+func WithProxy(proxyUrl string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyUrl)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q, expected e.g. http://proxy.example.com:3128", proxyUrl)
+		}
+		c.transport.Proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithCACertPEM trusts the PEM encoded CA certificates in addition to the system certificates,
+// e.g. the CA of a TLS inspecting proxy.
+func WithCACertPEM(pem []byte) ClientOption {
+	return func(c *Client) error {
+		// Without system certificates (e.g. in minimal containers) only the configured CA certificates are trusted
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("the CA certificates contain no PEM encoded certificate")
+		}
+		c.transport.TLSClientConfig.RootCAs = pool
+		return nil
+	}
+}
+
+// WithCACertFile trusts the CA certificates in a PEM file in addition to the system certificates.
+func WithCACertFile(name string) ClientOption {
+	return func(c *Client) error {
+		pem, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read the CA certificate file: %w", err)
+		}
+		return WithCACertPEM(pem)(c)
+	}
+}
+
+// WithClientCertificate authenticates the TLS connections with a PEM encoded client certificate and key (mTLS).
+func WithClientCertificate(certPEM, keyPEM []byte) ClientOption {
+	return func(c *Client) error {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		c.transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificate. This makes the connections
+// vulnerable to man-in-the-middle attacks and should only be used for testing.
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *Client) error {
+		c.transport.TLSClientConfig.InsecureSkipVerify = true
+		return nil
+	}
+}
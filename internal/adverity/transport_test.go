@@ -0,0 +1,186 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package adverity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTLSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serverCertPEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// newClientCertificate creates a self-signed client certificate and returns it and its key PEM encoded.
+func newClientCertificate(t *testing.T) ([]byte, []byte, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		cert
+}
+
+func readRoot(t *testing.T, c *Client) error {
+	t.Helper()
+	p, _ := url.Parse("workspaces/")
+	_, err := c.Read(t.Context(), p, nil)
+	return err
+}
+
+func TestClientTLS(t *testing.T) {
+	server := newTLSServer(t)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, serverCertPEM(server), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		opts    []ClientOption
+		wantErr string
+	}{
+		"untrusted certificate": {
+			wantErr: "certificate",
+		},
+		"CA certificate PEM": {
+			opts: []ClientOption{WithCACertPEM(serverCertPEM(server))},
+		},
+		"CA certificate file": {
+			opts: []ClientOption{WithCACertFile(caFile)},
+		},
+		"insecure skip verify": {
+			opts: []ClientOption{WithInsecureSkipVerify()},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(t.Context(), server.URL, append(test.opts, WithToken("token"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = readRoot(t, c)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("Read() error = %v, want none", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("Read() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM, cert := newClientCertificate(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// Without a client certificate the handshake fails
+	c, err := NewClient(t.Context(), server.URL, WithCACertPEM(serverCertPEM(server)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := readRoot(t, c); err == nil {
+		t.Error("Read() without client certificate succeeded, want an error")
+	}
+
+	c, err = NewClient(t.Context(), server.URL, WithCACertPEM(serverCertPEM(server)), WithClientCertificate(certPEM, keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := readRoot(t, c); err != nil {
+		t.Errorf("Read() with client certificate error = %v, want none", err)
+	}
+}
+
+func TestClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	c, err := NewClient(t.Context(), "http://example.invalid", WithProxy(proxy.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := readRoot(t, c); err != nil {
+		t.Fatal(err)
+	}
+	if want := "http://example.invalid/api/workspaces/"; proxied != want {
+		t.Errorf("proxied request = %q, want %q", proxied, want)
+	}
+}
+
+func TestClientTransportOptionErrors(t *testing.T) {
+	tests := map[string]ClientOption{
+		"proxy without scheme":   WithProxy("proxy.example.com"),
+		"CA without certificate": WithCACertPEM([]byte("not a certificate")),
+		"missing CA file":        WithCACertFile(filepath.Join(t.TempDir(), "missing.pem")),
+		"invalid client cert":    WithClientCertificate([]byte("cert"), []byte("key")),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewClient(t.Context(), "https://example.datatap.adverity.com", opt); err == nil {
+				t.Error("NewClient() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package adverity
 
 import (
+	"context"
 	"net/url"
 )
 
@@ -47,30 +48,30 @@ type WorkspaceResponse struct {
 	Created            string `json:"created"`
 }
 
-func (c *Client) CreateWorkspace(req *WorkspaceConfig) (*WorkspaceResponse, error) {
+func (c *Client) CreateWorkspace(ctx context.Context, req *WorkspaceConfig) (*WorkspaceResponse, error) {
 	r, _ := url.JoinPath("stacks", "/")
 	p, _ := url.Parse(r)
 
-	return Create[WorkspaceConfig, WorkspaceResponse](c, p, req, nil)
+	return Create[WorkspaceConfig, WorkspaceResponse](ctx, c, p, req, nil)
 }
 
-func (c *Client) ReadWorkspace(stackSlug string) (*WorkspaceResponse, error) {
+func (c *Client) ReadWorkspace(ctx context.Context, stackSlug string) (*WorkspaceResponse, error) {
 	r, _ := url.JoinPath("stacks", stackSlug, "/")
 	p, _ := url.Parse(r)
 
-	return Read[WorkspaceResponse](c, p, nil)
+	return Read[WorkspaceResponse](ctx, c, p, nil)
 }
 
-func (c *Client) UpdateWorkspace(stackSlug string, req *WorkspaceConfig) (*WorkspaceResponse, error) {
+func (c *Client) UpdateWorkspace(ctx context.Context, stackSlug string, req *WorkspaceConfig) (*WorkspaceResponse, error) {
 	r, _ := url.JoinPath("stacks", stackSlug, "/")
 	p, _ := url.Parse(r)
 
-	return Update[WorkspaceConfig, WorkspaceResponse](c, p, req, nil)
+	return Update[WorkspaceConfig, WorkspaceResponse](ctx, c, p, req, nil)
 }
 
-func (c *Client) DeleteWorkspace(stackSlug string) (*WorkspaceResponse, error) {
+func (c *Client) DeleteWorkspace(ctx context.Context, stackSlug string) (*WorkspaceResponse, error) {
 	r, _ := url.JoinPath("stacks", stackSlug, "/")
 	p, _ := url.Parse(r)
 
-	return Delete[WorkspaceResponse](c, p, nil)
+	return Delete[WorkspaceResponse](ctx, c, p, nil)
 }
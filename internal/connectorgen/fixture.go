@@ -0,0 +1,336 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+const (
+	kindDestination = "destination"
+	kindDatastream  = "datastream"
+)
+
+// fixture is a recorded OPTIONS response together with the connector it was recorded for.
+type fixture struct {
+	// Resource is the resource type name without the provider prefix, e.g. bigquery_destination.
+	Resource string `json:"resource"`
+	// Kind is either destination or datastream.
+	Kind string `json:"kind"`
+	// TypeID is the destination or datastream type id the response was recorded for.
+	TypeID int64 `json:"type_id"`
+	// Title is the display name of the connector.
+	Title string `json:"title"`
+	// Rename maps parameter keys to attribute names, for keys which are reserved by Terraform
+	// or clash with a common attribute of the resource.
+	Rename map[string]string `json:"rename"`
+	// Options is the recorded OPTIONS response.
+	Options adverity.MetadataResponse `json:"options"`
+}
+
+// connector is a fixture prepared for code generation.
+type connector struct {
+	File       string
+	Resource   string
+	Kind       string
+	TypeID     int64
+	Title      string
+	Attributes []attribute
+}
+
+// HasSensitive reports whether any attribute of the connector is sensitive.
+func (c connector) HasSensitive() bool {
+	for _, a := range c.Attributes {
+		if a.Sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// attribute is a typed parameter attribute.
+type attribute struct {
+	Name        string
+	Key         string
+	GoName      string
+	Kind        string // String, Int64, Float64, Bool, List or Dynamic
+	ElementType string // element type of List attributes, e.g. String
+	Required    bool
+	Sensitive   bool
+	Description string
+	Validators  []string
+}
+
+// Attribute names used by the common attributes of the resources and Terraform meta-arguments.
+var reservedNames = map[string][]string{
+	kindDestination: {"id", "instance", "last_updated", "destination_type_id", "name", "stack_id", "auth_id", "headers_formatting"},
+	kindDatastream:  {"id", "instance", "last_updated", "datastream_type_id", "name", "description", "stack_id", "auth_id", "datatype", "enabled"},
+	"":              {"count", "depends_on", "for_each", "lifecycle", "provider", "provisioner", "connection"},
+}
+
+// Fields set by the common attributes of the resources or not managed by them (e.g. schedules).
+var managedFields = map[string][]string{
+	kindDestination: adverity.PayloadFields(adverity.DestinationConfig{}),
+	kindDatastream:  adverity.PayloadFields(adverity.DatastreamCreateConfig{}),
+}
+
+// Parameters containing one of these words are marked as sensitive.
+var sensitiveWords = []string{"password", "passphrase", "secret", "token", "private_key"}
+
+var resourceName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// loadFixtures reads and prepares all fixtures in a directory, sorted by resource name.
+func loadFixtures(dir string) ([]connector, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	connectors := make([]connector, 0, len(files))
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var f fixture
+		if err := json.Unmarshal(b, &f); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		c, err := prepare(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		c.File = filepath.Base(file)
+		connectors = append(connectors, c)
+	}
+
+	slices.SortFunc(connectors, func(a, b connector) int {
+		return strings.Compare(a.Resource, b.Resource)
+	})
+
+	return connectors, nil
+}
+
+// prepare validates a fixture and converts the writable fields of its POST action into attributes.
+func prepare(f fixture) (connector, error) {
+	if !resourceName.MatchString(f.Resource) {
+		return connector{}, fmt.Errorf("invalid resource name %q", f.Resource)
+	}
+	if f.Kind != kindDestination && f.Kind != kindDatastream {
+		return connector{}, fmt.Errorf("invalid kind %q, expected %q or %q", f.Kind, kindDestination, kindDatastream)
+	}
+	if f.TypeID <= 0 {
+		return connector{}, fmt.Errorf("missing type_id")
+	}
+	if f.Title == "" {
+		return connector{}, fmt.Errorf("missing title")
+	}
+
+	fields, ok := f.Options.Actions["POST"]
+	if !ok {
+		return connector{}, fmt.Errorf("the OPTIONS response has no POST action")
+	}
+
+	c := connector{
+		Resource: f.Resource,
+		Kind:     f.Kind,
+		TypeID:   f.TypeID,
+		Title:    f.Title,
+	}
+
+	reserved := append(slices.Clone(reservedNames[f.Kind]), reservedNames[""]...)
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		field := fields[key]
+		if field.ReadOnly || slices.Contains(managedFields[f.Kind], key) {
+			continue
+		}
+
+		name := key
+		if renamed, ok := f.Rename[key]; ok {
+			name = renamed
+		}
+		if slices.Contains(reserved, name) {
+			return connector{}, fmt.Errorf("the attribute name %q of parameter %q is reserved, rename it in the fixture", name, key)
+		}
+
+		c.Attributes = append(c.Attributes, newAttribute(name, key, field))
+	}
+
+	return c, nil
+}
+
+// newAttribute maps a field onto the closest Terraform attribute type. Fields which cannot be
+// typed (e.g. nested objects) become dynamic attributes.
+func newAttribute(name, key string, field adverity.FieldMetadata) attribute {
+	a := attribute{
+		Name:        name,
+		Key:         key,
+		GoName:      goName(name),
+		Kind:        scalarKind(field.Type),
+		Required:    field.Required,
+		Description: description(field),
+	}
+
+	for _, word := range sensitiveWords {
+		if strings.Contains(key, word) {
+			a.Sensitive = true
+		}
+	}
+
+	switch field.Type {
+	case "choice":
+		a.Kind = choiceKind(field.Choices)
+		if v := oneOf(a.Kind, field.Choices); v != "" {
+			a.Validators = append(a.Validators, v)
+		}
+	case "multiple choice":
+		a.Kind, a.ElementType = "List", choiceKind(field.Choices)
+		if v := oneOf(a.ElementType, field.Choices); v != "" {
+			a.Validators = append(a.Validators, fmt.Sprintf("listvalidator.Value%ssAre(%s)", a.ElementType, v))
+		}
+	case "list":
+		a.Kind = "Dynamic"
+		if field.Child != nil {
+			if element := scalarKind(field.Child.Type); element != "Dynamic" {
+				a.Kind, a.ElementType = "List", element
+			}
+		}
+	}
+
+	switch a.Kind {
+	case "String":
+		if field.MaxLength != nil {
+			a.Validators = append(a.Validators, fmt.Sprintf("stringvalidator.LengthAtMost(%d)", *field.MaxLength))
+		}
+	case "Int64":
+		if v := between("int64validator", field.MinValue, field.MaxValue, func(f float64) string { return fmt.Sprint(int64(f)) }); v != "" {
+			a.Validators = append(a.Validators, v)
+		}
+	case "Float64":
+		if v := between("float64validator", field.MinValue, field.MaxValue, func(f float64) string { return fmt.Sprintf("%#v", f) }); v != "" {
+			a.Validators = append(a.Validators, v)
+		}
+	}
+
+	return a
+}
+
+// scalarKind returns the attribute type of a field type which maps onto a single value.
+func scalarKind(fieldType string) string {
+	switch fieldType {
+	case "string", "email", "url", "slug", "regex", "date", "datetime", "time", "duration":
+		return "String"
+	case "integer":
+		return "Int64"
+	case "float", "decimal":
+		return "Float64"
+	case "boolean":
+		return "Bool"
+	default:
+		return "Dynamic"
+	}
+}
+
+// choiceKind returns Int64 if all choices are integers, String otherwise.
+func choiceKind(choices []adverity.FieldChoice) string {
+	if len(choices) == 0 {
+		return "String"
+	}
+	for _, choice := range choices {
+		n, ok := choice.Value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return "String"
+		}
+	}
+	return "Int64"
+}
+
+func oneOf(kind string, choices []adverity.FieldChoice) string {
+	if len(choices) == 0 {
+		return ""
+	}
+	values := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		if kind == "Int64" {
+			values = append(values, fmt.Sprint(int64(choice.Value.(float64))))
+		} else {
+			values = append(values, fmt.Sprintf("%q", fmt.Sprint(choice.Value)))
+		}
+	}
+	return fmt.Sprintf("%svalidator.OneOf(%s)", strings.ToLower(kind), strings.Join(values, ", "))
+}
+
+func between(pkg string, minValue, maxValue *float64, format func(float64) string) string {
+	switch {
+	case minValue != nil && maxValue != nil:
+		return fmt.Sprintf("%s.Between(%s, %s)", pkg, format(*minValue), format(*maxValue))
+	case minValue != nil:
+		return fmt.Sprintf("%s.AtLeast(%s)", pkg, format(*minValue))
+	case maxValue != nil:
+		return fmt.Sprintf("%s.AtMost(%s)", pkg, format(*maxValue))
+	default:
+		return ""
+	}
+}
+
+// description documents a field with its help text (or label) and choices.
+func description(field adverity.FieldMetadata) string {
+	text := strings.TrimSpace(field.HelpText)
+	if text == "" {
+		text = field.Label + "."
+	}
+	if !strings.HasSuffix(text, ".") {
+		text += "."
+	}
+
+	if len(field.Choices) > 0 {
+		choices := make([]string, 0, len(field.Choices))
+		for _, choice := range field.Choices {
+			choices = append(choices, fmt.Sprintf("`%v` (%s)", choice.Value, choice.DisplayName))
+		}
+		prefix := " One of "
+		if field.Type == "multiple choice" {
+			prefix = " Any of "
+		}
+		text += prefix + strings.Join(choices, ", ") + "."
+	}
+
+	return text
+}
+
+// goName converts a snake case name into an exported Go identifier, e.g. login_customer_id to LoginCustomerID.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		switch part {
+		case "":
+		case "id", "url", "api":
+			b.WriteString(strings.ToUpper(part))
+		default:
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	if s := b.String(); s != "" && s[0] >= '0' && s[0] <= '9' {
+		return "Field" + s
+	}
+	return b.String()
+}
+
+// lowerCamel converts a snake case name into an unexported Go identifier, e.g. bigquery_destination to bigqueryDestination.
+func lowerCamel(name string) string {
+	s := goName(name)
+	return strings.ToLower(s[:1]) + s[1:]
+}
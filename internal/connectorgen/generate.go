@@ -0,0 +1,224 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+const (
+	generatedSuffix = "_gen.go"
+	generatedMarker = "Code generated by connectorgen"
+	registryFile    = "typed_connectors" + generatedSuffix
+)
+
+const header = `// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// ` + generatedMarker + ` from {{ .File }}. DO NOT EDIT.
+
+package provider
+`
+
+var resourceTemplate = template.Must(template.New("resource").Funcs(template.FuncMap{
+	"lowerCamel": lowerCamel,
+	"goName":     goName,
+	"quote":      func(s string) string { return fmt.Sprintf("%q", s) },
+}).Parse(header + `
+import (
+{{- range .Imports }}
+	{{ . }}
+{{- end }}
+)
+
+{{ $model := printf "%sModel" (lowerCamel .Resource) -}}
+{{ $impl := printf "typed%sResource[%s, *%s]" (goName .Kind) $model $model -}}
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &{{ $impl }}{}
+	_ resource.ResourceWithConfigure   = &{{ $impl }}{}
+	_ resource.ResourceWithImportState = &{{ $impl }}{}
+)
+
+// New{{ goName .Resource }}Resource is a helper function to simplify the provider implementation.
+func New{{ goName .Resource }}Resource() resource.Resource {
+	return &{{ $impl }}{
+		connector: typedConnector{
+			TypeName: {{ quote .Resource }},
+			TypeID:   {{ .TypeID }},
+			Title:    {{ quote .Title }},
+			Attributes: map[string]schema.Attribute{
+			{{- range .Attributes }}
+				{{ quote .Name }}: schema.{{ .Kind }}Attribute{
+					Description: {{ quote .Description }},
+				{{- if .ElementType }}
+					ElementType: types.{{ .ElementType }}Type,
+				{{- end }}
+				{{- if .Required }}
+					Required:    true,
+				{{- else }}
+					Optional:    true,
+				{{- end }}
+				{{- if .Sensitive }}
+					Sensitive:   true,
+				{{- end }}
+				{{- if .Validators }}
+					Validators: []validator.{{ .Kind }}{
+					{{- range .Validators }}
+						{{ . }},
+					{{- end }}
+					},
+				{{- end }}
+				},
+			{{- end }}
+			},
+		},
+	}
+}
+
+// {{ $model }} maps the resource schema data.
+type {{ $model }} struct {
+	typed{{ goName .Kind }}Model
+{{- range .Attributes }}
+	{{ .GoName }} types.{{ .Kind }} ` + "`" + `tfsdk:"{{ .Name }}"` + "`" + `
+{{- end }}
+}
+
+{{ $destination := eq .Kind "destination" -}}
+// parameters maps the typed attributes onto the {{ .Kind }} parameters.
+func (m *{{ $model }}) parameters(diags *diag.Diagnostics) []adverity.Parameter {
+	return expandTypedParameters(map[string]attr.Value{
+	{{- range .Attributes }}
+	{{- if not (and $destination .Sensitive) }}
+		{{ quote .Key }}: m.{{ .GoName }},
+	{{- end }}
+	{{- end }}
+	}, diags)
+}
+
+
+// refreshParameters maps the parameters of a {{ .Kind }} response onto the typed attributes.
+{{- if .HasSensitive }}
+// Sensitive attributes are kept, since Adverity does not return their values.
+{{- end }}
+func (m *{{ $model }}) refreshParameters(ctx context.Context, fields map[string]json.RawMessage, imported bool, diags *diag.Diagnostics) {
+{{- range .Attributes }}
+{{- if not .Sensitive }}
+	refreshTypedParameter(ctx, fields, {{ quote .Key }}, {{ quote .Name }}, imported, &m.{{ .GoName }}, diags)
+{{- end }}
+{{- end }}
+}
+{{- if $destination }}
+
+// sensitiveParameters maps the sensitive typed attributes onto the sensitive destination parameters, which are not logged.
+func (m *{{ $model }}) sensitiveParameters(diags *diag.Diagnostics) []adverity.Parameter {
+	{{- if not .HasSensitive }}
+	return nil
+	{{- else }}
+	return expandTypedParameters(map[string]attr.Value{
+	{{- range .Attributes }}
+	{{- if .Sensitive }}
+		{{ quote .Key }}: m.{{ .GoName }},
+	{{- end }}
+	{{- end }}
+	}, diags)
+	{{- end }}
+}
+{{- end }}
+`))
+
+var registryTemplate = template.Must(template.New("registry").Funcs(template.FuncMap{
+	"goName": goName,
+}).Parse(header + `
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// typedConnectorResources are the typed connector resources generated from the fixtures.
+var typedConnectorResources = []func() resource.Resource{
+{{- range .Connectors }}
+	New{{ goName .Resource }}Resource,
+{{- end }}
+}
+`))
+
+// generate renders the resource of each connector and the registry of all connectors, keyed by file name.
+func generate(connectors []connector) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(connectors)+1)
+
+	for _, c := range connectors {
+		data := struct {
+			connector
+			Imports []string
+		}{c, imports(c)}
+
+		content, err := render(resourceTemplate, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.File, err)
+		}
+		files[c.Resource+"_resource"+generatedSuffix] = content
+	}
+
+	data := struct {
+		File       string
+		Connectors []connector
+	}{"the fixtures", connectors}
+	content, err := render(registryTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	files[registryFile] = content
+
+	return files, nil
+}
+
+func render(t *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.String())
+	}
+
+	return formatted, nil
+}
+
+// imports returns the import specs used by the generated resource of a connector, grouped like the handwritten code.
+func imports(c connector) []string {
+	std := []string{`"context"`, `"encoding/json"`}
+	internal := []string{`"terraform-provider-adverity/internal/adverity"`}
+	external := []string{
+		`"github.com/hashicorp/terraform-plugin-framework/attr"`,
+		`"github.com/hashicorp/terraform-plugin-framework/diag"`,
+		`"github.com/hashicorp/terraform-plugin-framework/resource"`,
+		`"github.com/hashicorp/terraform-plugin-framework/resource/schema"`,
+		`"github.com/hashicorp/terraform-plugin-framework/types"`,
+	}
+
+	for _, a := range c.Attributes {
+		if len(a.Validators) > 0 {
+			external = append(external, `"github.com/hashicorp/terraform-plugin-framework/schema/validator"`)
+		}
+		for _, v := range a.Validators {
+			for _, pkg := range []string{"listvalidator", "stringvalidator", "int64validator", "float64validator"} {
+				if strings.Contains(v, pkg+".") {
+					external = append(external, `"github.com/hashicorp/terraform-plugin-framework-validators/`+pkg+`"`)
+				}
+			}
+		}
+	}
+
+	slices.Sort(external)
+	specs := append(std, "")
+	specs = append(append(specs, internal...), "")
+	return append(specs, slices.Compact(external)...)
+}
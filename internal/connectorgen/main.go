@@ -0,0 +1,63 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Connectorgen generates typed destination and datastream resources from recorded
+// OPTIONS responses of the Adverity API.
+//
+// Each fixture is a JSON file with the resource name, the kind (destination or datastream),
+// the type id and title of the connector and the recorded OPTIONS response of its list endpoint
+// (e.g. target-types/253/targets/). The writable fields of the POST action which are not set by
+// the common attributes of the resource become typed attributes, which are mapped onto the
+// parameters of the DestinationConfig or DatastreamCreateConfig payload.
+//
+// Usage:
+//
+//	go run ./internal/connectorgen -fixtures internal/connectorgen/fixtures -output internal/provider
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	fixturesDir := flag.String("fixtures", "fixtures", "directory containing the fixtures")
+	outputDir := flag.String("output", ".", "directory to write the generated files to")
+	flag.Parse()
+
+	connectors, err := loadFixtures(*fixturesDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := generate(connectors)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Remove previously generated files, so removing a fixture removes its resource
+	existing, err := filepath.Glob(filepath.Join(*outputDir, "*"+generatedSuffix))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range existing {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if strings.Contains(string(b), generatedMarker) {
+			if err := os.Remove(name); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(*outputDir, name), content, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+// TestGeneratedFilesUpToDate ensures the generated resources match the fixtures.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	connectors, err := loadFixtures("fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := generate(connectors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("..", "provider", name))
+		if err != nil {
+			t.Errorf("%s: %v, run go generate ./internal/provider", name, err)
+			continue
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s is out of date, run go generate ./internal/provider", name)
+		}
+	}
+}
+
+// TestFixtureEnumChoices ensures the enum codes of the provider are offered by the choices in the fixtures.
+func TestFixtureEnumChoices(t *testing.T) {
+	enums := map[string]map[string]adverity.Enum{
+		kindDestination: {"headers_formatting": adverity.HeadersFormattings},
+	}
+
+	files, err := filepath.Glob(filepath.Join("fixtures", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var f fixture
+		if err := json.Unmarshal(b, &f); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		for name, e := range enums[f.Kind] {
+			field, ok := f.Options.Actions["POST"][name]
+			if !ok || len(field.Choices) == 0 {
+				t.Errorf("%s: %s has no choices", file, name)
+				continue
+			}
+			for code := range e {
+				if err := e.CheckChoice(field, code); err != nil {
+					t.Errorf("%s: %s: %v", file, name, err)
+				}
+			}
+		}
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	tests := map[string]struct {
+		fixture fixture
+		wantErr bool
+	}{
+		"valid": {
+			fixture: fixture{Resource: "test_destination", Kind: kindDestination, TypeID: 1, Title: "Test"},
+		},
+		"invalid kind": {
+			fixture: fixture{Resource: "test_destination", Kind: "connection", TypeID: 1, Title: "Test"},
+			wantErr: true,
+		},
+		"missing type id": {
+			fixture: fixture{Resource: "test_destination", Kind: kindDestination, Title: "Test"},
+			wantErr: true,
+		},
+		"reserved name": {
+			fixture: fixture{Resource: "test_destination", Kind: kindDestination, TypeID: 1, Title: "Test",
+				Options: adverityOptions(map[string]string{"count": "integer"})},
+			wantErr: true,
+		},
+		"renamed": {
+			fixture: fixture{Resource: "test_destination", Kind: kindDestination, TypeID: 1, Title: "Test",
+				Rename: map[string]string{"count": "row_count"}, Options: adverityOptions(map[string]string{"count": "integer"})},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.fixture.Options.Actions == nil {
+				tt.fixture.Options = adverityOptions(nil)
+			}
+			_, err := prepare(tt.fixture)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewAttribute(t *testing.T) {
+	minValue, maxValue := 1.0, 90.0
+	tests := map[string]struct {
+		field       adverity.FieldMetadata
+		kind        string
+		elementType string
+		validators  []string
+	}{
+		"string": {
+			field: adverity.FieldMetadata{Type: "url"},
+			kind:  "String",
+		},
+		"integer range": {
+			field:      adverity.FieldMetadata{Type: "integer", MinValue: &minValue, MaxValue: &maxValue},
+			kind:       "Int64",
+			validators: []string{"int64validator.Between(1, 90)"},
+		},
+		"integer choice": {
+			field:      adverity.FieldMetadata{Type: "choice", Choices: []adverity.FieldChoice{{Value: 7.0}, {Value: 30.0}}},
+			kind:       "Int64",
+			validators: []string{"int64validator.OneOf(7, 30)"},
+		},
+		"string choice": {
+			field:      adverity.FieldMetadata{Type: "choice", Choices: []adverity.FieldChoice{{Value: "a"}, {Value: 1.0}}},
+			kind:       "String",
+			validators: []string{`stringvalidator.OneOf("a", "1")`},
+		},
+		"multiple choice": {
+			field:       adverity.FieldMetadata{Type: "multiple choice", Choices: []adverity.FieldChoice{{Value: "a"}}},
+			kind:        "List",
+			elementType: "String",
+			validators:  []string{`listvalidator.ValueStringsAre(stringvalidator.OneOf("a"))`},
+		},
+		"list": {
+			field:       adverity.FieldMetadata{Type: "list", Child: &adverity.FieldMetadata{Type: "boolean"}},
+			kind:        "List",
+			elementType: "Bool",
+		},
+		"list of objects": {
+			field: adverity.FieldMetadata{Type: "list", Child: &adverity.FieldMetadata{Type: "nested object"}},
+			kind:  "Dynamic",
+		},
+		"json": {
+			field: adverity.FieldMetadata{Type: "json"},
+			kind:  "Dynamic",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newAttribute("test", "test", tt.field)
+			if a.Kind != tt.kind || a.ElementType != tt.elementType {
+				t.Errorf("newAttribute() = %s/%s, want %s/%s", a.Kind, a.ElementType, tt.kind, tt.elementType)
+			}
+			if !slices.Equal(a.Validators, tt.validators) {
+				t.Errorf("newAttribute() validators = %q, want %q", a.Validators, tt.validators)
+			}
+		})
+	}
+}
+
+func adverityOptions(fields map[string]string) adverity.MetadataResponse {
+	post := make(map[string]adverity.FieldMetadata, len(fields))
+	for key, fieldType := range fields {
+		post[key] = adverity.FieldMetadata{Type: fieldType}
+	}
+	return adverity.MetadataResponse{Actions: map[string]map[string]adverity.FieldMetadata{"POST": post}}
+}
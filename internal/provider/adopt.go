@@ -0,0 +1,150 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// adoptExistingAttribute returns the adopt_existing attribute of a resource of the kind, e.g. "datastream".
+func adoptExistingAttribute(kind string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: fmt.Sprintf("Whether to adopt an existing %[1]s with the same name in the workspace instead of creating a new one, "+
+			"e.g. to take over %[1]ss created manually. The adopted %[1]s is updated to the configuration. "+
+			"Creating fails if several %[1]ss have the name. Requires `stack_id`. Only used when the resource is created.", kind),
+		Optional: true,
+		Validators: []validator.Bool{
+			adoptExistingValidator{},
+		},
+	}
+}
+
+// adoptExistingValidator requires stack_id if adopt_existing is true, since the existing object is searched
+// in the workspace and a list without a workspace does not find it.
+type adoptExistingValidator struct{}
+
+func (v adoptExistingValidator) Description(_ context.Context) string {
+	return "Requires stack_id to be set if true"
+}
+
+func (v adoptExistingValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v adoptExistingValidator) ValidateBool(ctx context.Context, req validator.BoolRequest, resp *validator.BoolResponse) {
+	if !req.ConfigValue.ValueBool() {
+		return
+	}
+
+	var stackId types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("stack_id"), &stackId)...)
+	if resp.Diagnostics.HasError() || !stackId.IsNull() {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Missing workspace",
+		"adopt_existing requires stack_id, the workspace which is searched for the existing object.",
+	)
+}
+
+// createOrAdopt creates an object of the kind with create and returns it, adding an error to diags on failure.
+//
+// With adoptExisting, the object with the same name found by find is adopted instead, and existing is true,
+// so the caller updates it to the plan. If the create request fails in a way it may have created the object
+// anyway, i.e. it timed out or failed with a server error, the object found by find which carries the client
+// token of the request (see adverity.NewClientToken), or which created otherwise identifies as created by the
+// request, is adopted if it is the only one, so the next apply does not create a duplicate. Otherwise the user is
+// asked to import the object.
+func createOrAdopt[T any](ctx context.Context, kind, name string, adoptExisting bool, create func() (*T, error), find func() ([]T, error), objectId func(T) int64, created func(T) bool, diags *diag.Diagnostics) (object *T, existing bool) {
+	if adoptExisting {
+		objects, err := find()
+		if err != nil {
+			diags.AddError(
+				"Error searching existing "+kind,
+				fmt.Sprintf("Could not search the workspace for a %s named %q to adopt, unexpected error: %s", kind, name, err),
+			)
+			return nil, false
+		}
+		if len(objects) > 1 {
+			diags.AddError(
+				"Ambiguous existing "+kind,
+				fmt.Sprintf("The workspace contains %d %ss named %q (IDs %s), so adopt_existing cannot choose one. "+
+					"Import the intended %s instead.", len(objects), kind, name, joinIds(objects, objectId), kind),
+			)
+			return nil, false
+		}
+		if len(objects) == 1 {
+			tflog.Info(ctx, "Adopting the existing Adverity "+kind, map[string]any{"id": objectId(objects[0]), "name": name})
+			return &objects[0], true
+		}
+	}
+
+	object, err := create()
+	if err == nil {
+		return object, false
+	}
+
+	detail := fmt.Sprintf("Could not create %s, unexpected error: %s", kind, err)
+	if !adverity.MayHaveSucceeded(err) {
+		diags.AddError("Error creating "+kind, detail)
+		return nil, false
+	}
+
+	// The request may have created the object, which is adopted if it is the only one identified as created by the request
+	var objects []T
+	found, findErr := find()
+	if findErr != nil {
+		tflog.Warn(ctx, "Could not search the "+kind+" created by the failed request", map[string]any{"error": findErr.Error()})
+	}
+	for _, o := range found {
+		if created(o) {
+			objects = append(objects, o)
+		}
+	}
+	if len(objects) != 1 {
+		diags.AddError("Error creating "+kind, detail+
+			fmt.Sprintf("\n\nAdverity may have created the %[1]s anyway. Check the workspace for a %[1]s named %q "+
+				"and import it before applying again, so no duplicate is created.", kind, name))
+		return nil, false
+	}
+
+	diags.AddWarning(
+		"Adopted "+kind+" after a failed create request",
+		fmt.Sprintf("The request to create the %[1]s failed (%[2]s), but the workspace contains the %[1]s named %[3]q with ID %[4]d, "+
+			"which is identified as created by the request before it failed. It is adopted instead of creating a duplicate.",
+			kind, err, name, objectId(objects[0])),
+	)
+	return &objects[0], false
+}
+
+// tagDescription returns a description with the client token of a create request appended.
+func tagDescription(description *string, token string) *string {
+	if description == nil || *description == "" {
+		return &token
+	}
+	tagged := *description + "\n\n" + token
+	return &tagged
+}
+
+// joinIds returns the IDs of the objects as a comma-separated list.
+func joinIds[T any](objects []T, objectId func(T) int64) string {
+	ids := make([]string, 0, len(objects))
+	for _, o := range objects {
+		ids = append(ids, strconv.FormatInt(objectId(o), 10))
+	}
+	return strings.Join(ids, ", ")
+}
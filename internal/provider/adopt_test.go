@@ -0,0 +1,276 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/adverity/fakeserver"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testAdoptClient returns a client of a fake Adverity API with an authorization, whose requests matching
+// fail reach the API but fail with a gateway timeout.
+func testAdoptClient(t *testing.T, fail func(req *http.Request) bool) *adverity.Client {
+	t.Helper()
+
+	server := testReplayServer(t)
+
+	gatewayTimeout := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err != nil || fail == nil || !fail(req) {
+				return resp, err
+			}
+			_ = resp.Body.Close()
+			return &http.Response{StatusCode: http.StatusGatewayTimeout, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+		})
+	}
+	client, err := adverity.NewClient(t.Context(), server.URL, adverity.WithToken(fakeserver.DefaultToken),
+		adverity.WithCACertPEM([]byte(server.CertificatePEM())), adverity.WithRoundTripper(gatewayTimeout))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+// testFailCreates matches the create requests of datastreams and destinations.
+func testFailCreates(req *http.Request) bool {
+	return req.Method == http.MethodPost && (strings.HasSuffix(req.URL.Path, "/datastreams/") || strings.HasSuffix(req.URL.Path, "/targets/"))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// A datastream created by a request which timed out is adopted instead of creating a duplicate with the next apply.
+func TestCreateAdoptsAfterTimeout(t *testing.T) {
+	client := testAdoptClient(t, testFailCreates)
+	r := &datastreamResource{providerData: &providerData{defaultClient: client}}
+
+	state := testReplayCreate(t, r, testReplayDatastreamPlan())
+
+	var datastream datastreamResourceModel
+	if diags := state.Get(t.Context(), &datastream); diags.HasError() {
+		t.Fatal(diags)
+	}
+	datastreams, err := client.ListDatastreams(t.Context(), testReplayStackID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datastreams) != 1 || datastreams[0].ID != datastream.ID.ValueInt64() {
+		t.Errorf("datastreams = %+v, want the adopted datastream %s only", datastreams, datastream.ID)
+	}
+	if len(datastream.Schedules) != 0 || len(datastreams[0].Schedules) != 0 {
+		t.Errorf("schedules = %+v, want the default schedule of the adopted datastream to be removed", datastreams[0].Schedules)
+	}
+}
+
+// After a timeout, only the datastream carrying the client token of the request is adopted, not another datastream with the name.
+func TestCreateAdoptsOnlyCreatedDatastreamAfterTimeout(t *testing.T) {
+	client := testAdoptClient(t, testFailCreates)
+	r := &datastreamResource{providerData: &providerData{defaultClient: client}}
+
+	// The create request of the other datastream also times out after creating it
+	name, stack, auth := "Campaigns", int64(testReplayStackID), int64(testReplayAuthorizationID)
+	if _, err := client.CreateDatastream(t.Context(), testAccDatastreamTypeID, &adverity.DatastreamCreateConfig{Name: &name, StackID: &stack, AuthID: &auth}); !adverity.MayHaveSucceeded(err) {
+		t.Fatalf("CreateDatastream() error = %v, want a timeout", err)
+	}
+	other, err := client.FindDatastreams(t.Context(), testReplayStackID, testAccDatastreamTypeID, name)
+	if err != nil || len(other) != 1 {
+		t.Fatalf("FindDatastreams() = %+v, %v, want the other datastream", other, err)
+	}
+
+	plan := testReplayDatastreamPlan()
+	plan.Description = types.StringValue("Daily campaigns")
+	state := testReplayCreate(t, r, plan)
+
+	var datastream datastreamResourceModel
+	if diags := state.Get(t.Context(), &datastream); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if datastream.ID.ValueInt64() == other[0].ID {
+		t.Errorf("id = %s, want the created datastream instead of the other one", datastream.ID)
+	}
+	created, err := client.ReadDatastream(t.Context(), testAccDatastreamTypeID, int(datastream.ID.ValueInt64()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Description != "Daily campaigns" || datastream.Description.ValueString() != "Daily campaigns" {
+		t.Errorf("description = %q, %s, want the client token replaced by the planned description", created.Description, datastream.Description)
+	}
+}
+
+// If the client token cannot be replaced by the planned description, the created datastream is kept with a warning.
+func TestCreateKeepsDatastreamWhenDescriptionFails(t *testing.T) {
+	client := testAdoptClient(t, func(req *http.Request) bool {
+		return req.Method == http.MethodPatch && strings.HasPrefix(req.URL.Path, "/api/datastream-types/")
+	})
+	r := &datastreamResource{providerData: &providerData{defaultClient: client}}
+
+	plan := testReplayDatastreamPlan()
+	plan.Description = types.StringValue("Daily campaigns")
+	resp := resource.CreateResponse{State: testReplayState(t, r, nil)}
+	r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan(testReplayState(t, r, plan))}, &resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("Create() diagnostics = %v, want a warning", resp.Diagnostics)
+	}
+
+	var datastream datastreamResourceModel
+	if diags := resp.State.Get(t.Context(), &datastream); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if datastream.ID.IsNull() || datastream.Description.ValueString() != "Daily campaigns" {
+		t.Errorf("state = id %s, description %s, want the created datastream with the planned description", datastream.ID, datastream.Description)
+	}
+	if _, err := client.ReadDatastream(t.Context(), testAccDatastreamTypeID, int(datastream.ID.ValueInt64())); err != nil {
+		t.Errorf("ReadDatastream() error = %v, want the datastream in the state to exist", err)
+	}
+}
+
+// After a timeout, the only destination with the name and type is adopted, not a destination of another type.
+func TestCreateAdoptsDestinationAfterTimeout(t *testing.T) {
+	client := testAdoptClient(t, testFailCreates)
+	r := &destinationResource{providerData: &providerData{defaultClient: client}}
+
+	// A destination of another type with the name
+	const otherTypeId = testAccDestinationTypeID + 1
+	name, stack := "Warehouse", int64(testReplayStackID)
+	if _, err := client.CreateDestination(t.Context(), otherTypeId, &adverity.DestinationConfig{Name: &name, StackID: &stack}); !adverity.MayHaveSucceeded(err) {
+		t.Fatalf("CreateDestination() error = %v, want a timeout", err)
+	}
+
+	plan := destinationResourceModel{
+		DestinationTypeId:   types.Int64Value(testAccDestinationTypeID),
+		ID:                  types.Int64Unknown(),
+		Name:                types.StringValue(name),
+		StackID:             types.Int64Value(stack),
+		HeadersFormatting:   types.StringUnknown(),
+		Parameters:          types.DynamicNull(),
+		SensitiveParameters: types.DynamicNull(),
+		LastUpdated:         types.StringUnknown(),
+	}
+	resp := resource.CreateResponse{State: testReplayState(t, r, nil)}
+	r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan(testReplayState(t, r, plan))}, &resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("Create() diagnostics = %v, want the adoption warning", resp.Diagnostics)
+	}
+
+	var destination destinationResourceModel
+	if diags := resp.State.Get(t.Context(), &destination); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if _, err := client.ReadDestination(t.Context(), testAccDestinationTypeID, int(destination.ID.ValueInt64())); err != nil {
+		t.Errorf("id = %s, want the created destination of type %d: %v", destination.ID, testAccDestinationTypeID, err)
+	}
+}
+
+func TestAdoptExistingRequiresStack(t *testing.T) {
+	tests := map[string]struct {
+		adoptExisting types.Bool
+		stackId       types.Int64
+		wantError     bool
+	}{
+		"adopt without workspace": {adoptExisting: types.BoolValue(true), stackId: types.Int64Null(), wantError: true},
+		"adopt in workspace":      {adoptExisting: types.BoolValue(true), stackId: types.Int64Value(1)},
+		"unknown workspace":       {adoptExisting: types.BoolValue(true), stackId: types.Int64Unknown()},
+		"no adoption":             {adoptExisting: types.BoolValue(false), stackId: types.Int64Null()},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &datastreamResource{}
+			config := testReplayDatastreamPlan()
+			config.StackID = test.stackId
+			config.AdoptExisting = test.adoptExisting
+
+			req := validator.BoolRequest{
+				Path:        path.Root("adopt_existing"),
+				ConfigValue: test.adoptExisting,
+				Config:      tfsdk.Config(testReplayState(t, r, config)),
+			}
+			var resp validator.BoolResponse
+			adoptExistingValidator{}.ValidateBool(t.Context(), req, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantError {
+				t.Errorf("has error = %v, want %v: %v", got, test.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCreateOrAdoptAfterFailure(t *testing.T) {
+	type object struct {
+		id      int64
+		created bool
+	}
+	timeout := &adverity.APIError{StatusCode: http.StatusGatewayTimeout}
+
+	tests := map[string]struct {
+		createErr error
+		found     []object
+		wantId    int64
+		wantError string
+	}{
+		"created object": {
+			createErr: timeout,
+			found:     []object{{id: 3}, {id: 4, created: true}},
+			wantId:    4,
+		},
+		"object not created by the request": {
+			createErr: timeout,
+			found:     []object{{id: 3}},
+			wantError: "import it before applying again",
+		},
+		"several created objects": {
+			createErr: timeout,
+			found:     []object{{id: 3, created: true}, {id: 4, created: true}},
+			wantError: "import it before applying again",
+		},
+		"failed request": {
+			createErr: &adverity.APIError{StatusCode: http.StatusBadRequest},
+			found:     []object{{id: 3, created: true}},
+			wantError: "Could not create datastream",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got, existing := createOrAdopt(t.Context(), "datastream", "Campaigns", false,
+				func() (*object, error) { return nil, test.createErr },
+				func() ([]object, error) { return test.found, nil },
+				func(o object) int64 { return o.id },
+				func(o object) bool { return o.created },
+				&diags,
+			)
+
+			if existing {
+				t.Error("existing = true, want false")
+			}
+			if test.wantError != "" {
+				if got != nil || !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), test.wantError) {
+					t.Errorf("createOrAdopt() = %v, %v, want the error %q", got, diags, test.wantError)
+				}
+				return
+			}
+			if diags.HasError() || got == nil || got.id != test.wantId {
+				t.Errorf("createOrAdopt() = %v, %v, want the object %d", got, diags, test.wantId)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &apiTokenEphemeralResource{}
+)
+
+// apiTokenPrivateKey is the key of the private data of an open token, see apiTokenPrivateData.
+const apiTokenPrivateKey = "api_token"
+
+// NewApiTokenEphemeralResource is a helper function to simplify the provider implementation.
+func NewApiTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &apiTokenEphemeralResource{}
+}
+
+// apiTokenEphemeralResource is the ephemeral resource implementation.
+type apiTokenEphemeralResource struct {
+	providerData *providerData
+}
+
+// apiTokenEphemeralResourceModel maps the ephemeral resource schema data.
+type apiTokenEphemeralResourceModel struct {
+	Instance    types.String `tfsdk:"instance"`
+	InstanceUrl types.String `tfsdk:"instance_url"`
+	Username    types.String `tfsdk:"username"`
+	Password    types.String `tfsdk:"password"`
+	Revoke      types.Bool   `tfsdk:"revoke"`
+	Token       types.String `tfsdk:"token"`
+}
+
+// apiTokenPrivateData is the private data of an open token, to revoke it on close.
+type apiTokenPrivateData struct {
+	Instance    *string `json:"instance,omitempty"`
+	InstanceUrl *string `json:"instance_url,omitempty"`
+	Token       string  `json:"token"`
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (r *apiTokenEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+// Metadata returns the ephemeral resource type name.
+func (r *apiTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_token"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (r *apiTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exchanges a username and password for an Adverity API token, e.g. to configure a second provider alias, without storing the token in the state or plan. " +
+			"The token is issued by the `auth/token/` endpoint of Adverity, so its permissions are those of the user. " +
+			"The token is not short-lived: Adverity issues one token per user, which stays valid after the run until it is revoked, " +
+			"so treat it like the password of the user.",
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Description: "Name of the provider instance to obtain the token from, as configured in an instances block of the provider. " +
+					"Defaults to the instance configured by instance_url.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("instance_url")),
+				},
+			},
+			"instance_url": schema.StringAttribute{
+				Description: "Instance URL of the Adverity API (e.g. https://<your-instance>.datatap.adverity.com). Defaults to the instance of the provider. " +
+					"Plain HTTP is only accepted if the provider sets allow_insecure_http.",
+				Optional: true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Username to obtain the token for.",
+				Required:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Password of the user.",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"revoke": schema.BoolAttribute{
+				Description: "Whether to revoke the token with a `DELETE` request to `auth/token/` when Terraform closes the ephemeral resource. Defaults to false. " +
+					"Since Adverity issues one token per user, revoking it also invalidates the token for every other use of the user, so only enable it for a dedicated user. " +
+					"A token which cannot be revoked is reported as a warning and stays valid.",
+				Optional: true,
+			},
+			"token": schema.StringAttribute{
+				Description: "API token of the user.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Open obtains a token.
+func (r *apiTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	// Retrieve values from config
+	var data apiTokenEphemeralResourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := r.client(ctx, data.Instance, data.InstanceUrl, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Exchange the credentials for a token
+	token, err := client.CreateToken(ctx, data.Username.ValueString(), data.Password.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error obtaining Adverity API token",
+			"Could not obtain API token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	data.Token = types.StringValue(token.Token)
+
+	// Keep the token to revoke it on close
+	if data.Revoke.ValueBool() {
+		private, err := json.Marshal(apiTokenPrivateData{
+			Instance:    data.Instance.ValueStringPointer(),
+			InstanceUrl: data.InstanceUrl.ValueStringPointer(),
+			Token:       token.Token,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error storing Adverity API token",
+				"Could not store the token to revoke it on close, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, apiTokenPrivateKey, private)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Set the result to fully populated data
+	diags = resp.Result.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Close revokes the token if revoke is true. A token which cannot be revoked stays valid, so
+// this is reported as a warning.
+func (r *apiTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	b, diags := req.Private.GetKey(ctx, apiTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || b == nil {
+		return
+	}
+
+	var private apiTokenPrivateData
+	if err := json.Unmarshal(b, &private); err != nil {
+		resp.Diagnostics.AddError(
+			"Error revoking Adverity API token",
+			"Could not read the token to revoke, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	client := r.client(ctx, types.StringPointerValue(private.Instance), types.StringPointerValue(private.InstanceUrl), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := client.RevokeToken(ctx, private.Token); err != nil {
+		resp.Diagnostics.AddWarning(
+			"Error revoking Adverity API token",
+			"Could not revoke the API token, so it stays valid until it is revoked in Adverity. Unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// client returns the client to obtain and revoke tokens with. Tokens of an instance unknown to the
+// provider are requested with a separate client, with the transport settings of the provider.
+func (r *apiTokenEphemeralResource) client(ctx context.Context, instance, instanceUrl types.String, diags *diag.Diagnostics) *adverity.Client {
+	switch {
+	case !instanceUrl.IsNull():
+		var opts []adverity.ClientOption
+		allowInsecureHttp := false
+		if r.providerData != nil {
+			opts = r.providerData.sharedOptions
+			allowInsecureHttp = r.providerData.allowInsecureHttp
+		}
+		validateInstanceUrl(path.Root("instance_url"), instanceUrl.ValueString(), allowInsecureHttp, diags)
+		if diags.HasError() {
+			return nil
+		}
+		client, err := adverity.NewClient(ctx, instanceUrl.ValueString(), opts...)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("instance_url"),
+				"Unable to create Adverity API client",
+				"Could not create an API client for the instance URL: "+err.Error(),
+			)
+			return nil
+		}
+		return client
+	case r.providerData != nil:
+		return r.providerData.client(instance, diags)
+	default:
+		diags.AddAttributeError(
+			path.Root("instance_url"),
+			"Missing Adverity instance URL",
+			"The provider is not configured yet, so the instance URL must be set.",
+		)
+		return nil
+	}
+}
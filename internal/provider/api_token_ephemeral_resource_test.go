@@ -0,0 +1,220 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/adverity/fakeserver"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testProtoValue returns a value of an object type for the protocol, with the attributes which are not set null.
+func testProtoValue(t *testing.T, typ tftypes.Type, attributes map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	object, ok := typ.(tftypes.Object)
+	if !ok {
+		t.Fatalf("type %s is not an object", typ)
+	}
+	values := make(map[string]tftypes.Value, len(object.AttributeTypes))
+	for name, attributeType := range object.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+
+	v, err := tfprotov6.NewDynamicValue(typ, tftypes.NewValue(typ, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &v
+}
+
+// testProtoDiagnostics fails the test if diags contain an error.
+func testProtoDiagnostics(t *testing.T, name string, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("%s error: %s: %s", name, d.Summary, d.Detail)
+		}
+	}
+}
+
+// Tokens are kept when Terraform closes the ephemeral resource, unless revoke is true.
+func TestApiTokenEphemeralResourceRevokesToken(t *testing.T) {
+	server := fakeserver.New()
+	t.Cleanup(server.Close)
+	server.AddUser("jane", "secret")
+
+	p := New("test")()
+	var providerSchema provider.SchemaResponse
+	p.Schema(t.Context(), provider.SchemaRequest{}, &providerSchema)
+	providerType := providerSchema.Schema.Type().TerraformType(t.Context())
+
+	var ephemeralSchema ephemeral.SchemaResponse
+	NewApiTokenEphemeralResource().Schema(t.Context(), ephemeral.SchemaRequest{}, &ephemeralSchema)
+	ephemeralType := ephemeralSchema.Schema.Type().TerraformType(t.Context())
+
+	tests := map[string]struct {
+		attributes map[string]tftypes.Value
+		wantRevoke bool
+	}{
+		"default": {},
+		"provider instance": {
+			attributes: map[string]tftypes.Value{"revoke": tftypes.NewValue(tftypes.Bool, true)},
+			wantRevoke: true,
+		},
+		"instance url": {
+			attributes: map[string]tftypes.Value{
+				"instance_url": tftypes.NewValue(tftypes.String, server.URL),
+				"revoke":       tftypes.NewValue(tftypes.Bool, true),
+			},
+			wantRevoke: true,
+		},
+		"revoke disabled": {
+			attributes: map[string]tftypes.Value{"revoke": tftypes.NewValue(tftypes.Bool, false)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps, err := providerserver.NewProtocol6WithError(p)()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			configured, err := ps.ConfigureProvider(t.Context(), &tfprotov6.ConfigureProviderRequest{
+				TerraformVersion: "1.10.0",
+				Config: testProtoValue(t, providerType, map[string]tftypes.Value{
+					"instance_url": tftypes.NewValue(tftypes.String, server.URL),
+					"auth_token":   tftypes.NewValue(tftypes.String, fakeserver.DefaultToken),
+					"ca_cert_pem":  tftypes.NewValue(tftypes.String, server.CertificatePEM()),
+				}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testProtoDiagnostics(t, "ConfigureProvider()", configured.Diagnostics)
+
+			attributes := map[string]tftypes.Value{
+				"username": tftypes.NewValue(tftypes.String, "jane"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			}
+			for k, v := range test.attributes {
+				attributes[k] = v
+			}
+			opened, err := ps.OpenEphemeralResource(t.Context(), &tfprotov6.OpenEphemeralResourceRequest{
+				TypeName: "adverity_api_token",
+				Config:   testProtoValue(t, ephemeralType, attributes),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testProtoDiagnostics(t, "OpenEphemeralResource()", opened.Diagnostics)
+
+			result, err := opened.Result.Unmarshal(ephemeralType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var values map[string]tftypes.Value
+			var token string
+			if err := result.As(&values); err != nil {
+				t.Fatal(err)
+			}
+			if err := values["token"].As(&token); err != nil || token == "" {
+				t.Fatalf("token = %q, %v, want a token", token, err)
+			}
+
+			client, err := adverity.NewClient(t.Context(), server.URL, adverity.WithToken(token), adverity.WithCACertPEM([]byte(server.CertificatePEM())))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.QueryDatastreamTypes(t.Context(), ""); err != nil {
+				t.Fatalf("request with the token error = %v", err)
+			}
+
+			closed, err := ps.CloseEphemeralResource(t.Context(), &tfprotov6.CloseEphemeralResourceRequest{
+				TypeName: "adverity_api_token",
+				Private:  opened.Private,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(closed.Diagnostics) > 0 {
+				t.Fatalf("CloseEphemeralResource() diagnostics = %v", closed.Diagnostics)
+			}
+
+			_, err = client.QueryDatastreamTypes(t.Context(), "")
+			if revoked := err != nil && strings.Contains(err.Error(), "status: 401"); revoked != test.wantRevoke {
+				t.Errorf("request after close error = %v, want revoked %v", err, test.wantRevoke)
+			}
+
+			// Keep the token of the user from leaking into the next test
+			_ = client.RevokeToken(t.Context(), token)
+		})
+	}
+}
+
+func TestApiTokenEphemeralResourceInstanceUrl(t *testing.T) {
+	tests := map[string]struct {
+		instanceUrl       string
+		allowInsecureHttp bool
+		wantSummary       string
+	}{
+		"http is rejected": {
+			instanceUrl: "http://127.0.0.1:1",
+			wantSummary: "Insecure Adverity instance URL",
+		},
+		"http with allow_insecure_http": {
+			instanceUrl:       "http://127.0.0.1:1",
+			allowInsecureHttp: true,
+			wantSummary:       "Error obtaining Adverity API token",
+		},
+		"url without host": {
+			instanceUrl: "https://",
+			wantSummary: "Invalid Adverity instance URL",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, ok := NewApiTokenEphemeralResource().(*apiTokenEphemeralResource)
+			if !ok {
+				t.Fatal("unexpected ephemeral resource implementation")
+			}
+			r.providerData = &providerData{allowInsecureHttp: test.allowInsecureHttp}
+
+			var schemaResp ephemeral.SchemaResponse
+			r.Schema(t.Context(), ephemeral.SchemaRequest{}, &schemaResp)
+			typ := schemaResp.Schema.Type().TerraformType(t.Context())
+			raw, err := testProtoValue(t, typ, map[string]tftypes.Value{
+				"instance_url": tftypes.NewValue(tftypes.String, test.instanceUrl),
+				"username":     tftypes.NewValue(tftypes.String, "jane"),
+				"password":     tftypes.NewValue(tftypes.String, "secret"),
+			}).Unmarshal(typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config := tfsdk.Config{Schema: schemaResp.Schema, Raw: raw}
+
+			resp := ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema}}
+			r.Open(t.Context(), ephemeral.OpenRequest{Config: config}, &resp)
+
+			if errs := resp.Diagnostics.Errors(); len(errs) != 1 || errs[0].Summary() != test.wantSummary {
+				t.Errorf("Open() diagnostics = %v, want the error %q", resp.Diagnostics, test.wantSummary)
+			}
+		})
+	}
+}
@@ -6,16 +6,21 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"terraform-provider-adverity/internal/adverity"
 	"terraform-provider-adverity/internal/provider/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -24,6 +29,8 @@ var (
 	_ resource.Resource                = &authorizationResource{}
 	_ resource.ResourceWithConfigure   = &authorizationResource{}
 	_ resource.ResourceWithImportState = &authorizationResource{}
+	_ resource.ResourceWithModifyPlan  = &authorizationResource{}
+	_ resource.ResourceWithMoveState   = &authorizationResource{}
 )
 
 // NewAuthorizationResource is a helper function to simplify the provider implementation.
@@ -33,7 +40,7 @@ func NewAuthorizationResource() resource.Resource {
 
 // authorizationResource is the resource implementation.
 type authorizationResource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // authorizationResourceModel maps the resource schema data.
@@ -44,6 +51,10 @@ type authorizationResourceModel struct {
 	StackID             types.Int64   `tfsdk:"stack_id"`
 	IsAuthorized        types.Bool    `tfsdk:"is_authorized"`
 	Parameters          types.Dynamic `tfsdk:"parameters"`
+	SensitiveParameters types.Dynamic `tfsdk:"sensitive_parameters"`
+	ParametersWo        types.Dynamic `tfsdk:"parameters_wo"`
+	ParametersWoVersion types.Int64   `tfsdk:"parameters_wo_version"`
+	Instance            types.String  `tfsdk:"instance"`
 	LastUpdated         types.String  `tfsdk:"last_updated"`
 }
 
@@ -54,6 +65,67 @@ func (r *authorizationResource) refreshState(authorization *adverity.Authorizati
 	state.IsAuthorized = types.BoolValue(authorization.IsAuthorized)
 }
 
+// ModifyPlan validates the parameters against the field metadata of the authorization type.
+func (r *authorizationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var typeId types.Int64
+	var parameters, sensitiveParameters, writeOnlyParameters types.Dynamic
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("authorization_type_id"), &typeId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("sensitive_parameters"), &sensitiveParameters)...)
+	// Write-only values are only part of the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parameters_wo"), &writeOnlyParameters)...)
+	if resp.Diagnostics.HasError() || typeId.IsUnknown() {
+		return
+	}
+
+	// Validate against the field metadata of the planned instance
+	client := r.providerData.planClient(ctx, req.Plan, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+
+	readFields := func() (map[string]adverity.FieldMetadata, error) {
+		return client.ReadAuthorizationFields(ctx, int(typeId.ValueInt64()))
+	}
+	sources := []utils.ParameterSource{
+		utils.ParametersSource(parameters),
+		{Path: path.Root("sensitive_parameters"), Value: sensitiveParameters, Sensitive: true},
+		{Path: path.Root("parameters_wo"), Value: writeOnlyParameters, Sensitive: true},
+	}
+	utils.ValidateParametersWithMetadata(sources, readFields, adverity.PayloadFields(adverity.AuthorizationConfig{}), &resp.Diagnostics)
+}
+
+// addWriteOnlyParameters adds the write-only parameters of the configuration to the sensitive parameters of
+// the payload, so they are not logged. They are read from the configuration since they are not part of the plan.
+func (r *authorizationResource) addWriteOnlyParameters(ctx context.Context, config tfsdk.Config, payload *adverity.AuthorizationConfig, diags *diag.Diagnostics) {
+	var writeOnlyParameters types.Dynamic
+	diags.Append(config.GetAttribute(ctx, path.Root("parameters_wo"), &writeOnlyParameters)...)
+	if diags.HasError() || writeOnlyParameters.IsNull() {
+		return
+	}
+
+	parameters := utils.ExpandParameters(writeOnlyParameters.UnderlyingValue(), path.Root("parameters_wo"), diags)
+	if payload.SensitiveParameters != nil {
+		parameters = append(*payload.SensitiveParameters, parameters...)
+	}
+	payload.SensitiveParameters = &parameters
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *authorizationResource) updatePayload(model authorizationResourceModel, diags *diag.Diagnostics) *adverity.AuthorizationConfig {
+	return &adverity.AuthorizationConfig{
+		Name:                model.Name.ValueStringPointer(),
+		StackID:             model.StackID.ValueInt64Pointer(),
+		Parameters:          utils.ExpandDynamicParameters(model.Parameters, path.Root("parameters"), diags),
+		SensitiveParameters: utils.ExpandDynamicParameters(model.SensitiveParameters, path.Root("sensitive_parameters"), diags),
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *authorizationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -62,18 +134,18 @@ func (r *authorizationResource) Configure(_ context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
 }
 
 // Metadata returns the resource type name.
@@ -93,6 +165,7 @@ func (r *authorizationResource) Schema(_ context.Context, _ resource.SchemaReque
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"instance": instanceAttribute(),
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the authorization.",
 				Computed:    true,
@@ -100,14 +173,21 @@ func (r *authorizationResource) Schema(_ context.Context, _ resource.SchemaReque
 			"authorization_type_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the authorization type.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description: "Name of the authorization.",
 				Required:    true,
 			},
 			"stack_id": schema.Int64Attribute{
-				Description: "Numeric identifier of the workspace.",
+				Description: "Numeric identifier of the workspace. Changing it moves the authorization to the workspace in place, keeping its extracts and history.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					stackMove("authorization", path.Empty(), "",
+						"Datastreams and destinations using the authorization are not moved and keep using it."),
+				},
 			},
 			"is_authorized": schema.BoolAttribute{
 				Description: "Whether the authorization is authorized.",
@@ -117,6 +197,25 @@ func (r *authorizationResource) Schema(_ context.Context, _ resource.SchemaReque
 				Description: "Additional authorization parameters.",
 				Optional:    true,
 			},
+			"sensitive_parameters": schema.DynamicAttribute{
+				Description: "Additional authorization parameters with secret values (e.g. passwords, client secrets or service account keys). " +
+					"They are merged with parameters, but never shown in the plan output or logged. Each key must only be set in one of the parameter attributes.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"parameters_wo": schema.DynamicAttribute{
+				Description: "Additional authorization parameters with credentials, which are sent to Adverity but never stored in the state. " +
+					"They are sent on create and whenever parameters_wo_version changes. Each key must only be set in one of parameters, sensitive_parameters and parameters_wo.",
+				Optional:  true,
+				WriteOnly: true,
+			},
+			"parameters_wo_version": schema.Int64Attribute{
+				Description: "Version of parameters_wo. Change it (e.g. increment it) to send the write-only parameters again, e.g. to rotate credentials.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("parameters_wo")),
+				},
+			},
 		},
 	}
 }
@@ -131,6 +230,12 @@ func (r *authorizationResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	payload := &adverity.AuthorizationConfig{
 		Name:    plan.Name.ValueStringPointer(),
@@ -145,8 +250,21 @@ func (r *authorizationResource) Create(ctx context.Context, req resource.CreateR
 		payload.Parameters = &parameters
 	}
 
+	if !plan.SensitiveParameters.IsNull() {
+		sensitiveParameters := utils.ExpandParameters(plan.SensitiveParameters.UnderlyingValue(), path.Root("sensitive_parameters"), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		payload.SensitiveParameters = &sensitiveParameters
+	}
+
+	r.addWriteOnlyParameters(ctx, req.Config, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create new authorization
-	authorization, err := r.client.CreateAuthorization(int(plan.AuthorizationTypeId.ValueInt64()), payload)
+	authorization, err := client.CreateAuthorization(ctx, int(plan.AuthorizationTypeId.ValueInt64()), payload)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating authorization",
@@ -177,8 +295,14 @@ func (r *authorizationResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get refreshed authorization value from Adverity
-	authorization, err := r.client.ReadAuthorization(int(state.AuthorizationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
+	authorization, err := client.ReadAuthorization(ctx, int(state.AuthorizationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Adverity authorization",
@@ -199,30 +323,45 @@ func (r *authorizationResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *authorizationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan authorizationResourceModel
+	// Retrieve values from plan and prior state
+	var plan, state authorizationResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Generate API request body from plan
-	payload := &adverity.AuthorizationConfig{
-		Name:    plan.Name.ValueStringPointer(),
-		StackID: plan.StackID.ValueInt64Pointer(),
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !plan.Parameters.IsNull() {
-		parameters := utils.ExpandParameters(plan.Parameters.UnderlyingValue(), path.Root("parameters"), &resp.Diagnostics)
+	// Generate API request body with the changes from the prior state to the plan
+	payload := utils.PatchPayload(r.updatePayload(state, &resp.Diagnostics), r.updatePayload(plan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only parameters are not stored in the state, so they are only sent
+	// again (e.g. to rotate credentials) when their version changes
+	if !plan.ParametersWoVersion.Equal(state.ParametersWoVersion) {
+		r.addWriteOnlyParameters(ctx, req.Config, payload, &resp.Diagnostics)
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		payload.Parameters = &parameters
 	}
 
-	// Update existing authorization
-	authorization, err := r.client.UpdateAuthorization(int(plan.AuthorizationTypeId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
+	// Update existing authorization, or read it if nothing changed
+	var authorization *adverity.AuthorizationResponse
+	var err error
+	if utils.EmptyPatch(payload) {
+		authorization, err = client.ReadAuthorization(ctx, int(plan.AuthorizationTypeId.ValueInt64()), int(plan.ID.ValueInt64()))
+	} else {
+		authorization, err = client.UpdateAuthorization(ctx, int(plan.AuthorizationTypeId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Adverity authorization",
@@ -252,8 +391,14 @@ func (r *authorizationResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing authorization
-	_, err := r.client.DeleteAuthorization(int(state.AuthorizationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
+	_, err := client.DeleteAuthorization(ctx, int(state.AuthorizationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Adverity authorization",
@@ -264,8 +409,11 @@ func (r *authorizationResource) Delete(ctx context.Context, req resource.DeleteR
 }
 
 func (r *authorizationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
 	// Split the composite import ID (<authorization_type_id>:<id>) into its parts
-	parts := utils.SplitImportParts(req.ID, 2, "<authorization_type_id>:<id>", &resp.Diagnostics)
+	parts := utils.SplitImportParts(importID, 2, "<authorization_type_id>:<id>", &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -281,3 +429,49 @@ func (r *authorizationResource) ImportState(ctx context.Context, req resource.Im
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("authorization_type_id"), authTypeId)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
+
+// MoveState migrates the state of the deprecated adverity_connection resource with a moved block.
+// The objects are the same in Adverity, so only the state is converted and no request is sent.
+func (r *authorizationResource) MoveState(ctx context.Context) []resource.StateMover {
+	var connectionSchema resource.SchemaResponse
+	(&connectionResource{}).Schema(ctx, resource.SchemaRequest{}, &connectionSchema)
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &connectionSchema.Schema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				// Other resources are left to the other state movers or rejected by Terraform
+				if req.SourceTypeName != "adverity_connection" || !strings.HasSuffix(req.SourceProviderAddress, "/adverity") {
+					return
+				}
+				if req.SourceState == nil {
+					resp.Diagnostics.AddError(
+						"Unable to move adverity_connection state",
+						"The state of the connection could not be read. Please report this issue to the provider developers.",
+					)
+					return
+				}
+
+				var connection connectionResourceModel
+				resp.Diagnostics.Append(req.SourceState.Get(ctx, &connection)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, authorizationResourceModel{
+					AuthorizationTypeId: connection.ConnectionTypeId,
+					ID:                  connection.ID,
+					Name:                connection.Name,
+					StackID:             connection.StackID,
+					IsAuthorized:        connection.IsAuthorized,
+					Parameters:          connection.Parameters,
+					SensitiveParameters: types.DynamicNull(),
+					ParametersWo:        types.DynamicNull(),
+					ParametersWoVersion: types.Int64Null(),
+					Instance:            connection.Instance,
+					LastUpdated:         connection.LastUpdated,
+				})...)
+			},
+		},
+	}
+}
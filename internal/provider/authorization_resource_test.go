@@ -0,0 +1,133 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccAuthorizationResource(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(server, "adverity_authorization", "connection"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_authorization.test", tfjsonpath.New("name"), knownvalue.StringExact("Google Ads")),
+					statecheck.ExpectKnownValue("adverity_authorization.test", tfjsonpath.New("is_authorized"), knownvalue.Bool(false)),
+					statecheck.CompareValuePairs(
+						"adverity_authorization.test", tfjsonpath.New("stack_id"),
+						"adverity_workspace.test", tfjsonpath.New("id"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:            "adverity_authorization.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateId("adverity_authorization.test", "authorization_type_id", "id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "parameters"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads EMEA"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_authorization.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_authorization.test", tfjsonpath.New("name"), knownvalue.StringExact("Google Ads EMEA")),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAuthorizationResource_moveFromConnection(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+	var id int64
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// moved blocks between resource types require Terraform 1.8
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		CheckDestroy: testAccCheckDestroyed(server, "adverity_authorization", "connection"),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccWorkspaceConfig + fmt.Sprintf(`
+resource "adverity_connection" "test" {
+  connection_type_id = %[1]d
+  name               = "Google Ads"
+  stack_id           = adverity_workspace.test.id
+
+  parameters = {
+    customer_id = "123-456-7890"
+  }
+}
+`, testAccAuthorizationTypeID),
+				Check: func(s *terraform.State) (err error) {
+					id, err = testAccResourceID(s, "adverity_connection.test")
+					return err
+				},
+			},
+			// The moved block migrates the state without replacing the connection
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") + `
+moved {
+  from = adverity_connection.test
+  to   = adverity_authorization.test
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_authorization.test", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: func(s *terraform.State) error {
+					moved, err := testAccResourceID(s, "adverity_authorization.test")
+					if err == nil && moved != id {
+						err = fmt.Errorf("authorization id = %d, want the id %d of the connection", moved, id)
+					}
+					return err
+				},
+			},
+		},
+	})
+}
+
+func testAccAuthorizationResourceConfig(name string) string {
+	return fmt.Sprintf(`
+resource "adverity_authorization" "test" {
+  authorization_type_id = %[2]d
+  name                  = %[1]q
+  stack_id              = adverity_workspace.test.id
+
+  parameters = {
+    customer_id = "123-456-7890"
+  }
+}
+`, name, testAccAuthorizationTypeID)
+}
@@ -8,9 +8,6 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
-
-	"terraform-provider-adverity/internal/adverity"
-
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -29,13 +26,14 @@ func NewAuthorizationTypeDataSource() datasource.DataSource {
 
 // authorizationTypeDataSource is the data source implementation.
 type authorizationTypeDataSource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // authorizationTypeDataSourceModel maps the data source schema data.
 type authorizationTypeDataSourceModel struct {
 	SearchTerm types.String `tfsdk:"search_term"`
 	Results    types.Map    `tfsdk:"results"`
+	Instance   types.String `tfsdk:"instance"`
 }
 
 // Configure adds the provider configured client to the data source.
@@ -46,17 +44,17 @@ func (d *authorizationTypeDataSource) Configure(_ context.Context, req datasourc
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.providerData = data
 }
 
 // Metadata returns the data source type name.
@@ -69,6 +67,7 @@ func (d *authorizationTypeDataSource) Schema(_ context.Context, _ datasource.Sch
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of authorization types.",
 		Attributes: map[string]schema.Attribute{
+			"instance": instanceDataSourceAttribute(),
 			"search_term": schema.StringAttribute{
 				Description: "Search term to filter on.",
 				Required:    true,
@@ -92,7 +91,13 @@ func (d *authorizationTypeDataSource) Read(ctx context.Context, req datasource.R
 		return
 	}
 
-	authorizationTypes, err := d.client.QueryAuthorizationTypes(data.SearchTerm.ValueString())
+	// Select the client of the instance
+	client := d.providerData.client(data.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	authorizationTypes, err := client.QueryAuthorizationTypes(ctx, data.SearchTerm.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error querying Adverity authorization types",
@@ -0,0 +1,157 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Refresh strategies of the provider.
+const (
+	refreshStrategyIndividual = "individual"
+	refreshStrategyBatch      = "batch"
+)
+
+// batchRefresh serves the reads of datastreams, destinations and destination mappings from lists of the
+// objects, which are requested by the first read of an object of the workspace (or of a mapping of the
+// destination) and kept for the run. Each listed object is served once, so later reads of the object in
+// the same run, e.g. after it was updated, request it again. The zero value is ready to use.
+type batchRefresh struct {
+	mu    sync.Mutex
+	lists map[batchListKey]*batchList
+}
+
+// batchListKey identifies a list, e.g. the datastreams of a workspace of an instance.
+type batchListKey struct {
+	client *adverity.Client
+	kind   string
+	// parent is the ID of the workspace, or of the destination of mappings
+	parent int64
+	// typeId is the type of listed destinations, which are listed per type
+	typeId int64
+}
+
+// batchList holds the listed objects by ID. It is loaded once, concurrent reads wait for it.
+type batchList struct {
+	once    sync.Once
+	objects map[int64]any
+}
+
+// take removes the object with the ID from the list and returns it, loading the list with the first call.
+// It returns nil if the list does not contain the object or could not be loaded.
+func (b *batchRefresh) take(ctx context.Context, key batchListKey, id int64, load func() (map[int64]any, error)) any {
+	b.mu.Lock()
+	if b.lists == nil {
+		b.lists = make(map[batchListKey]*batchList)
+	}
+	list, ok := b.lists[key]
+	if !ok {
+		list = &batchList{}
+		b.lists[key] = list
+	}
+	b.mu.Unlock()
+
+	list.once.Do(func() {
+		objects, err := load()
+		if err != nil {
+			// The objects are read individually instead
+			tflog.Warn(ctx, "Could not list the Adverity objects for the batch refresh", map[string]any{"kind": key.kind, "parent": key.parent, "error": err.Error()})
+		}
+		b.mu.Lock()
+		list.objects = objects
+		b.mu.Unlock()
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	object, ok := list.objects[id]
+	if !ok {
+		return nil
+	}
+	delete(list.objects, id)
+	return object
+}
+
+// takeListed returns the object with the ID from the list, and whether the list contained it.
+func takeListed[T any](ctx context.Context, b *batchRefresh, key batchListKey, id int64, list func() ([]T, error), objectId func(T) int64) (*T, bool) {
+	object, ok := b.take(ctx, key, id, func() (map[int64]any, error) {
+		objects, err := list()
+		if err != nil {
+			return nil, err
+		}
+		byId := make(map[int64]any, len(objects))
+		for i := range objects {
+			byId[objectId(objects[i])] = &objects[i]
+		}
+		return byId, nil
+	}).(*T)
+	return object, ok
+}
+
+// readDatastream reads a datastream, from the list of datastreams of its workspace if refresh_strategy is batch.
+func (p *providerData) readDatastream(ctx context.Context, client *adverity.Client, stackId, datastreamTypeId, datastreamId types.Int64) (*adverity.DatastreamResponse, error) {
+	if p.batch != nil && !stackId.IsNull() && !stackId.IsUnknown() {
+		key := batchListKey{client: client, kind: "datastreams", parent: stackId.ValueInt64()}
+		datastream, ok := takeListed(ctx, p.batch, key, datastreamId.ValueInt64(), func() ([]adverity.DatastreamResponse, error) {
+			return client.ListDatastreams(ctx, int(stackId.ValueInt64()))
+		}, func(d adverity.DatastreamResponse) int64 { return d.ID })
+		if ok && datastream.DatastreamTypeID == datastreamTypeId.ValueInt64() {
+			return datastream, nil
+		}
+	}
+
+	return client.ReadDatastream(ctx, int(datastreamTypeId.ValueInt64()), int(datastreamId.ValueInt64()))
+}
+
+// readDestination reads a destination, from the list of destinations of its type in its workspace if refresh_strategy
+// is batch. Since list rows may lack parameters of the type, a listed destination is only used if it contains all
+// the fields, e.g. the parameters in the state, and read individually otherwise.
+func (p *providerData) readDestination(ctx context.Context, client *adverity.Client, stackId, destinationTypeId, destinationId types.Int64, fields []string) (*adverity.DestinationResponse, error) {
+	if p.batch != nil && !stackId.IsNull() && !stackId.IsUnknown() {
+		key := batchListKey{client: client, kind: "destinations", parent: stackId.ValueInt64(), typeId: destinationTypeId.ValueInt64()}
+		destination, ok := takeListed(ctx, p.batch, key, destinationId.ValueInt64(), func() ([]adverity.DestinationResponse, error) {
+			return client.ListDestinationsOfType(ctx, int(destinationTypeId.ValueInt64()), int(stackId.ValueInt64()))
+		}, func(d adverity.DestinationResponse) int64 { return d.ID })
+		if ok && destination.StackID == stackId.ValueInt64() && hasFields(destination.Fields, fields) {
+			return destination, nil
+		}
+	}
+
+	return client.ReadDestination(ctx, int(destinationTypeId.ValueInt64()), int(destinationId.ValueInt64()))
+}
+
+// destinationFields are the fields of a destination response which the destination resources read.
+var destinationFields = []string{"name", "stack", "headers_formatting"}
+
+// hasFields returns whether a response contains all the fields.
+func hasFields(response map[string]json.RawMessage, fields []string) bool {
+	for _, field := range fields {
+		if _, ok := response[field]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// readDestinationMapping reads a destination mapping, from the list of mappings of its destination if refresh_strategy is batch.
+func (p *providerData) readDestinationMapping(ctx context.Context, client *adverity.Client, destinationTypeId, destinationId, destinationMappingId types.Int64) (*adverity.DestinationMappingResponse, error) {
+	if p.batch != nil {
+		key := batchListKey{client: client, kind: "mappings", parent: destinationId.ValueInt64()}
+		mapping, ok := takeListed(ctx, p.batch, key, destinationMappingId.ValueInt64(), func() ([]adverity.DestinationMappingResponse, error) {
+			return client.ListDestinationMappings(ctx, int(destinationTypeId.ValueInt64()), int(destinationId.ValueInt64()))
+		}, func(m adverity.DestinationMappingResponse) int64 { return m.ID })
+		if ok {
+			return mapping, nil
+		}
+	}
+
+	return client.ReadDestinationMapping(ctx, int(destinationTypeId.ValueInt64()), int(destinationId.ValueInt64()), int(destinationMappingId.ValueInt64()))
+}
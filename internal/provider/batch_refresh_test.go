@@ -0,0 +1,101 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/adverity/fakeserver"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestBatchRefresh(t *testing.T) {
+	server := fakeserver.New()
+	t.Cleanup(server.Close)
+	server.AddDatastreamType(fakeserver.Type{ID: testAccDatastreamTypeID, Name: "Google Ads Insights", Slug: "google-ads-insights"})
+	server.AddTargetType(fakeserver.Type{ID: testAccDestinationTypeID, Name: "Google BigQuery", Slug: "bigquery"})
+	server.AddTargetType(fakeserver.Type{ID: testAccDestinationTypeID + 1, Name: "Snowflake", Slug: "snowflake"})
+
+	client, err := adverity.NewClient(t.Context(), server.URL, adverity.WithToken(fakeserver.DefaultToken), adverity.WithCACertPEM([]byte(server.CertificatePEM())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, stack := "Campaigns", fakeserver.RootStackID
+	var datastreams []types.Int64
+	for range 5 {
+		datastream, err := client.CreateDatastream(t.Context(), testAccDatastreamTypeID, &adverity.DatastreamCreateConfig{Name: &name, StackID: &stack})
+		if err != nil {
+			t.Fatal(err)
+		}
+		datastreams = append(datastreams, types.Int64Value(datastream.ID))
+	}
+	createDestination := func(typeId int) int64 {
+		destination, err := client.CreateDestination(t.Context(), typeId, &adverity.DestinationConfig{Name: &name, StackID: &stack})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return destination.ID
+	}
+	destination, partial, other := createDestination(testAccDestinationTypeID), createDestination(testAccDestinationTypeID), createDestination(testAccDestinationTypeID+1)
+	mappedId := datastreams[0].ValueInt64()
+	mapping, err := client.CreateDestinationMapping(t.Context(), testAccDestinationTypeID, int(destination), &adverity.DestinationMappingConfig{DatastreamId: &mappedId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := &providerData{defaultClient: client, batch: &batchRefresh{}}
+	stackId, datastreamTypeId, destinationTypeId := types.Int64Value(stack), types.Int64Value(testAccDatastreamTypeID), types.Int64Value(testAccDestinationTypeID)
+	start := len(server.Requests())
+
+	// Parallel reads of a workspace share a single list request
+	var wg sync.WaitGroup
+	for _, id := range datastreams {
+		wg.Go(func() {
+			datastream, err := data.readDatastream(t.Context(), client, stackId, datastreamTypeId, id)
+			if err != nil || datastream.ID != id.ValueInt64() || datastream.Name != name {
+				t.Errorf("readDatastream(%s) = %+v, %v, want the datastream", id, datastream, err)
+			}
+		})
+	}
+	wg.Wait()
+	if _, err := data.readDestination(t.Context(), client, stackId, destinationTypeId, types.Int64Value(destination), destinationFields); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := data.readDestinationMapping(t.Context(), client, destinationTypeId, types.Int64Value(destination), types.Int64Value(mapping.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A listed destination without a parameter of the state is read individually
+	if _, err := data.readDestination(t.Context(), client, stackId, destinationTypeId, types.Int64Value(partial), append(slices.Clone(destinationFields), "dataset")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Destinations are listed per type, so a destination of another type is not served as the type
+	if got, err := data.readDestination(t.Context(), client, stackId, destinationTypeId, types.Int64Value(other), destinationFields); err == nil {
+		t.Errorf("readDestination(%d) = %+v, want an error for the destination of another type", other, got)
+	}
+
+	// A datastream is only read from the list once, e.g. a read after an update requests it again
+	if _, err := data.readDatastream(t.Context(), client, stackId, datastreamTypeId, datastreams[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /api/datastreams/",
+		"GET /api/target-types/30/targets/",
+		"GET /api/target-types/30/targets/" + strconv.FormatInt(destination, 10) + "/mappings/",
+		"GET /api/target-types/30/targets/" + strconv.FormatInt(partial, 10) + "/",
+		"GET /api/target-types/30/targets/" + strconv.FormatInt(other, 10) + "/",
+		"GET /api/datastream-types/20/datastreams/" + datastreams[0].String() + "/",
+	}
+	if got := server.Requests()[start:]; !slices.Equal(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
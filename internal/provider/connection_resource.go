@@ -11,6 +11,7 @@ import (
 	"terraform-provider-adverity/internal/adverity"
 	"terraform-provider-adverity/internal/provider/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -24,6 +25,7 @@ var (
 	_ resource.Resource                = &connectionResource{}
 	_ resource.ResourceWithConfigure   = &connectionResource{}
 	_ resource.ResourceWithImportState = &connectionResource{}
+	_ resource.ResourceWithModifyPlan  = &connectionResource{}
 )
 
 // NewConnectionResource is a helper function to simplify the provider implementation.
@@ -33,7 +35,7 @@ func NewConnectionResource() resource.Resource {
 
 // connectionResource is the resource implementation.
 type connectionResource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // connectionResourceModel maps the resource schema data.
@@ -44,6 +46,7 @@ type connectionResourceModel struct {
 	StackID          types.Int64   `tfsdk:"stack_id"`
 	IsAuthorized     types.Bool    `tfsdk:"is_authorized"`
 	Parameters       types.Dynamic `tfsdk:"parameters"`
+	Instance         types.String  `tfsdk:"instance"`
 	LastUpdated      types.String  `tfsdk:"last_updated"`
 }
 
@@ -54,6 +57,42 @@ func (r *connectionResource) refreshState(connection *adverity.AuthorizationResp
 	state.IsAuthorized = types.BoolValue(connection.IsAuthorized)
 }
 
+// ModifyPlan validates the parameters against the field metadata of the connection type.
+func (r *connectionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var typeId types.Int64
+	var parameters types.Dynamic
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("connection_type_id"), &typeId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+	if resp.Diagnostics.HasError() || typeId.IsUnknown() {
+		return
+	}
+
+	// Validate against the field metadata of the planned instance
+	client := r.providerData.planClient(ctx, req.Plan, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+
+	readFields := func() (map[string]adverity.FieldMetadata, error) {
+		return client.ReadAuthorizationFields(ctx, int(typeId.ValueInt64()))
+	}
+	utils.ValidateParametersWithMetadata([]utils.ParameterSource{utils.ParametersSource(parameters)}, readFields, adverity.PayloadFields(adverity.AuthorizationConfig{}), &resp.Diagnostics)
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *connectionResource) updatePayload(model connectionResourceModel, diags *diag.Diagnostics) *adverity.AuthorizationConfig {
+	return &adverity.AuthorizationConfig{
+		Name:       model.Name.ValueStringPointer(),
+		StackID:    model.StackID.ValueInt64Pointer(),
+		Parameters: utils.ExpandDynamicParameters(model.Parameters, path.Root("parameters"), diags),
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *connectionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -62,18 +101,18 @@ func (r *connectionResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
 }
 
 // Metadata returns the resource type name.
@@ -84,8 +123,9 @@ func (r *connectionResource) Metadata(_ context.Context, req resource.MetadataRe
 // Schema defines the schema for the resource.
 func (r *connectionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description:        "Manages a connection.",
-		DeprecationMessage: "Use 'authorization' resource instead.",
+		Description: "Manages a connection.",
+		DeprecationMessage: "Use the adverity_authorization resource instead. With Terraform 1.8 and later, a moved block from " +
+			"adverity_connection.<name> to adverity_authorization.<name> migrates the state without recreating the connection.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Description: "Numeric identifier of the connection.",
@@ -94,6 +134,7 @@ func (r *connectionResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"instance": instanceAttribute(),
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the connection.",
 				Computed:    true,
@@ -101,6 +142,9 @@ func (r *connectionResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"connection_type_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the connection type.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description: "Name of the connection.",
@@ -109,6 +153,9 @@ func (r *connectionResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"stack_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the workspace.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					stackRequiresReplace("connection"),
+				},
 			},
 			"is_authorized": schema.BoolAttribute{
 				Description: "Whether the connection is authorized.",
@@ -132,6 +179,12 @@ func (r *connectionResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	payload := &adverity.AuthorizationConfig{
 		Name:    plan.Name.ValueStringPointer(),
@@ -147,7 +200,7 @@ func (r *connectionResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 
 	// Create new connection
-	connection, err := r.client.CreateAuthorization(int(plan.ConnectionTypeId.ValueInt64()), payload)
+	connection, err := client.CreateAuthorization(ctx, int(plan.ConnectionTypeId.ValueInt64()), payload)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating connection",
@@ -178,8 +231,14 @@ func (r *connectionResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get refreshed connection value from Adverity
-	connection, err := r.client.ReadAuthorization(int(state.ConnectionTypeId.ValueInt64()), int(state.ID.ValueInt64()))
+	connection, err := client.ReadAuthorization(ctx, int(state.ConnectionTypeId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Adverity connection",
@@ -200,30 +259,36 @@ func (r *connectionResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *connectionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan connectionResourceModel
+	// Retrieve values from plan and prior state
+	var plan, state connectionResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Generate API request body from plan
-	payload := &adverity.AuthorizationConfig{
-		Name:    plan.Name.ValueStringPointer(),
-		StackID: plan.StackID.ValueInt64Pointer(),
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !plan.Parameters.IsNull() {
-		parameters := utils.ExpandParameters(plan.Parameters.UnderlyingValue(), path.Root("parameters"), &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		payload.Parameters = &parameters
+	// Generate API request body with the changes from the prior state to the plan
+	payload := utils.PatchPayload(r.updatePayload(state, &resp.Diagnostics), r.updatePayload(plan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Update existing connection
-	connection, err := r.client.UpdateAuthorization(int(plan.ConnectionTypeId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
+	// Update existing connection, or read it if nothing changed
+	var connection *adverity.AuthorizationResponse
+	var err error
+	if utils.EmptyPatch(payload) {
+		connection, err = client.ReadAuthorization(ctx, int(plan.ConnectionTypeId.ValueInt64()), int(plan.ID.ValueInt64()))
+	} else {
+		connection, err = client.UpdateAuthorization(ctx, int(plan.ConnectionTypeId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Adverity connection",
@@ -253,8 +318,14 @@ func (r *connectionResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing connection
-	_, err := r.client.DeleteAuthorization(int(state.ConnectionTypeId.ValueInt64()), int(state.ID.ValueInt64()))
+	_, err := client.DeleteAuthorization(ctx, int(state.ConnectionTypeId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Adverity connection",
@@ -265,8 +336,11 @@ func (r *connectionResource) Delete(ctx context.Context, req resource.DeleteRequ
 }
 
 func (r *connectionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
 	// Split the composite import ID (<connection_type_id>:<id>) into its parts
-	parts := utils.SplitImportParts(req.ID, 2, "<connection_type_id>:<id>", &resp.Diagnostics)
+	parts := utils.SplitImportParts(importID, 2, "<connection_type_id>:<id>", &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
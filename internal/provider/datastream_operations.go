@@ -0,0 +1,165 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The requests shared by the datastream resource and the typed datastream resources. Each adds an
+// error to diags and returns nil on failure. The callers hold the lock of the datastream
+// (see adverity.Client.LockDatastream) around the requests which change it.
+
+// createDatastream creates a datastream of the type from payload, or adopts the existing datastream with the
+// same name (see createOrAdopt), in which case existing is true and the caller updates it with adoptDatastream.
+// The create request carries a client token in the description, which is replaced by the description of
+// payload once the datastream is created. If that fails, the created datastream is returned with a warning,
+// so it is kept in the state and the next apply sets the description.
+func createDatastream(ctx context.Context, client *adverity.Client, typeId, stackId types.Int64, name types.String, adoptExisting types.Bool, payload *adverity.DatastreamCreateConfig, diags *diag.Diagnostics) (datastream *adverity.DatastreamResponse, existing bool) {
+	token := adverity.NewClientToken()
+	tagged := *payload
+	tagged.Description = tagDescription(payload.Description, token)
+
+	datastream, existing = createOrAdopt(ctx, "datastream", name.ValueString(), adoptExisting.ValueBool(),
+		func() (*adverity.DatastreamResponse, error) {
+			return client.CreateDatastream(ctx, int(typeId.ValueInt64()), &tagged)
+		},
+		func() ([]adverity.DatastreamResponse, error) {
+			return client.FindDatastreams(ctx, int(stackId.ValueInt64()), int(typeId.ValueInt64()), name.ValueString())
+		},
+		func(d adverity.DatastreamResponse) int64 { return d.ID },
+		func(d adverity.DatastreamResponse) bool { return strings.Contains(d.Description, token) },
+		diags,
+	)
+	if diags.HasError() || existing {
+		return datastream, existing
+	}
+
+	// Replace the client token with the planned description
+	description := ""
+	if payload.Description != nil {
+		description = *payload.Description
+	}
+	updated, err := client.UpdateDatastream(ctx, int(datastream.DatastreamTypeID), int(datastream.ID), &adverity.DatastreamUpdateConfig{Description: &description})
+	if err != nil {
+		// Keep the created datastream with the planned description, so the state matches the plan
+		// and the next refresh detects the client token left in the description
+		datastream.Description = description
+		diags.AddWarning(
+			"Error updating Adverity datastream",
+			fmt.Sprintf("Could not replace the client token in the description of the created datastream with ID %d, unexpected error: %s\n\n"+
+				"The datastream is kept and the next apply sets its description.", datastream.ID, err),
+		)
+		return datastream, false
+	}
+	return updated, false
+}
+
+// adoptDatastream updates an adopted datastream to the plan, the schedules before the datastream (see updateDatastream).
+func adoptDatastream(ctx context.Context, client *adverity.Client, datastream *adverity.DatastreamResponse, schedulePayload *adverity.DatastreamScheduleConfig, payload *adverity.DatastreamUpdateConfig, diags *diag.Diagnostics) *adverity.DatastreamResponse {
+	if _, err := client.UpdateDatastreamSchedule(ctx, int(datastream.ID), schedulePayload); err != nil {
+		diags.AddError(
+			"Error updating Adverity datastream schedule",
+			"Could not update the schedules of the adopted datastream, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+
+	datastream, err := client.UpdateDatastream(ctx, int(datastream.DatastreamTypeID), int(datastream.ID), payload)
+	if err != nil {
+		diags.AddError(
+			"Error updating Adverity datastream",
+			"Could not update the adopted datastream, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	return datastream
+}
+
+// removeDefaultSchedules removes the schedule Adverity adds to datastreams created without schedules,
+// so the datastream matches a configuration without schedules, and sets the enabled flag along.
+func removeDefaultSchedules(ctx context.Context, client *adverity.Client, datastream *adverity.DatastreamResponse, enabled types.Bool, diags *diag.Diagnostics) *adverity.DatastreamResponse {
+	if len(datastream.Schedules) == 0 {
+		return datastream
+	}
+
+	emptySchedules := make([]adverity.Schedule, 0)
+	schedulePayload := &adverity.DatastreamScheduleConfig{
+		Schedules: &emptySchedules,
+		Enabled:   enabled.ValueBoolPointer(),
+	}
+	if _, err := client.UpdateDatastreamSchedule(ctx, int(datastream.ID), schedulePayload); err != nil {
+		diags.AddError(
+			"Error removing default schedule from datastream",
+			"Could not remove default schedule, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+
+	read, err := client.ReadDatastream(ctx, int(datastream.DatastreamTypeID), int(datastream.ID))
+	if err != nil {
+		diags.AddError(
+			"Error reading Adverity datastream",
+			"Could not read datastream, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	// The schedule update does not change the description, which keeps the planned description
+	// if its client token could not be replaced (see createDatastream)
+	read.Description = datastream.Description
+	return read
+}
+
+// readDatastreamState reads a datastream to refresh the state of a resource (see readDatastream).
+func (p *providerData) readDatastreamState(ctx context.Context, client *adverity.Client, stackId, typeId, id types.Int64, diags *diag.Diagnostics) *adverity.DatastreamResponse {
+	datastream, err := p.readDatastream(ctx, client, stackId, typeId, id)
+	if err != nil {
+		diags.AddError(
+			"Error reading Adverity datastream",
+			"Could not read datastream, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	return datastream
+}
+
+// updateDatastream sends the changes of a datastream. The enabled flag and the schedules are only accepted by
+// the schedule endpoint, which is only called if schedulePayload changes any of them. Its response is ignored,
+// since it lacks fields needed for a refresh (e.g. stack_id), and the changes are reflected in the response
+// of the datastream update which follows. If payload contains no changes, the datastream is read instead.
+func updateDatastream(ctx context.Context, client *adverity.Client, typeId, id types.Int64, schedulePayload *adverity.DatastreamScheduleConfig, payload *adverity.DatastreamUpdateConfig, diags *diag.Diagnostics) *adverity.DatastreamResponse {
+	if schedulePayload.Enabled != nil || schedulePayload.Schedules != nil {
+		if _, err := client.UpdateDatastreamSchedule(ctx, int(id.ValueInt64()), schedulePayload); err != nil {
+			diags.AddError(
+				"Error updating Adverity datastream schedule",
+				"Could not update datastream schedule, unexpected error: "+err.Error(),
+			)
+			return nil
+		}
+	}
+
+	var datastream *adverity.DatastreamResponse
+	var err error
+	if utils.EmptyPatch(payload) {
+		datastream, err = client.ReadDatastream(ctx, int(typeId.ValueInt64()), int(id.ValueInt64()))
+	} else {
+		datastream, err = client.UpdateDatastream(ctx, int(typeId.ValueInt64()), int(id.ValueInt64()), payload)
+	}
+	if err != nil {
+		diags.AddError(
+			"Error updating Adverity datastream",
+			"Could not update datastream, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	return datastream
+}
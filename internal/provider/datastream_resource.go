@@ -6,15 +6,20 @@ package provider
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
 	"time"
 
 	"terraform-provider-adverity/internal/adverity"
 	"terraform-provider-adverity/internal/provider/utils"
 	"terraform-provider-adverity/internal/provider/validators"
+	"terraform-provider-adverity/internal/schedule"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -23,14 +28,18 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &datastreamResource{}
-	_ resource.ResourceWithConfigure   = &datastreamResource{}
-	_ resource.ResourceWithImportState = &datastreamResource{}
+	_ resource.Resource                     = &datastreamResource{}
+	_ resource.ResourceWithConfigure        = &datastreamResource{}
+	_ resource.ResourceWithImportState      = &datastreamResource{}
+	_ resource.ResourceWithValidateConfig   = &datastreamResource{}
+	_ resource.ResourceWithConfigValidators = &datastreamResource{}
+	_ resource.ResourceWithModifyPlan       = &datastreamResource{}
 )
 
 // NewDatastreamResource is a helper function to simplify the provider implementation.
@@ -40,16 +49,20 @@ func NewDatastreamResource() resource.Resource {
 
 // datastreamResource is the resource implementation.
 type datastreamResource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 type datastreamScheduleModel struct {
+	Key                types.String `tfsdk:"key"`
+	ID                 types.Int64  `tfsdk:"id"`
+	CronExpression     types.String `tfsdk:"cron_expression"`
 	CronPreset         types.String `tfsdk:"cron_preset"`
 	CronType           types.String `tfsdk:"cron_type"`
 	CronInterval       types.Int64  `tfsdk:"cron_interval"`
 	CronIntervalStart  types.Int64  `tfsdk:"cron_interval_start"`
 	CronStartOfDay     types.String `tfsdk:"cron_start_of_day"`
 	TimeRangePreset    types.Int64  `tfsdk:"time_range_preset"`
+	TimeRange          types.String `tfsdk:"time_range"`
 	DeltaType          types.Int64  `tfsdk:"delta_type"`
 	DeltaInterval      types.Int64  `tfsdk:"delta_interval"`
 	DeltaIntervalStart types.Int64  `tfsdk:"delta_interval_start"`
@@ -70,6 +83,7 @@ type datastreamResourceModel struct {
 	StackID             types.Int64               `tfsdk:"stack_id"`
 	AuthID              types.Int64               `tfsdk:"auth_id"`
 	Schedules           []datastreamScheduleModel `tfsdk:"schedule"`
+	ManageSchedules     types.Bool                `tfsdk:"manage_schedules"`
 	Enabled             types.Bool                `tfsdk:"enabled"`
 	DataType            types.String              `tfsdk:"datatype"`
 	RetentionType       types.Int64               `tfsdk:"retention_type"`
@@ -78,6 +92,8 @@ type datastreamResourceModel struct {
 	ExtractNameKeys     types.String              `tfsdk:"extract_name_keys"`
 	IsInsightsMediaplan types.Bool                `tfsdk:"is_insights_mediaplan"`
 	Parameters          types.Dynamic             `tfsdk:"parameters"`
+	AdoptExisting       types.Bool                `tfsdk:"adopt_existing"`
+	Instance            types.String              `tfsdk:"instance"`
 	LastUpdated         types.String              `tfsdk:"last_updated"`
 }
 
@@ -95,93 +111,705 @@ func (r *datastreamResource) refreshState(datastream *adverity.DatastreamRespons
 	state.ExtractNameKeys = types.StringValue(datastream.ExtractNameKeys)
 	state.Enabled = types.BoolValue(datastream.Enabled)
 
-	// Workaround to preserve the order of schedules created by Terraform. Adverity does
-	// not guarantee proper schedule ordering in POST, PATCH or GET responses.
-	// This ensures that Terraform doesn't detect spurious changes to the schedules on
-	// every plan/apply and avoids inconsistent results.
-	sort.Slice(datastream.Schedules, func(i, j int) bool {
-		return *datastream.Schedules[i].ID < *datastream.Schedules[j].ID
+	// Schedules managed by a separate adverity_datastream_schedule resource are not tracked here
+	if state.ManageSchedules.IsNull() || state.ManageSchedules.ValueBool() {
+		state.Schedules = flattenSchedules(datastream.Schedules, state.Schedules)
+	}
+}
+
+// flattenSchedule maps a schedule returned by the API to a schedule block.
+func flattenSchedule(schedule adverity.Schedule) datastreamScheduleModel {
+	return datastreamScheduleModel{
+		ID:                 types.Int64PointerValue(schedule.ID),
+		CronPreset:         types.StringPointerValue(schedule.CronPreset),
+		CronType:           types.StringPointerValue(schedule.CronType),
+		CronInterval:       types.Int64PointerValue(schedule.CronInterval),
+		CronIntervalStart:  types.Int64PointerValue(schedule.CronIntervalStart),
+		CronStartOfDay:     types.StringPointerValue(schedule.CronStartOfDay),
+		TimeRangePreset:    types.Int64PointerValue(schedule.TimeRangePreset),
+		TimeRange:          utils.FlattenEnum(adverity.TimeRanges, schedule.TimeRangePreset),
+		DeltaType:          types.Int64PointerValue(schedule.DeltaType),
+		DeltaInterval:      types.Int64PointerValue(schedule.DeltaInterval),
+		DeltaIntervalStart: types.Int64PointerValue(schedule.DeltaIntervalStart),
+		DeltaStartOfDay:    types.StringPointerValue(schedule.DeltaStartOfDay),
+		FixedStart:         types.StringPointerValue(schedule.FixedStart),
+		FixedEnd:           types.StringPointerValue(schedule.FixedEnd),
+		OffsetDays:         types.Int64PointerValue(schedule.OffsetDays),
+		NotBeforeDate:      types.StringPointerValue(schedule.NotBeforeDate),
+		NotBeforeTime:      types.StringPointerValue(schedule.NotBeforeTime),
+	}
+}
+
+// flattenSchedules maps the schedules returned by the API to the schedule blocks.
+//
+// Adverity does not guarantee proper schedule ordering in POST, PATCH or GET responses,
+// so the schedules are matched to the prior schedule blocks instead: by ID for known
+// schedules and by their configured values for schedules which were just created.
+// This keeps the keys stable and avoids spurious diffs. Schedules unknown to Terraform
+// (e.g. after an import) are added without a key.
+func flattenSchedules(apiSchedules []adverity.Schedule, prior []datastreamScheduleModel) []datastreamScheduleModel {
+	remaining := slices.Clone(apiSchedules)
+	sort.Slice(remaining, func(i, j int) bool {
+		return *remaining[i].ID < *remaining[j].ID
 	})
 
-	var schedules []datastreamScheduleModel
-	for _, schedule := range datastream.Schedules {
-		schedules = append(schedules, datastreamScheduleModel{
-			CronPreset:         types.StringPointerValue(schedule.CronPreset),
-			CronType:           types.StringPointerValue(schedule.CronType),
-			CronInterval:       types.Int64PointerValue(schedule.CronInterval),
-			CronIntervalStart:  types.Int64PointerValue(schedule.CronIntervalStart),
-			CronStartOfDay:     types.StringPointerValue(schedule.CronStartOfDay),
-			TimeRangePreset:    types.Int64PointerValue(schedule.TimeRangePreset),
-			DeltaType:          types.Int64PointerValue(schedule.DeltaType),
-			DeltaInterval:      types.Int64PointerValue(schedule.DeltaInterval),
-			DeltaIntervalStart: types.Int64PointerValue(schedule.DeltaIntervalStart),
-			DeltaStartOfDay:    types.StringPointerValue(schedule.DeltaStartOfDay),
-			FixedStart:         types.StringPointerValue(schedule.FixedStart),
-			FixedEnd:           types.StringPointerValue(schedule.FixedEnd),
-			OffsetDays:         types.Int64PointerValue(schedule.OffsetDays),
-			NotBeforeDate:      types.StringPointerValue(schedule.NotBeforeDate),
-			NotBeforeTime:      types.StringPointerValue(schedule.NotBeforeTime),
+	take := func(match func(adverity.Schedule) bool) *adverity.Schedule {
+		for i, schedule := range remaining {
+			if match(schedule) {
+				remaining = slices.Delete(remaining, i, i+1)
+				return &schedule
+			}
+		}
+		return nil
+	}
+
+	matched := make([]*adverity.Schedule, len(prior))
+
+	// Schedules which are already known by their ID
+	for i, p := range prior {
+		if p.ID.IsNull() || p.ID.IsUnknown() {
+			continue
+		}
+		matched[i] = take(func(s adverity.Schedule) bool {
+			return *s.ID == p.ID.ValueInt64()
 		})
 	}
-	state.Schedules = schedules
+
+	// New schedules by their configured values, falling back to creation order
+	for _, byValues := range []bool{true, false} {
+		for i, p := range prior {
+			if matched[i] != nil || !p.ID.IsUnknown() {
+				continue
+			}
+			want := expandSchedule(p)
+			matched[i] = take(func(s adverity.Schedule) bool {
+				return !byValues || scheduleMatches(want, s)
+			})
+		}
+	}
+
+	var schedules []datastreamScheduleModel
+	for i, p := range prior {
+		if matched[i] == nil {
+			continue // schedule was deleted outside of Terraform
+		}
+		schedule := flattenSchedule(*matched[i])
+		schedule.Key = p.Key
+		schedule.CronExpression = flattenCronExpression(*matched[i], p.CronExpression)
+		schedules = append(schedules, schedule)
+	}
+	for _, s := range remaining {
+		schedule := flattenSchedule(s)
+		schedule.Key = types.StringNull()
+		schedule.CronExpression = flattenCronExpression(s, types.StringNull())
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules
+}
+
+// flattenCronExpression reconstructs the cron expression of a schedule from its cron fields.
+// The prior expression is kept as long as it is equivalent to the schedule returned by the API,
+// so different notations of the same schedule (e.g. 1-5 and MON-FRI) don't cause diffs.
+func flattenCronExpression(apiSchedule adverity.Schedule, prior types.String) types.String {
+	cron, err := schedule.CronFromSchedule(apiSchedule)
+	if err != nil {
+		return types.StringNull()
+	}
+	expr, err := schedule.FormatCronExpression(cron)
+	if err != nil {
+		return types.StringNull() // changed outside of Terraform into something cron syntax cannot express
+	}
+	if !prior.IsNull() && !prior.IsUnknown() && schedule.EquivalentCronExpressions(prior.ValueString(), expr) {
+		return prior
+	}
+
+	return types.StringValue(expr)
+}
+
+// expandSchedule maps a schedule block to the API representation, omitting the ID and unknown values.
+// A cron expression is translated into the cron fields it represents.
+func expandSchedule(schedule datastreamScheduleModel) adverity.Schedule {
+	config := adverity.Schedule{}
+
+	if !schedule.CronPreset.IsUnknown() {
+		config.CronPreset = schedule.CronPreset.ValueStringPointer()
+	}
+	if !schedule.CronType.IsUnknown() {
+		config.CronType = schedule.CronType.ValueStringPointer()
+	}
+	if !schedule.CronInterval.IsUnknown() {
+		config.CronInterval = schedule.CronInterval.ValueInt64Pointer()
+	}
+	if !schedule.CronIntervalStart.IsUnknown() {
+		config.CronIntervalStart = schedule.CronIntervalStart.ValueInt64Pointer()
+	}
+	if !schedule.CronStartOfDay.IsUnknown() {
+		config.CronStartOfDay = schedule.CronStartOfDay.ValueStringPointer()
+	}
+	if !schedule.TimeRangePreset.IsUnknown() {
+		config.TimeRangePreset = schedule.TimeRangePreset.ValueInt64Pointer()
+	}
+	if code := utils.ExpandEnum(adverity.TimeRanges, schedule.TimeRange); code != nil {
+		config.TimeRangePreset = code
+	}
+	if !schedule.DeltaType.IsUnknown() {
+		config.DeltaType = schedule.DeltaType.ValueInt64Pointer()
+	}
+	if !schedule.DeltaInterval.IsUnknown() {
+		config.DeltaInterval = schedule.DeltaInterval.ValueInt64Pointer()
+	}
+	if !schedule.DeltaIntervalStart.IsUnknown() {
+		config.DeltaIntervalStart = schedule.DeltaIntervalStart.ValueInt64Pointer()
+	}
+	if !schedule.DeltaStartOfDay.IsUnknown() {
+		config.DeltaStartOfDay = schedule.DeltaStartOfDay.ValueStringPointer()
+	}
+	if !schedule.FixedStart.IsUnknown() {
+		config.FixedStart = schedule.FixedStart.ValueStringPointer()
+	}
+	if !schedule.FixedEnd.IsUnknown() {
+		config.FixedEnd = schedule.FixedEnd.ValueStringPointer()
+	}
+	if !schedule.OffsetDays.IsUnknown() {
+		config.OffsetDays = schedule.OffsetDays.ValueInt64Pointer()
+	}
+	if !schedule.NotBeforeDate.IsUnknown() {
+		config.NotBeforeDate = schedule.NotBeforeDate.ValueStringPointer()
+	}
+	if !schedule.NotBeforeTime.IsUnknown() {
+		config.NotBeforeTime = schedule.NotBeforeTime.ValueStringPointer()
+	}
+	expandCronExpression(schedule.CronExpression, &config)
+
+	return config
 }
 
-func (r *datastreamResource) mapSchedulesToConfig(plan datastreamResourceModel) *[]adverity.Schedule {
+// expandCronExpression sets the cron fields of the schedule from a configured cron expression.
+// Invalid expressions are ignored here since they are rejected by the attribute validator.
+// Schedules which already have cron fields (e.g. refreshed ones with a computed expression)
+// are kept as they are, since the expression does not carry the fields Adverity left unset.
+func expandCronExpression(expr types.String, config *adverity.Schedule) {
+	if expr.IsNull() || expr.IsUnknown() || config.CronPreset != nil || config.CronType != nil {
+		return
+	}
+
+	if cron, err := schedule.ParseCronExpression(expr.ValueString()); err == nil {
+		cron.Apply(config)
+	}
+}
+
+// expandSchedules maps the schedule blocks to the API representation for creating all schedules at once.
+func expandSchedules(scheduleModels []datastreamScheduleModel) *[]adverity.Schedule {
 	schedules := make([]adverity.Schedule, 0) // we want to send an empty array when there are no schedules set
 
-	for _, schedule := range plan.Schedules {
-		config := adverity.Schedule{}
+	for _, schedule := range scheduleModels {
+		schedules = append(schedules, expandSchedule(schedule))
+	}
 
-		if !schedule.CronPreset.IsUnknown() {
-			config.CronPreset = schedule.CronPreset.ValueStringPointer()
-		}
-		if !schedule.CronType.IsUnknown() {
-			config.CronType = schedule.CronType.ValueStringPointer()
+	return &schedules
+}
+
+// reconcileSchedules builds the schedule payload for updating the schedules of an existing
+// datastream. Adverity requires the full list of schedules, so unchanged schedules are sent
+// as they are known from the prior state, changed schedules are sent with their ID and new
+// values, new schedules are sent without an ID and removed schedules are omitted.
+// The returned bool reports whether any schedule was added, changed or removed.
+func reconcileSchedules(planned, prior []datastreamScheduleModel) (*[]adverity.Schedule, bool) {
+	priorByID := make(map[int64]datastreamScheduleModel, len(prior))
+	for _, p := range prior {
+		if !p.ID.IsNull() && !p.ID.IsUnknown() {
+			priorByID[p.ID.ValueInt64()] = p
 		}
-		if !schedule.CronInterval.IsUnknown() {
-			config.CronInterval = schedule.CronInterval.ValueInt64Pointer()
+	}
+
+	changed := false
+	schedules := make([]adverity.Schedule, 0, len(planned))
+	for _, schedule := range planned {
+		config := expandSchedule(schedule)
+
+		if p, ok := priorByID[schedule.ID.ValueInt64()]; ok && !schedule.ID.IsUnknown() {
+			delete(priorByID, schedule.ID.ValueInt64())
+			if priorConfig := expandSchedule(p); scheduleMatches(config, priorConfig) {
+				config = priorConfig
+			} else {
+				changed = true
+			}
+			config.ID = schedule.ID.ValueInt64Pointer()
+		} else {
+			changed = true
 		}
-		if !schedule.CronIntervalStart.IsUnknown() {
-			config.CronIntervalStart = schedule.CronIntervalStart.ValueInt64Pointer()
+
+		schedules = append(schedules, config)
+	}
+
+	return &schedules, changed || len(priorByID) > 0
+}
+
+// scheduleMatches reports whether all values set in want are equal in got. IDs are ignored.
+func scheduleMatches(want, got adverity.Schedule) bool {
+	w := reflect.ValueOf(want)
+	g := reflect.ValueOf(got)
+
+	for i := 0; i < w.NumField(); i++ {
+		if w.Type().Field(i).Name == "ID" || w.Field(i).IsNil() {
+			continue
 		}
-		if !schedule.CronStartOfDay.IsUnknown() {
-			config.CronStartOfDay = schedule.CronStartOfDay.ValueStringPointer()
+		if g.Field(i).IsNil() || w.Field(i).Elem().Interface() != g.Field(i).Elem().Interface() {
+			return false
 		}
-		if !schedule.TimeRangePreset.IsUnknown() {
-			config.TimeRangePreset = schedule.TimeRangePreset.ValueInt64Pointer()
+	}
+
+	return true
+}
+
+// planSchedules aligns the planned schedule blocks with the prior state.
+//
+// Schedules with a key are matched to the prior schedule with the same key, the other schedules
+// (and schedules stored by provider versions without keys) to a prior schedule with the same values.
+// Unchanged schedules keep their prior values, changed schedules keep their ID but get unknown
+// computed values, and new schedules are planned entirely from the configuration.
+func planSchedules(ctx context.Context, config tfsdk.Config, state tfsdk.State, diags *diag.Diagnostics) ([]datastreamScheduleModel, bool) {
+	var configSet types.Set
+	diags.Append(config.GetAttribute(ctx, path.Root("schedule"), &configSet)...)
+	if diags.HasError() || configSet.IsUnknown() {
+		return nil, false
+	}
+
+	var configured, prior []datastreamScheduleModel
+	diags.Append(configSet.ElementsAs(ctx, &configured, false)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("schedule"), &prior)...)
+	if diags.HasError() {
+		return nil, false
+	}
+
+	planned := make([]datastreamScheduleModel, len(configured))
+	matched := make([]bool, len(configured))
+
+	// Schedules by their key
+	for i, c := range configured {
+		if !known(c.Key) {
+			continue
 		}
-		if !schedule.DeltaType.IsUnknown() {
-			config.DeltaType = schedule.DeltaType.ValueInt64Pointer()
+		for j, p := range prior {
+			if p.Key.Equal(c.Key) {
+				planned[i], matched[i] = planSchedule(c, p), true
+				prior = slices.Delete(prior, j, j+1)
+				break
+			}
 		}
-		if !schedule.DeltaInterval.IsUnknown() {
-			config.DeltaInterval = schedule.DeltaInterval.ValueInt64Pointer()
+	}
+
+	// Schedules without a key, or with a key unknown to the prior state, by their values
+	for i, c := range configured {
+		if matched[i] {
+			continue
 		}
-		if !schedule.DeltaIntervalStart.IsUnknown() {
-			config.DeltaIntervalStart = schedule.DeltaIntervalStart.ValueInt64Pointer()
+		want := expandSchedule(c)
+		j := slices.IndexFunc(prior, func(p datastreamScheduleModel) bool {
+			return scheduleMatches(want, expandSchedule(p))
+		})
+		if j < 0 {
+			c = c.withUnknownComputedValues()
+			c.ID = types.Int64Unknown()
+			planned[i] = c
+			continue
 		}
-		if !schedule.DeltaStartOfDay.IsUnknown() {
-			config.DeltaStartOfDay = schedule.DeltaStartOfDay.ValueStringPointer()
+		planned[i] = planSchedule(c, prior[j])
+		prior = slices.Delete(prior, j, j+1)
+	}
+
+	return planned, true
+}
+
+// planSchedule plans a configured schedule matched to a prior schedule.
+func planSchedule(c, p datastreamScheduleModel) datastreamScheduleModel {
+	if !c.hasUnknownValues() && scheduleMatches(expandSchedule(c), expandSchedule(p)) {
+		p.Key = c.Key
+		if !c.CronExpression.IsNull() {
+			p.CronExpression = c.CronExpression
 		}
-		if !schedule.FixedStart.IsUnknown() {
-			config.FixedStart = schedule.FixedStart.ValueStringPointer()
+		return p
+	}
+
+	id := p.ID
+	c = c.withUnknownComputedValues()
+	c.ID = id
+	return c
+}
+
+// validateParameters validates the parameters against the field metadata of the datastream type.
+func (r *datastreamResource) validateParameters(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	// Nothing to validate before the provider is configured
+	if r.providerData == nil {
+		return
+	}
+
+	var typeId types.Int64
+	var parameters types.Dynamic
+	diags.Append(plan.GetAttribute(ctx, path.Root("datastream_type_id"), &typeId)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+	if diags.HasError() || typeId.IsUnknown() {
+		return
+	}
+
+	// Validate against the field metadata of the planned instance
+	client := r.providerData.planClient(ctx, plan, diags)
+	if client == nil {
+		return
+	}
+
+	readFields := func() (map[string]adverity.FieldMetadata, error) {
+		return client.ReadDatastreamFields(ctx, int(typeId.ValueInt64()))
+	}
+	managed := append(adverity.PayloadFields(adverity.DatastreamCreateConfig{}), adverity.PayloadFields(adverity.DatastreamScheduleConfig{})...)
+	utils.ValidateParametersWithMetadata([]utils.ParameterSource{utils.ParametersSource(parameters)}, readFields, managed, diags)
+}
+
+// planManageSchedules plans manage_schedules if it is not configured. The schedules are only managed
+// if schedule blocks are configured, so a datastream without them does not remove the schedules
+// managed by an adverity_datastream_schedule resource.
+func planManageSchedules(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan, diags *diag.Diagnostics) {
+	var manageSchedules types.Bool
+	var schedules types.Set
+	diags.Append(config.GetAttribute(ctx, path.Root("manage_schedules"), &manageSchedules)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("schedule"), &schedules)...)
+	if diags.HasError() || !manageSchedules.IsNull() {
+		return
+	}
+
+	// Dynamic schedule blocks are resolved on apply
+	planned := types.BoolUnknown()
+	if !schedules.IsUnknown() {
+		planned = types.BoolValue(len(schedules.Elements()) > 0)
+	}
+	diags.Append(plan.SetAttribute(ctx, path.Root("manage_schedules"), planned)...)
+}
+
+// resolveManageSchedules sets manage_schedules if it was not known when planning.
+func (m *datastreamResourceModel) resolveManageSchedules() {
+	if m.ManageSchedules.IsUnknown() {
+		m.ManageSchedules = types.BoolValue(len(m.Schedules) > 0)
+	}
+}
+
+// hasUnknownValues reports whether any value of the schedule is not yet known.
+func (m datastreamScheduleModel) hasUnknownValues() bool {
+	v := reflect.ValueOf(m)
+	for i := 0; i < v.NumField(); i++ {
+		if value, ok := v.Field(i).Interface().(attr.Value); ok && value.IsUnknown() {
+			return true
 		}
-		if !schedule.FixedEnd.IsUnknown() {
-			config.FixedEnd = schedule.FixedEnd.ValueStringPointer()
+	}
+	return false
+}
+
+// withUnknownComputedValues returns a copy of the schedule with all unset values marked as unknown,
+// since Adverity derives them from the set values (e.g. cron_type and cron_interval from cron_preset).
+func (m datastreamScheduleModel) withUnknownComputedValues() datastreamScheduleModel {
+	unknownString := func(v types.String) types.String {
+		if v.IsNull() {
+			return types.StringUnknown()
 		}
-		if !schedule.OffsetDays.IsUnknown() {
-			config.OffsetDays = schedule.OffsetDays.ValueInt64Pointer()
+		return v
+	}
+	unknownInt64 := func(v types.Int64) types.Int64 {
+		if v.IsNull() {
+			return types.Int64Unknown()
 		}
-		if !schedule.NotBeforeDate.IsUnknown() {
-			config.NotBeforeDate = schedule.NotBeforeDate.ValueStringPointer()
+		return v
+	}
+
+	m.CronExpression = unknownString(m.CronExpression)
+	m.CronPreset = unknownString(m.CronPreset)
+	m.CronType = unknownString(m.CronType)
+	m.CronInterval = unknownInt64(m.CronInterval)
+	m.CronIntervalStart = unknownInt64(m.CronIntervalStart)
+	m.CronStartOfDay = unknownString(m.CronStartOfDay)
+	m.TimeRangePreset = unknownInt64(m.TimeRangePreset)
+	m.TimeRange = unknownString(m.TimeRange)
+	m.DeltaType = unknownInt64(m.DeltaType)
+	m.DeltaInterval = unknownInt64(m.DeltaInterval)
+	m.DeltaIntervalStart = unknownInt64(m.DeltaIntervalStart)
+	m.DeltaStartOfDay = unknownString(m.DeltaStartOfDay)
+	m.FixedStart = unknownString(m.FixedStart)
+	m.FixedEnd = unknownString(m.FixedEnd)
+	m.OffsetDays = unknownInt64(m.OffsetDays)
+	m.NotBeforeDate = unknownString(m.NotBeforeDate)
+	m.NotBeforeTime = unknownString(m.NotBeforeTime)
+
+	return m
+}
+
+// validateScheduleKeys ensures the keys of the schedule blocks are unique.
+func validateScheduleKeys(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var configSet types.Set
+	diags.Append(config.GetAttribute(ctx, path.Root("schedule"), &configSet)...)
+	if diags.HasError() || configSet.IsUnknown() {
+		return
+	}
+
+	var configured []datastreamScheduleModel
+	diags.Append(configSet.ElementsAs(ctx, &configured, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool, len(configured))
+	for i, c := range configured {
+		if c.Key.IsNull() || c.Key.IsUnknown() {
+			continue
 		}
-		if !schedule.NotBeforeTime.IsUnknown() {
-			config.NotBeforeTime = schedule.NotBeforeTime.ValueStringPointer()
+		if seen[c.Key.ValueString()] {
+			diags.AddAttributeError(
+				path.Root("schedule").AtSetValue(configSet.Elements()[i]).AtName("key"),
+				"Duplicate schedule key",
+				fmt.Sprintf("The schedule key %q is used more than once. Each schedule must have a unique key.", c.Key.ValueString()),
+			)
 		}
+		seen[c.Key.ValueString()] = true
+	}
+}
 
-		schedules = append(schedules, config)
+// datastreamScheduleBlock returns the schedule block shared by the datastream and datastream schedule resources.
+func datastreamScheduleBlock() schema.SetNestedBlock {
+	return schema.SetNestedBlock{
+		Description: "Schedule the datastream. The schedules are a set, so their order does not matter. " +
+			"Schedules without a key are identified by their values, so changing one replaces it with a new schedule.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"key": schema.StringAttribute{
+					Description: "Unique key of the schedule within the datastream, which identifies it across changes of its values, " +
+						"so it keeps its ID. Imported schedules have no key, a configured key is taken over by the imported schedule with the same values.",
+					Optional: true,
+				},
+				"id": schema.Int64Attribute{
+					Description: "Numeric identifier of the schedule.",
+					Computed:    true,
+				},
+				"cron_expression": schema.StringAttribute{
+					Description: "Standard five-field cron expression (e.g. `30 3 * * 1-5`), translated into the cron fields of the schedule. " +
+						"Supported are runs every N hours (N dividing 24), every day, every weekday, every week on one day and every month on one day. " +
+						"Computed from the cron fields if not configured, e.g. for imported schedules, and null if cron syntax cannot express them.",
+					Optional: true,
+					Computed: true,
+					Validators: []validator.String{
+						validators.CronExpression(),
+						stringvalidator.ConflictsWith(
+							path.MatchRelative().AtParent().AtName("cron_preset"),
+							path.MatchRelative().AtParent().AtName("cron_type"),
+							path.MatchRelative().AtParent().AtName("cron_interval"),
+							path.MatchRelative().AtParent().AtName("cron_interval_start"),
+							path.MatchRelative().AtParent().AtName("cron_start_of_day"),
+						),
+					},
+				},
+				// We don't use plan modifiers for any attribute here because Adverity changes
+				// attributes based on presets (e.g. cron_type and cron_interval are derived from cron_preset)
+				"cron_preset": schema.StringAttribute{
+					Description: "Cron preset.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_type")),
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_interval")),
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_expression")),
+					},
+				},
+				"cron_type": schema.StringAttribute{
+					Description: "Cron type.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_preset")),
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_expression")),
+					},
+				},
+				"cron_interval": schema.Int64Attribute{
+					Description: "Cron interval.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.Int64{
+						int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_preset")),
+						int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_expression")),
+					},
+				},
+				"cron_interval_start": schema.Int64Attribute{
+					Description: "Cron interval start.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.Int64{
+						int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_expression")),
+					},
+				},
+				"cron_start_of_day": schema.StringAttribute{
+					Description: "Cron start of day.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						validators.TimeHHMMSS(),
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_expression")),
+					},
+				},
+				"time_range_preset": schema.Int64Attribute{
+					Description: "Numeric identifier of the time range preset. Use `time_range` for the readable form.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.Int64{
+						int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("time_range")),
+					},
+				},
+				"time_range": schema.StringAttribute{
+					Description: "Time range preset in readable form, alternative to `time_range_preset`. " +
+						"Only `custom` is supported, other presets are set with `time_range_preset`.",
+					Optional: true,
+					Computed: true,
+					Validators: []validator.String{
+						stringvalidator.OneOf(adverity.TimeRanges.Names()...),
+						stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("time_range_preset")),
+					},
+				},
+				"delta_type": schema.Int64Attribute{
+					Description: "Numeric identifier of the delta type.",
+					Optional:    true,
+					Computed:    true,
+				},
+				"delta_interval": schema.Int64Attribute{
+					Description: "Delta interval.",
+					Optional:    true,
+					Computed:    true,
+				},
+				"delta_interval_start": schema.Int64Attribute{
+					Description: "Delta interval start.",
+					Optional:    true,
+					Computed:    true,
+				},
+				"delta_start_of_day": schema.StringAttribute{
+					Description: "Delta start of day.",
+					Optional:    true,
+					Computed:    true,
+				},
+				"fixed_start": schema.StringAttribute{
+					Description: "Fixed start of a custom time range, which requires it.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						validators.DateYYYYMMDD(),
+					},
+				},
+				"fixed_end": schema.StringAttribute{
+					Description: "Fixed end of a custom time range. Must not be before `fixed_start`.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						validators.DateYYYYMMDD(),
+					},
+				},
+				"offset_days": schema.Int64Attribute{
+					Description: "Offset days.",
+					Optional:    true,
+					Computed:    true,
+				},
+				"not_before_date": schema.StringAttribute{
+					Description: "Not before date.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						validators.DateYYYYMMDD(),
+					},
+				},
+				"not_before_time": schema.StringAttribute{
+					Description: "Not before time.",
+					Optional:    true,
+					Computed:    true,
+					Validators: []validator.String{
+						validators.TimeHHMMSS(),
+					},
+				},
+			},
+		},
 	}
+}
 
-	return &schedules
+// ConfigValidators returns the validators checking the semantics of the schedules.
+func (r *datastreamResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		scheduleConfigValidator{},
+	}
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *datastreamResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var manageSchedules types.Bool
+	var schedules types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("manage_schedules"), &manageSchedules)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedule"), &schedules)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateScheduleKeys(ctx, req.Config, &resp.Diagnostics)
+
+	if manageSchedules.IsNull() || manageSchedules.IsUnknown() || manageSchedules.ValueBool() {
+		return
+	}
+
+	if len(schedules.Elements()) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schedule"),
+			"Conflicting schedule configuration",
+			"Schedule blocks cannot be declared when manage_schedules is false. "+
+				"Either remove the schedule blocks or manage the schedules with an adverity_datastream_schedule resource.",
+		)
+	}
+}
+
+// ModifyPlan validates the parameters against the field metadata of the datastream type,
+// plans whether the schedules are managed and aligns the planned schedules with the prior state.
+func (r *datastreamResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	r.validateParameters(ctx, req.Plan, &resp.Diagnostics)
+	planManageSchedules(ctx, req.Config, &resp.Plan, &resp.Diagnostics)
+
+	// Nothing to align on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	schedules, ok := planSchedules(ctx, req.Config, req.State, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schedule"), schedules)...)
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *datastreamResource) updatePayload(model datastreamResourceModel, diags *diag.Diagnostics) *adverity.DatastreamUpdateConfig {
+	payload := &adverity.DatastreamUpdateConfig{
+		Name:                model.Name.ValueStringPointer(),
+		Description:         model.Description.ValueStringPointer(),
+		StackID:             model.StackID.ValueInt64Pointer(),
+		AuthID:              model.AuthID.ValueInt64Pointer(),
+		IsInsightsMediaplan: model.IsInsightsMediaplan.ValueBoolPointer(),
+		ManageExtractNames:  model.ManageExtractNames.ValueBoolPointer(),
+		ExtractNameKeys:     model.ExtractNameKeys.ValueStringPointer(),
+		Parameters:          utils.ExpandDynamicParameters(model.Parameters, path.Root("parameters"), diags),
+	}
+	if !model.DataType.IsUnknown() {
+		payload.DataType = model.DataType.ValueStringPointer()
+	}
+	if !model.RetentionType.IsUnknown() {
+		payload.RetentionType = model.RetentionType.ValueInt64Pointer()
+	}
+	if !model.RetentionNumber.IsUnknown() {
+		payload.RetentionNumber = model.RetentionNumber.ValueInt64Pointer()
+	}
+	return payload
 }
 
 // Configure adds the provider configured client to the resource.
@@ -192,18 +820,18 @@ func (r *datastreamResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
 }
 
 // Metadata returns the resource type name.
@@ -223,6 +851,8 @@ func (r *datastreamResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"adopt_existing": adoptExistingAttribute("datastream"),
+			"instance":       instanceAttribute(),
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the datastream.",
 				Computed:    true,
@@ -230,6 +860,9 @@ func (r *datastreamResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"datastream_type_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the datastream type.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description: "Name of the datastream.",
@@ -244,8 +877,12 @@ func (r *datastreamResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 			"stack_id": schema.Int64Attribute{
-				Description: "Numeric identifier of the workspace.",
+				Description: "Numeric identifier of the workspace. Changing it moves the datastream to the workspace in place, keeping its extracts and history.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					stackMove("datastream", path.Root("auth_id"), "authorization",
+						"Destination mappings of the datastream are kept, their destinations are not moved."),
+				},
 			},
 			"auth_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the connection.",
@@ -307,121 +944,20 @@ func (r *datastreamResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"manage_schedules": schema.BoolAttribute{
+				Description: "Whether to manage the schedules of the datastream with `schedule` blocks. " +
+					"Defaults to true if `schedule` blocks are configured and to false otherwise, so schedules managed by an " +
+					"`adverity_datastream_schedule` resource are left untouched. Set to true to remove all schedules of the datastream.",
+				Optional: true,
+				Computed: true,
+			},
 			"parameters": schema.DynamicAttribute{
 				Description: "Additional datastream parameters.",
 				Optional:    true,
 			},
 		},
 		Blocks: map[string]schema.Block{
-			"schedule": schema.ListNestedBlock{
-				Description: "Schedule the datastream.",
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						// We don't use plan modifiers for any attribute here because Adverity changes
-						// attributes based on presets (e.g. cron_type and cron_interval are derived from cron_preset)
-						"cron_preset": schema.StringAttribute{
-							Description: "Cron preset.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_type")),
-								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_interval")),
-							},
-						},
-						"cron_type": schema.StringAttribute{
-							Description: "Cron type.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_preset")),
-							},
-						},
-						"cron_interval": schema.Int64Attribute{
-							Description: "Cron interval.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.Int64{
-								int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("cron_preset")),
-							},
-						},
-						"cron_interval_start": schema.Int64Attribute{
-							Description: "Cron interval start.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"cron_start_of_day": schema.StringAttribute{
-							Description: "Cron start of day.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								validators.TimeHHMMSS(),
-							},
-						},
-						"time_range_preset": schema.Int64Attribute{
-							Description: "Time range preset.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"delta_type": schema.Int64Attribute{
-							Description: "Delta type.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"delta_interval": schema.Int64Attribute{
-							Description: "Delta interval.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"delta_interval_start": schema.Int64Attribute{
-							Description: "Delta interval start.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"delta_start_of_day": schema.StringAttribute{
-							Description: "Delta start of day.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"fixed_start": schema.StringAttribute{
-							Description: "Fixed start.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								validators.DateYYYYMMDD(),
-							},
-						},
-						"fixed_end": schema.StringAttribute{
-							Description: "Fixed end.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								validators.DateYYYYMMDD(),
-							},
-						},
-						"offset_days": schema.Int64Attribute{
-							Description: "Offset days.",
-							Optional:    true,
-							Computed:    true,
-						},
-						"not_before_date": schema.StringAttribute{
-							Description: "Not before date.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								validators.DateYYYYMMDD(),
-							},
-						},
-						"not_before_time": schema.StringAttribute{
-							Description: "Not before time.",
-							Optional:    true,
-							Computed:    true,
-							Validators: []validator.String{
-								validators.TimeHHMMSS(),
-							},
-						},
-					},
-				},
-			},
+			"schedule": datastreamScheduleBlock(),
 		},
 	}
 }
@@ -436,6 +972,12 @@ func (r *datastreamResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	payload := &adverity.DatastreamCreateConfig{
 		Name:                plan.Name.ValueStringPointer(),
@@ -465,44 +1007,42 @@ func (r *datastreamResource) Create(ctx context.Context, req resource.CreateRequ
 		payload.Parameters = &parameters
 	}
 
-	payload.Schedules = r.mapSchedulesToConfig(plan)
+	plan.resolveManageSchedules()
+	if plan.ManageSchedules.ValueBool() {
+		payload.Schedules = expandSchedules(plan.Schedules)
+	}
 
-	// Create new datastream
-	datastream, err := r.client.CreateDatastream(int(plan.DatastreamTypeId.ValueInt64()), payload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating datastream",
-			"Could not create datastream, unexpected error: "+err.Error(),
-		)
+	// Create new datastream, or adopt the existing one
+	datastream, existing := createDatastream(ctx, client, plan.DatastreamTypeId, plan.StackID, plan.Name, plan.AdoptExisting, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Workaround for removing default schedules created by Adverity when
-	// no schedules are defined in the datastream resource block.
-	// If no schedule blocks were defined, delete the default schedule so
-	// the actual state on the server matches the Terraform configuration.
-	if len(plan.Schedules) == 0 && len(datastream.Schedules) > 0 {
-		emptySchedules := make([]adverity.Schedule, 0)
-		schedulePayload := &adverity.DatastreamScheduleConfig{
-			Schedules: &emptySchedules,
-			Enabled:   plan.Enabled.ValueBoolPointer(),
-		}
-		_, err = r.client.UpdateDatastreamSchedule(int(datastream.ID), schedulePayload)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error removing default schedule from datastream",
-				"Could not remove default schedule, unexpected error: "+err.Error(),
-			)
-			return
+	// Hold the datastream until its schedules are set up
+	unlock := client.LockDatastream(int(datastream.ID))
+	defer unlock()
+
+	if existing {
+		// Update the adopted datastream to the plan
+		schedulePayload := &adverity.DatastreamScheduleConfig{Enabled: plan.Enabled.ValueBoolPointer()}
+		if plan.ManageSchedules.ValueBool() {
+			schedulePayload.Schedules = expandSchedules(plan.Schedules)
 		}
-		datastream, err = r.client.ReadDatastream(int(datastream.DatastreamTypeID), int(datastream.ID))
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error reading Adverity datastream",
-				"Could not read datastream, unexpected error: "+err.Error(),
-			)
+		updatePayload := r.updatePayload(plan, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+		datastream = adoptDatastream(ctx, client, datastream, schedulePayload, updatePayload, &resp.Diagnostics)
+	} else if len(plan.Schedules) == 0 {
+		// Workaround for removing default schedules created by Adverity when
+		// no schedules are defined in the datastream resource block.
+		// If no schedule blocks were defined (or the schedules are managed by a separate
+		// adverity_datastream_schedule resource), delete the default schedule so
+		// the actual state on the server matches the Terraform configuration.
+		datastream = removeDefaultSchedules(ctx, client, datastream, plan.Enabled, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Map response body to schema and populate computed attribute values
@@ -527,13 +1067,15 @@ func (r *datastreamResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get refreshed datastream value from Adverity
-	datastream, err := r.client.ReadDatastream(int(state.DatastreamTypeId.ValueInt64()), int(state.ID.ValueInt64()))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading Adverity datastream",
-			"Could not read datastream, unexpected error: "+err.Error(),
-		)
+	datastream := r.providerData.readDatastreamState(ctx, client, state.StackID, state.DatastreamTypeId, state.ID, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -549,66 +1091,53 @@ func (r *datastreamResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *datastreamResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan datastreamResourceModel
+	// Retrieve values from plan and prior state
+	var plan, state datastreamResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Generate API request body from plan
-	payload := &adverity.DatastreamUpdateConfig{
-		Name:                plan.Name.ValueStringPointer(),
-		Description:         plan.Description.ValueStringPointer(),
-		StackID:             plan.StackID.ValueInt64Pointer(),
-		AuthID:              plan.AuthID.ValueInt64Pointer(),
-		IsInsightsMediaplan: plan.IsInsightsMediaplan.ValueBoolPointer(),
-		ManageExtractNames:  plan.ManageExtractNames.ValueBoolPointer(),
-		ExtractNameKeys:     plan.ExtractNameKeys.ValueStringPointer(),
-	}
-	if !plan.DataType.IsUnknown() {
-		payload.DataType = plan.DataType.ValueStringPointer()
-	}
-	if !plan.RetentionType.IsUnknown() {
-		payload.RetentionType = plan.RetentionType.ValueInt64Pointer()
-	}
-	if !plan.RetentionNumber.IsUnknown() {
-		payload.RetentionNumber = plan.RetentionNumber.ValueInt64Pointer()
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	plan.resolveManageSchedules()
 
-	if !plan.Parameters.IsNull() {
-		parameters := utils.ExpandParameters(plan.Parameters.UnderlyingValue(), path.Root("parameters"), &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		payload.Parameters = &parameters
+	// The schedules and the datastream are updated with separate requests,
+	// which must not interleave with other changes of the datastream
+	unlock := client.LockDatastream(int(plan.ID.ValueInt64()))
+	defer unlock()
+
+	// Generate API request body with the changes from the prior state to the plan, which leaves
+	// the values left to Adverity unchanged
+	patchPlan := plan
+	patchPlan.DataType = utils.KnownOrPrior(state.DataType, plan.DataType)
+	patchPlan.RetentionType = utils.KnownOrPrior(state.RetentionType, plan.RetentionType)
+	patchPlan.RetentionNumber = utils.KnownOrPrior(state.RetentionNumber, plan.RetentionNumber)
+	payload := utils.PatchPayload(r.updatePayload(state, &resp.Diagnostics), r.updatePayload(patchPlan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	// The enabled flag and the schedules are only accepted by the schedule endpoint
 	schedulePayload := &adverity.DatastreamScheduleConfig{
-		Enabled: plan.Enabled.ValueBoolPointer(),
+		Enabled: utils.PatchValue(state.Enabled, plan.Enabled, types.Bool.ValueBoolPointer),
 	}
-	schedulePayload.Schedules = r.mapSchedulesToConfig(plan)
-
-	// Update existing datastream schedule
-	// We ignore the returned body since not all fields are populated by this endpoint for a state refresh (e.g. stack_id)
-	_, err := r.client.UpdateDatastreamSchedule(int(plan.ID.ValueInt64()), schedulePayload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Adverity datastream schedule",
-			"Could not update datastream schedule, unexpected error: "+err.Error(),
-		)
-		return
+	if plan.ManageSchedules.ValueBool() {
+		// Only send the schedules if any of them were added, changed or removed
+		if schedules, changed := reconcileSchedules(plan.Schedules, state.Schedules); changed {
+			schedulePayload.Schedules = schedules
+		}
 	}
 
-	// Update existing datastream
-	// Schedule changes from the previous update request are reflected in this response
-	datastream, err := r.client.UpdateDatastream(int(plan.DatastreamTypeId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Adverity datastream",
-			"Could not update datastream, unexpected error: "+err.Error(),
-		)
+	// Update existing datastream, the schedules first so the response reflects them
+	datastream := updateDatastream(ctx, client, plan.DatastreamTypeId, plan.ID, schedulePayload, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -633,8 +1162,14 @@ func (r *datastreamResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing datastream
-	_, err := r.client.DeleteDatastream(int(state.DatastreamTypeId.ValueInt64()), int(state.ID.ValueInt64()))
+	_, err := client.DeleteDatastream(ctx, int(state.DatastreamTypeId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Adverity datastream",
@@ -645,8 +1180,11 @@ func (r *datastreamResource) Delete(ctx context.Context, req resource.DeleteRequ
 }
 
 func (r *datastreamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
 	// Split the composite import ID (<datastream_type_id>:<id>) into its parts
-	parts := utils.SplitImportParts(req.ID, 2, "<datastream_type_id>:<id>", &resp.Diagnostics)
+	parts := utils.SplitImportParts(importID, 2, "<datastream_type_id>:<id>", &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
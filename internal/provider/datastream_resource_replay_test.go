@@ -0,0 +1,266 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/adverity/fakeserver"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The replay tests guard the workarounds for quirks of the Adverity API. They call the resource
+// methods directly with a client which replays a fixture from testdata/fakeserver, and fail if the
+// requests differ from the ones in the fixture. The fixtures are recorded against the fake Adverity
+// API, which reproduces the quirks as far as they are known, not against a real instance, so they
+// pin the requests of the workarounds rather than prove the quirks. Don't edit a fixture by hand,
+// record it again instead: set ADVERITY_RECORD_FIXTURES to record the fixtures against the fake
+// Adverity API, or ADVERITY_RECORD_INSTANCE_URL and ADVERITY_RECORD_AUTH_TOKEN to record them
+// against a real instance with the workspace and authorization below.
+
+// The workspace and authorization the fixtures are recorded with.
+const (
+	testReplayStackID         = 1
+	testReplayAuthorizationID = 2
+)
+
+// testReplayServer returns a fake Adverity API with the workspace and authorization the fixtures are recorded with.
+func testReplayServer(t *testing.T) *fakeserver.Server {
+	t.Helper()
+
+	server := fakeserver.New()
+	t.Cleanup(server.Close)
+	server.AddConnectionType(fakeserver.Type{ID: testAccAuthorizationTypeID, Name: "Google Ads", Slug: "google-ads"})
+	server.AddDatastreamType(fakeserver.Type{ID: testAccDatastreamTypeID, Name: "Google Ads Insights", Slug: "google-ads-insights"})
+	server.AddTargetType(fakeserver.Type{ID: testAccDestinationTypeID, Name: "Google BigQuery", Slug: "bigquery"})
+	server.AddTargetType(fakeserver.Type{ID: testAccDestinationTypeID + 1, Name: "Snowflake", Slug: "snowflake"})
+
+	client, err := adverity.NewClient(t.Context(), server.URL, adverity.WithToken(fakeserver.DefaultToken), adverity.WithCACertPEM([]byte(server.CertificatePEM())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "Google Ads"
+	authorization, err := client.CreateAuthorization(t.Context(), testAccAuthorizationTypeID, &adverity.AuthorizationConfig{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization.ID != testReplayAuthorizationID || fakeserver.RootStackID != testReplayStackID {
+		t.Fatalf("authorization %d in workspace %d, want %d in %d", authorization.ID, fakeserver.RootStackID, testReplayAuthorizationID, testReplayStackID)
+	}
+
+	return server
+}
+
+// testReplayProviderData returns the provider data with a client which replays the fixture name.
+func testReplayProviderData(t *testing.T, name string) *providerData {
+	t.Helper()
+
+	file := filepath.Join("testdata", "fakeserver", name+".json")
+	mode := adverity.CassetteReplay
+	options := []adverity.ClientOption{adverity.WithToken("replay-token")}
+	instanceUrl := "https://replay.invalid"
+	switch {
+	case os.Getenv("ADVERITY_RECORD_INSTANCE_URL") != "":
+		mode = adverity.CassetteRecord
+		instanceUrl = os.Getenv("ADVERITY_RECORD_INSTANCE_URL")
+		options = []adverity.ClientOption{adverity.WithToken(os.Getenv("ADVERITY_RECORD_AUTH_TOKEN"))}
+	case os.Getenv("ADVERITY_RECORD_FIXTURES") != "":
+		server := testReplayServer(t)
+		mode = adverity.CassetteRecord
+		instanceUrl = server.URL
+		options = []adverity.ClientOption{adverity.WithToken(fakeserver.DefaultToken), adverity.WithCACertPEM([]byte(server.CertificatePEM()))}
+	}
+
+	cassette, err := adverity.NewCassette(file, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := adverity.NewClient(t.Context(), instanceUrl, append(options, adverity.WithRoundTripper(cassette.RoundTripper))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if mode == adverity.CassetteRecord {
+			if err := cassette.Save(); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		if remaining := cassette.Remaining(); remaining > 0 && !t.Failed() {
+			t.Errorf("%d requests of the fixture were not sent", remaining)
+		}
+	})
+
+	return &providerData{defaultClient: client}
+}
+
+// testReplayCreate creates the resource planned by the model and returns the new state.
+func testReplayCreate(t *testing.T, r resource.Resource, plan any) tfsdk.State {
+	t.Helper()
+
+	resp := resource.CreateResponse{State: testReplayState(t, r, nil)}
+	r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan(testReplayState(t, r, plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create() diagnostics = %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+// testReplayUpdate updates the resource from the prior state to the plan and returns the new state.
+func testReplayUpdate(t *testing.T, r resource.Resource, state tfsdk.State, plan any) tfsdk.State {
+	t.Helper()
+
+	resp := resource.UpdateResponse{State: state}
+	r.Update(t.Context(), resource.UpdateRequest{Plan: tfsdk.Plan(testReplayState(t, r, plan)), State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() diagnostics = %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+// testReplayState returns a state of the resource schema set to the model, or a null state for a nil model.
+func testReplayState(t *testing.T, r resource.Resource, model any) tfsdk.State {
+	t.Helper()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(t.Context(), resource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(t.Context()), nil),
+	}
+	if model != nil {
+		if diags := state.Set(t.Context(), model); diags.HasError() {
+			t.Fatalf("State.Set() diagnostics = %v", diags)
+		}
+	}
+	return state
+}
+
+// testReplayDatastreamPlan returns the plan of a new enabled datastream with the schedules.
+func testReplayDatastreamPlan(schedules ...datastreamScheduleModel) datastreamResourceModel {
+	return datastreamResourceModel{
+		DatastreamTypeId:    types.Int64Value(testAccDatastreamTypeID),
+		ID:                  types.Int64Unknown(),
+		Name:                types.StringValue("Campaigns"),
+		Description:         types.StringUnknown(),
+		StackID:             types.Int64Value(testReplayStackID),
+		AuthID:              types.Int64Value(testReplayAuthorizationID),
+		Schedules:           schedules,
+		ManageSchedules:     types.BoolValue(true),
+		Enabled:             types.BoolValue(true),
+		DataType:            types.StringUnknown(),
+		RetentionType:       types.Int64Unknown(),
+		RetentionNumber:     types.Int64Unknown(),
+		ManageExtractNames:  types.BoolUnknown(),
+		ExtractNameKeys:     types.StringUnknown(),
+		IsInsightsMediaplan: types.BoolUnknown(),
+		Parameters:          types.DynamicNull(),
+		Instance:            types.StringNull(),
+		LastUpdated:         types.StringUnknown(),
+	}
+}
+
+// testReplaySchedule returns the plan of a new schedule.
+func testReplaySchedule(key, cronPreset string, timeRangePreset int64) datastreamScheduleModel {
+	schedule := datastreamScheduleModel{
+		Key:             types.StringValue(key),
+		CronExpression:  types.StringNull(),
+		CronPreset:      types.StringValue(cronPreset),
+		TimeRangePreset: types.Int64Value(timeRangePreset),
+	}.withUnknownComputedValues()
+	schedule.ID = types.Int64Unknown()
+	return schedule
+}
+
+// testReplayScheduleByKey returns the schedule with the key, or an empty schedule.
+func testReplayScheduleByKey(schedules []datastreamScheduleModel, key string) datastreamScheduleModel {
+	for _, schedule := range schedules {
+		if schedule.Key.ValueString() == key {
+			return schedule
+		}
+	}
+	return datastreamScheduleModel{}
+}
+
+// Adverity adds a default schedule to datastreams created without schedules, which must be removed again.
+func TestReplayDatastreamDefaultSchedule(t *testing.T) {
+	r := &datastreamResource{providerData: testReplayProviderData(t, "datastream_default_schedule")}
+
+	state := testReplayCreate(t, r, testReplayDatastreamPlan())
+
+	var datastream datastreamResourceModel
+	if diags := state.Get(t.Context(), &datastream); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(datastream.Schedules) != 0 {
+		t.Errorf("schedules = %+v, want the default schedule to be removed", datastream.Schedules)
+	}
+	if !datastream.Enabled.ValueBool() {
+		t.Error("datastream is disabled, want enabled")
+	}
+}
+
+// Adverity does not return the schedules in the order they were sent, so they are matched to the configured schedules.
+func TestReplayDatastreamScheduleOrdering(t *testing.T) {
+	r := &datastreamResource{providerData: testReplayProviderData(t, "datastream_schedule_ordering")}
+
+	state := testReplayCreate(t, r, testReplayDatastreamPlan(
+		testReplaySchedule("daily", "CRON_EVERY_DAY", 2),
+		testReplaySchedule("hourly", "CRON_EVERY_HOUR", 1),
+	))
+
+	var datastream datastreamResourceModel
+	if diags := state.Get(t.Context(), &datastream); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(datastream.Schedules) != 2 {
+		t.Fatalf("schedules = %+v, want 2 schedules", datastream.Schedules)
+	}
+	for _, want := range []struct{ key, cronPreset, cronType string }{
+		{"daily", "CRON_EVERY_DAY", adverity.CronTypeDay},
+		{"hourly", "CRON_EVERY_HOUR", adverity.CronTypeHour},
+	} {
+		got := testReplayScheduleByKey(datastream.Schedules, want.key)
+		if got.CronPreset.ValueString() != want.cronPreset || got.CronType.ValueString() != want.cronType {
+			t.Errorf("schedule %s = %s %s, want %s %s", want.key, got.CronPreset, got.CronType, want.cronPreset, want.cronType)
+		}
+	}
+}
+
+// Adverity silently drops the cron fields of schedules sent in the same PATCH as the datatype, so
+// the schedules are updated with a separate request before the datastream.
+func TestReplayDatastreamUpdateSchedulesAndDatatype(t *testing.T) {
+	r := &datastreamResource{providerData: testReplayProviderData(t, "datastream_update_schedules")}
+
+	state := testReplayCreate(t, r, testReplayDatastreamPlan(testReplaySchedule("daily", "CRON_EVERY_DAY", 2)))
+
+	var plan datastreamResourceModel
+	if diags := state.Get(t.Context(), &plan); diags.HasError() {
+		t.Fatal(diags)
+	}
+	plan.DataType = types.StringValue("Staging")
+	plan.Schedules = append(plan.Schedules, testReplaySchedule("hourly", "CRON_EVERY_HOUR", 1))
+	plan.LastUpdated = types.StringUnknown()
+
+	state = testReplayUpdate(t, r, state, plan)
+
+	var datastream datastreamResourceModel
+	if diags := state.Get(t.Context(), &datastream); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if datastream.DataType.ValueString() != "Staging" {
+		t.Errorf("datatype = %s, want Staging", datastream.DataType)
+	}
+	if len(datastream.Schedules) != 2 || testReplayScheduleByKey(datastream.Schedules, "hourly").CronType.ValueString() != adverity.CronTypeHour {
+		t.Errorf("schedules = %+v, want the new hourly schedule with its cron fields", datastream.Schedules)
+	}
+}
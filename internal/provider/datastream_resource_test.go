@@ -0,0 +1,340 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccDatastreamResource(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+	var id, dailyScheduleID int64
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(server, "adverity_datastream", "datastream"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+					testAccDatastreamResourceConfig("Campaigns", testAccDailySchedule),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("name"), knownvalue.StringExact("Campaigns")),
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("enabled"), knownvalue.Bool(true)),
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("schedule"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"key": knownvalue.StringExact("daily"), "cron_type": knownvalue.StringExact("day")}),
+					})),
+				},
+				Check: func(s *terraform.State) (err error) {
+					if id, err = testAccResourceID(s, "adverity_datastream.test"); err != nil {
+						return err
+					}
+					dailyScheduleID, err = testAccScheduleID(s, "adverity_datastream.test", "daily")
+					return err
+				},
+			},
+			// ImportState testing, imported schedules have no key
+			{
+				ResourceName:            "adverity_datastream.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateId("adverity_datastream.test", "datastream_type_id", "id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "manage_schedules", "schedule"},
+			},
+			// Update testing: adding a schedule does not replace the existing one
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+					testAccDatastreamResourceConfig("Campaigns", testAccHourlySchedule+testAccDailySchedule),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_datastream.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("schedule"), knownvalue.SetSizeExact(2)),
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("schedule"), knownvalue.SetPartial([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"key": knownvalue.StringExact("hourly"), "cron_interval": knownvalue.Int64Exact(1)}),
+					})),
+				},
+				Check: func(s *terraform.State) error {
+					daily, err := testAccScheduleID(s, "adverity_datastream.test", "daily")
+					if err == nil && daily != dailyScheduleID {
+						err = fmt.Errorf("daily schedule id = %d, want the id %d of the existing schedule", daily, dailyScheduleID)
+					}
+					return err
+				},
+			},
+			// Reordering the schedules plans no changes
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+					testAccDatastreamResourceConfig("Campaigns", testAccDailySchedule+testAccHourlySchedule),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Drift testing: a disabled datastream is enabled again
+			{
+				PreConfig: func() {
+					if err := server.Modify("datastream", id, map[string]any{"enabled": false}); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+					testAccDatastreamResourceConfig("Campaigns", testAccHourlySchedule+testAccDailySchedule),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_datastream.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("enabled"), knownvalue.Bool(true)),
+				},
+			},
+			// Move testing: the datastream is moved to another workspace in place
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+					strings.Replace(testAccDatastreamResourceConfig("Campaigns", testAccHourlySchedule+testAccDailySchedule), "adverity_workspace.test.id", "1", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_datastream.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_datastream.test", tfjsonpath.New("stack_id"), knownvalue.Int64Exact(1)),
+				},
+				Check: func(s *terraform.State) error {
+					moved, err := testAccResourceID(s, "adverity_datastream.test")
+					if err == nil && moved != id {
+						err = fmt.Errorf("datastream id = %d, want the id %d of the moved datastream", moved, id)
+					}
+					return err
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccDatastreamResourceWithoutSchedules(t *testing.T) {
+	_, providerConfig := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The default schedule added by Adverity is removed, otherwise the plan after the apply is not empty
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+					testAccDatastreamResourceConfig("Campaigns", ""),
+			},
+		},
+	})
+}
+
+const (
+	testAccDailySchedule = `
+  schedule {
+    key         = "daily"
+    cron_preset = "CRON_EVERY_DAY"
+    time_range_preset = 2
+  }
+`
+	testAccHourlySchedule = `
+  schedule {
+    key         = "hourly"
+    cron_preset = "CRON_EVERY_HOUR"
+    time_range_preset = 1
+  }
+`
+)
+
+// testAccScheduleID returns the numeric ID of the schedule with the key of a resource in the state.
+func testAccScheduleID(s *terraform.State, resourceName, key string) (int64, error) {
+	rs, ok := s.RootModule().Resources[resourceName]
+	if !ok {
+		return 0, fmt.Errorf("resource %s not found in state", resourceName)
+	}
+	for name, value := range rs.Primary.Attributes {
+		if prefix, ok := strings.CutSuffix(name, ".key"); ok && strings.HasPrefix(prefix, "schedule.") && value == key {
+			return strconv.ParseInt(rs.Primary.Attributes[prefix+".id"], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("schedule %q of resource %s not found in state", key, resourceName)
+}
+
+func testAccDatastreamResourceConfig(name, schedules string) string {
+	return fmt.Sprintf(`
+resource "adverity_datastream" "test" {
+  datastream_type_id = %[2]d
+  name               = %[1]q
+  stack_id           = adverity_workspace.test.id
+  auth_id            = adverity_authorization.test.id
+  enabled            = true
+%[3]s}
+`, name, testAccDatastreamTypeID, schedules)
+}
+
+func TestPlanSchedules(t *testing.T) {
+	r := &datastreamResource{}
+	configured := func(key, cronPreset string, timeRangePreset int64) datastreamScheduleModel {
+		return datastreamScheduleModel{Key: types.StringValue(key), CronPreset: types.StringValue(cronPreset), TimeRangePreset: types.Int64Value(timeRangePreset)}
+	}
+	keyless := func(cronPreset string, timeRangePreset int64) datastreamScheduleModel {
+		return datastreamScheduleModel{CronPreset: types.StringValue(cronPreset), TimeRangePreset: types.Int64Value(timeRangePreset)}
+	}
+	stored := func(schedule datastreamScheduleModel, id int64) datastreamScheduleModel {
+		preset := schedule.CronPreset.ValueString()
+		s := flattenSchedule(adverity.Schedule{ID: &id, CronPreset: &preset, TimeRangePreset: schedule.TimeRangePreset.ValueInt64Pointer()})
+		s.Key = schedule.Key
+		return s
+	}
+
+	prior := []datastreamScheduleModel{
+		stored(configured("daily", "CRON_EVERY_DAY", 2), 10),
+		stored(keyless("CRON_EVERY_HOUR", 1), 11),
+		stored(keyless("CRON_EVERY_WEEKDAY", 2), 12),
+	}
+
+	tests := map[string]struct {
+		config []datastreamScheduleModel
+		// want maps the keys (or the cron presets of schedules without a key) to the planned IDs, 0 for new schedules
+		want map[string]int64
+	}{
+		"reordered": {
+			config: []datastreamScheduleModel{
+				keyless("CRON_EVERY_WEEKDAY", 2),
+				keyless("CRON_EVERY_HOUR", 1),
+				configured("daily", "CRON_EVERY_DAY", 2),
+			},
+			want: map[string]int64{"daily": 10, "CRON_EVERY_HOUR": 11, "CRON_EVERY_WEEKDAY": 12},
+		},
+		"keyed schedule changed in place, keyless schedule replaced": {
+			config: []datastreamScheduleModel{
+				configured("daily", "CRON_EVERY_DAY", 1),
+				keyless("CRON_EVERY_HOUR", 2),
+			},
+			want: map[string]int64{"daily": 10, "CRON_EVERY_HOUR": 0},
+		},
+		"key added to a schedule without one": {
+			config: []datastreamScheduleModel{
+				configured("hourly", "CRON_EVERY_HOUR", 1),
+			},
+			want: map[string]int64{"hourly": 11},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := testReplayDatastreamPlan(prior...)
+			state.ID = types.Int64Value(1)
+
+			var diags diag.Diagnostics
+			planned, ok := planSchedules(t.Context(), tfsdk.Config(testReplayState(t, r, testReplayDatastreamPlan(test.config...))), testReplayState(t, r, state), &diags)
+			if !ok || diags.HasError() {
+				t.Fatalf("planSchedules() = %t, diagnostics %v", ok, diags)
+			}
+
+			got := make(map[string]int64, len(planned))
+			for _, p := range planned {
+				name := p.Key.ValueString()
+				if p.Key.IsNull() {
+					name = p.CronPreset.ValueString()
+				}
+				got[name] = p.ID.ValueInt64()
+			}
+			if !maps.Equal(got, test.want) {
+				t.Errorf("planned IDs = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPlanManageSchedules(t *testing.T) {
+	r := &datastreamResource{}
+	daily := datastreamScheduleModel{Key: types.StringValue("daily"), CronPreset: types.StringValue("CRON_EVERY_DAY")}
+
+	tests := map[string]struct {
+		manageSchedules types.Bool
+		schedules       []datastreamScheduleModel
+		want            types.Bool
+	}{
+		"without schedule blocks":                  {manageSchedules: types.BoolNull(), want: types.BoolValue(false)},
+		"with schedule blocks":                     {manageSchedules: types.BoolNull(), schedules: []datastreamScheduleModel{daily}, want: types.BoolValue(true)},
+		"configured true without schedule blocks":  {manageSchedules: types.BoolValue(true), want: types.BoolValue(true)},
+		"configured false without schedule blocks": {manageSchedules: types.BoolValue(false), want: types.BoolValue(false)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testReplayDatastreamPlan(test.schedules...)
+			config.ManageSchedules = test.manageSchedules
+			planned := config
+			if test.manageSchedules.IsNull() {
+				planned.ManageSchedules = types.BoolUnknown()
+			}
+			plan := tfsdk.Plan(testReplayState(t, r, planned))
+
+			var diags diag.Diagnostics
+			planManageSchedules(t.Context(), tfsdk.Config(testReplayState(t, r, config)), &plan, &diags)
+			var got types.Bool
+			diags.Append(plan.GetAttribute(t.Context(), path.Root("manage_schedules"), &got)...)
+			if diags.HasError() {
+				t.Fatalf("diagnostics = %v", diags)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("manage_schedules = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFlattenSchedulesCronExpression(t *testing.T) {
+	id, cronType, interval, intervalStart, startOfDay := int64(7), adverity.CronTypeWeekday, int64(1), int64(0), "03:30:00"
+	everyTwoDays, twoDays := int64(8), int64(2)
+	daily := adverity.CronTypeDay
+	imported := []adverity.Schedule{
+		{ID: &id, CronType: &cronType, CronInterval: &interval, CronIntervalStart: &intervalStart, CronStartOfDay: &startOfDay},
+		{ID: &everyTwoDays, CronType: &daily, CronInterval: &twoDays, CronIntervalStart: &intervalStart, CronStartOfDay: &startOfDay},
+	}
+
+	// Imported schedules get the cron expression of their cron fields, if cron syntax can express them
+	schedules := flattenSchedules(imported, nil)
+	if len(schedules) != 2 {
+		t.Fatalf("schedules = %+v, want 2 schedules", schedules)
+	}
+	if got := schedules[0].CronExpression; !got.Equal(types.StringValue("30 3 * * 1-5")) {
+		t.Errorf("cron_expression = %s, want 30 3 * * 1-5", got)
+	}
+	if got := schedules[1].CronExpression; !got.IsNull() {
+		t.Errorf("cron_expression of a schedule every 2 days = %s, want null", got)
+	}
+
+	// An equivalent configured expression is kept
+	prior := flattenSchedule(imported[0])
+	prior.CronExpression = types.StringValue("30 3 * * MON-FRI")
+	schedules = flattenSchedules(imported[:1], []datastreamScheduleModel{prior})
+	if got := schedules[0].CronExpression; !got.Equal(prior.CronExpression) {
+		t.Errorf("cron_expression = %s, want the configured %s", got, prior.CronExpression)
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                     = &datastreamScheduleResource{}
+	_ resource.ResourceWithConfigure        = &datastreamScheduleResource{}
+	_ resource.ResourceWithImportState      = &datastreamScheduleResource{}
+	_ resource.ResourceWithValidateConfig   = &datastreamScheduleResource{}
+	_ resource.ResourceWithConfigValidators = &datastreamScheduleResource{}
+	_ resource.ResourceWithModifyPlan       = &datastreamScheduleResource{}
+)
+
+// NewDatastreamScheduleResource is a helper function to simplify the provider implementation.
+func NewDatastreamScheduleResource() resource.Resource {
+	return &datastreamScheduleResource{}
+}
+
+// datastreamScheduleResource is the resource implementation.
+type datastreamScheduleResource struct {
+	providerData *providerData
+}
+
+// datastreamScheduleResourceModel maps the resource schema data.
+type datastreamScheduleResourceModel struct {
+	DatastreamTypeId types.Int64               `tfsdk:"datastream_type_id"`
+	DatastreamId     types.Int64               `tfsdk:"datastream_id"`
+	ID               types.Int64               `tfsdk:"id"`
+	Schedules        []datastreamScheduleModel `tfsdk:"schedule"`
+	Instance         types.String              `tfsdk:"instance"`
+	LastUpdated      types.String              `tfsdk:"last_updated"`
+}
+
+func (r *datastreamScheduleResource) refreshState(datastream *adverity.DatastreamResponse, state *datastreamScheduleResourceModel) {
+	state.ID = types.Int64Value(datastream.ID)
+	state.DatastreamId = types.Int64Value(datastream.ID)
+	state.Schedules = flattenSchedules(datastream.Schedules, state.Schedules)
+}
+
+// ConfigValidators returns the validators checking the semantics of the schedules.
+func (r *datastreamScheduleResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		scheduleConfigValidator{},
+	}
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *datastreamScheduleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateScheduleKeys(ctx, req.Config, &resp.Diagnostics)
+}
+
+// ModifyPlan aligns the planned schedules with the prior state.
+func (r *datastreamScheduleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to align on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	schedules, ok := planSchedules(ctx, req.Config, req.State, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schedule"), schedules)...)
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *datastreamScheduleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+// Metadata returns the resource type name.
+func (r *datastreamScheduleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_datastream_schedule"
+}
+
+// Schema defines the schema for the resource.
+func (r *datastreamScheduleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the schedules of a datastream. " +
+			"The datastream must not declare inline schedule blocks, without them it leaves the schedules to this resource.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "Numeric identifier of the datastream schedules (same as datastream_id).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"instance": instanceAttribute(),
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the datastream schedules.",
+				Computed:    true,
+			},
+			"datastream_type_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the datastream type.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"datastream_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the datastream.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"schedule": datastreamScheduleBlock(),
+		},
+	}
+}
+
+// Create a new resource.
+func (r *datastreamScheduleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan datastreamScheduleResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The schedules are read and written with separate requests,
+	// which must not interleave with other changes of the datastream
+	unlock := client.LockDatastream(int(plan.DatastreamId.ValueInt64()))
+	defer unlock()
+
+	// Check for schedules which are already present on the datastream (e.g. inline schedule blocks)
+	datastream, err := client.ReadDatastream(ctx, int(plan.DatastreamTypeId.ValueInt64()), int(plan.DatastreamId.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Adverity datastream",
+			"Could not read datastream, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if len(datastream.Schedules) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Datastream already has schedules",
+			fmt.Sprintf("Datastream %d already has %d schedule(s) which will be replaced. ", datastream.ID, len(datastream.Schedules))+
+				"If the adverity_datastream resource declares inline schedule blocks, both resources will overwrite each other's schedules. "+
+				"Remove the schedule blocks from the datastream, which then leaves its schedules to this resource.",
+		)
+	}
+
+	// Generate API request body from plan
+	payload := &adverity.DatastreamScheduleConfig{
+		Schedules: expandSchedules(plan.Schedules),
+	}
+
+	// Replace the schedules of the datastream
+	_, err = client.UpdateDatastreamSchedule(ctx, int(plan.DatastreamId.ValueInt64()), payload)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating datastream schedule",
+			"Could not create datastream schedule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Read the datastream again since not all fields are populated by the schedule endpoint
+	datastream, err = client.ReadDatastream(ctx, int(plan.DatastreamTypeId.ValueInt64()), int(plan.DatastreamId.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Adverity datastream",
+			"Could not read datastream, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to schema and populate computed attribute values
+	r.refreshState(datastream, &plan)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *datastreamScheduleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state datastreamScheduleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get refreshed datastream value from Adverity
+	datastream, err := client.ReadDatastream(ctx, int(state.DatastreamTypeId.ValueInt64()), int(state.DatastreamId.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Adverity datastream schedule",
+			"Could not read datastream schedule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite state with refreshed attributes
+	r.refreshState(datastream, &state)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *datastreamScheduleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and prior state
+	var plan, state datastreamScheduleResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The schedules are read and written with separate requests,
+	// which must not interleave with other changes of the datastream
+	unlock := client.LockDatastream(int(plan.DatastreamId.ValueInt64()))
+	defer unlock()
+
+	// Generate API request body from plan, only sending the schedules if any of them were added, changed or removed
+	if schedules, changed := reconcileSchedules(plan.Schedules, state.Schedules); changed {
+		payload := &adverity.DatastreamScheduleConfig{
+			Schedules: schedules,
+		}
+
+		// Update existing datastream schedule
+		// We ignore the returned body since not all fields are populated by this endpoint for a state refresh
+		_, err := client.UpdateDatastreamSchedule(ctx, int(plan.DatastreamId.ValueInt64()), payload)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating Adverity datastream schedule",
+				"Could not update datastream schedule, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	datastream, err := client.ReadDatastream(ctx, int(plan.DatastreamTypeId.ValueInt64()), int(plan.DatastreamId.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Adverity datastream",
+			"Could not read datastream, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update resource state with updated attributes and timestamp
+	r.refreshState(datastream, &plan)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete removes all schedules from the datastream and removes the Terraform state on success.
+func (r *datastreamScheduleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state datastreamScheduleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Removing the schedules must not interleave with other changes of the datastream
+	unlock := client.LockDatastream(int(state.DatastreamId.ValueInt64()))
+	defer unlock()
+
+	// Remove all schedules from the datastream
+	emptySchedules := make([]adverity.Schedule, 0)
+	payload := &adverity.DatastreamScheduleConfig{
+		Schedules: &emptySchedules,
+	}
+	_, err := client.UpdateDatastreamSchedule(ctx, int(state.DatastreamId.ValueInt64()), payload)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting Adverity datastream schedule",
+			"Could not delete datastream schedule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *datastreamScheduleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
+	// Split the composite import ID (<datastream_type_id>:<datastream_id>) into its parts
+	parts := utils.SplitImportParts(importID, 2, "<datastream_type_id>:<datastream_id>", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Parse each part as an integer
+	dsTypeId := utils.ParseImportPartInt(parts[0], "datastream_type_id", &resp.Diagnostics)
+	dsId := utils.ParseImportPartInt(parts[1], "datastream_id", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set the parsed values in state
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("datastream_type_id"), dsTypeId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("datastream_id"), dsId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), dsId)...)
+}
@@ -0,0 +1,151 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.ConfigValidator = scheduleConfigValidator{}
+
+var cronTypes = []string{
+	adverity.CronTypeHour,
+	adverity.CronTypeDay,
+	adverity.CronTypeWeekday,
+	adverity.CronTypeWeek,
+	adverity.CronTypeMonth,
+}
+
+// scheduleDateFormat matches the dates accepted by validators.DateYYYYMMDD, which include
+// dates that don't exist in the calendar (e.g. 2025-02-31).
+var scheduleDateFormat = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])$`)
+
+// scheduleConfigValidator validates the semantics of the schedule blocks across their attributes,
+// which the per-attribute format validators cannot check.
+type scheduleConfigValidator struct{}
+
+func (v scheduleConfigValidator) Description(_ context.Context) string {
+	return "Schedules must use supported cron presets and types, real calendar dates and consistent time ranges, and custom time ranges require fixed_start"
+}
+
+func (v scheduleConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v scheduleConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var configSet types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedule"), &configSet)...)
+	if resp.Diagnostics.HasError() || configSet.IsUnknown() {
+		return
+	}
+
+	var configured []datastreamScheduleModel
+	resp.Diagnostics.Append(configSet.ElementsAs(ctx, &configured, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i, schedule := range configured {
+		validateSchedule(path.Root("schedule").AtSetValue(configSet.Elements()[i]), schedule, &resp.Diagnostics)
+	}
+}
+
+// validateSchedule validates a single schedule block. Unknown values are skipped.
+func validateSchedule(p path.Path, schedule datastreamScheduleModel, diags *diag.Diagnostics) {
+	if known(schedule.CronPreset) {
+		if _, ok := adverity.CronPresets[schedule.CronPreset.ValueString()]; !ok {
+			presets := make([]string, 0, len(adverity.CronPresets))
+			for preset := range adverity.CronPresets {
+				presets = append(presets, preset)
+			}
+			slices.Sort(presets)
+			diags.AddAttributeError(
+				p.AtName("cron_preset"),
+				"Invalid cron preset",
+				fmt.Sprintf("The cron preset %q is not supported, expected one of %q.", schedule.CronPreset.ValueString(), presets),
+			)
+		}
+	}
+	if known(schedule.CronType) && !slices.Contains(cronTypes, schedule.CronType.ValueString()) {
+		diags.AddAttributeError(
+			p.AtName("cron_type"),
+			"Invalid cron type",
+			fmt.Sprintf("The cron type %q is not supported, expected one of %q.", schedule.CronType.ValueString(), cronTypes),
+		)
+	}
+
+	// The numeric delta_type and time_range_preset codes are passed through, since the provider
+	// cannot confirm which codes an instance supports. Their readable forms are validated by the schema.
+	custom := known(schedule.TimeRange) && schedule.TimeRange.ValueString() == adverity.TimeRanges[adverity.TimeRangePresetCustom]
+	if known(schedule.TimeRangePreset) {
+		custom = schedule.TimeRangePreset.ValueInt64() == adverity.TimeRangePresetCustom
+	}
+
+	fixedStart, fixedStartOk := parseScheduleDate(p.AtName("fixed_start"), schedule.FixedStart, diags)
+	fixedEnd, fixedEndOk := parseScheduleDate(p.AtName("fixed_end"), schedule.FixedEnd, diags)
+	parseScheduleDate(p.AtName("not_before_date"), schedule.NotBeforeDate, diags)
+
+	// A custom time range is defined by its fixed dates
+	if custom && schedule.FixedStart.IsNull() {
+		diags.AddAttributeError(
+			p.AtName("fixed_start"),
+			"Missing fixed start",
+			"A custom time range requires fixed_start.",
+		)
+	}
+	if fixedStartOk && fixedEndOk && fixedEnd.Before(fixedStart) {
+		diags.AddAttributeError(
+			p.AtName("fixed_end"),
+			"Invalid fixed end",
+			fmt.Sprintf("The fixed end %s must not be before the fixed start %s.", schedule.FixedEnd.ValueString(), schedule.FixedStart.ValueString()),
+		)
+	}
+
+	if !schedule.NotBeforeTime.IsNull() && schedule.NotBeforeDate.IsNull() {
+		diags.AddAttributeError(
+			p.AtName("not_before_time"),
+			"Missing not before date",
+			"A not before time requires a not before date.",
+		)
+	}
+}
+
+// parseScheduleDate parses a known date value and reports dates which don't exist in the calendar
+// (e.g. 2025-02-31). Values not in YYYY-MM-DD format are reported by the attribute validator.
+func parseScheduleDate(p path.Path, value types.String, diags *diag.Diagnostics) (time.Time, bool) {
+	if !known(value) || !scheduleDateFormat.MatchString(value.ValueString()) {
+		return time.Time{}, false
+	}
+
+	date, err := time.Parse(time.DateOnly, value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			p,
+			"Invalid date",
+			fmt.Sprintf("The date %s does not exist.", value.ValueString()),
+		)
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+func known(value interface {
+	IsNull() bool
+	IsUnknown() bool
+}) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
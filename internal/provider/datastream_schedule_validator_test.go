@@ -0,0 +1,90 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := map[string]struct {
+		schedule datastreamScheduleModel
+		// wantErrors are the attributes with an error and a part of its detail
+		wantErrors map[string]string
+	}{
+		"preset schedule": {
+			schedule: datastreamScheduleModel{CronPreset: types.StringValue("CRON_EVERY_DAY"), TimeRangePreset: types.Int64Value(2)},
+		},
+		"unknown values are skipped": {
+			schedule: datastreamScheduleModel{CronPreset: types.StringUnknown(), TimeRangePreset: types.Int64Unknown(), FixedStart: types.StringUnknown()},
+		},
+		"invalid cron preset": {
+			schedule:   datastreamScheduleModel{CronPreset: types.StringValue("CRON_EVERY_MINUTE")},
+			wantErrors: map[string]string{"cron_preset": `"CRON_EVERY_DAY"`},
+		},
+		"invalid cron type": {
+			schedule:   datastreamScheduleModel{CronType: types.StringValue("minute")},
+			wantErrors: map[string]string{"cron_type": `"hour"`},
+		},
+		"numeric codes are passed through": {
+			schedule: datastreamScheduleModel{DeltaType: types.Int64Value(5), TimeRangePreset: types.Int64Value(9)},
+		},
+		"custom time range with fixed start": {
+			schedule: datastreamScheduleModel{TimeRangePreset: types.Int64Value(0), FixedStart: types.StringValue("2025-01-01")},
+		},
+		"custom time range without fixed start": {
+			schedule:   datastreamScheduleModel{TimeRangePreset: types.Int64Value(0), FixedEnd: types.StringValue("2025-01-31")},
+			wantErrors: map[string]string{"fixed_start": "requires fixed_start"},
+		},
+		"custom time range relative to the run": {
+			schedule:   datastreamScheduleModel{TimeRange: types.StringValue("custom"), DeltaType: types.Int64Value(2), DeltaInterval: types.Int64Value(2)},
+			wantErrors: map[string]string{"fixed_start": "requires fixed_start"},
+		},
+		"date which does not exist": {
+			schedule:   datastreamScheduleModel{NotBeforeDate: types.StringValue("2025-02-31")},
+			wantErrors: map[string]string{"not_before_date": "2025-02-31 does not exist"},
+		},
+		"fixed end before fixed start": {
+			schedule:   datastreamScheduleModel{TimeRange: types.StringValue("custom"), FixedStart: types.StringValue("2025-02-01"), FixedEnd: types.StringValue("2025-01-31")},
+			wantErrors: map[string]string{"fixed_end": "must not be before the fixed start"},
+		},
+		"fixed end on the fixed start": {
+			schedule: datastreamScheduleModel{TimeRange: types.StringValue("custom"), FixedStart: types.StringValue("2025-02-01"), FixedEnd: types.StringValue("2025-02-01")},
+		},
+		"not before time without date": {
+			schedule:   datastreamScheduleModel{NotBeforeTime: types.StringValue("06:00:00")},
+			wantErrors: map[string]string{"not_before_time": "requires a not before date"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateSchedule(path.Root("schedule"), test.schedule, &diags)
+
+			got := make(map[string]string, diags.ErrorsCount())
+			for _, d := range diags.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				if !ok {
+					t.Fatalf("diagnostic %v has no path", d)
+				}
+				steps := withPath.Path().Steps()
+				got[steps[len(steps)-1].String()] = d.Detail()
+			}
+			if len(got) != len(test.wantErrors) {
+				t.Fatalf("errors = %v, want errors for %v", got, test.wantErrors)
+			}
+			for attribute, want := range test.wantErrors {
+				if !strings.Contains(got[attribute], want) {
+					t.Errorf("error of %s = %q, want it to contain %q", attribute, got[attribute], want)
+				}
+			}
+		})
+	}
+}
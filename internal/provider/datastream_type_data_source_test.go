@@ -0,0 +1,43 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccDatastreamTypeDataSource(t *testing.T) {
+	_, providerConfig := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccDatastreamTypeDataSourceConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.adverity_datastream_type.test",
+						tfjsonpath.New("results"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"google-ads-insights": knownvalue.Int64Exact(testAccDatastreamTypeID),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccDatastreamTypeDataSourceConfig = `
+data "adverity_datastream_type" "test" {
+  search_term = "google"
+}
+`
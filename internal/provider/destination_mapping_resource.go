@@ -6,6 +6,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
@@ -14,6 +15,7 @@ import (
 	"terraform-provider-adverity/internal/adverity"
 	"terraform-provider-adverity/internal/provider/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -27,6 +29,7 @@ var (
 	_ resource.Resource                = &destinationMappingResource{}
 	_ resource.ResourceWithConfigure   = &destinationMappingResource{}
 	_ resource.ResourceWithImportState = &destinationMappingResource{}
+	_ resource.ResourceWithModifyPlan  = &destinationMappingResource{}
 )
 
 // NewDestinationMappingResource is a helper function to simplify the provider implementation.
@@ -36,7 +39,7 @@ func NewDestinationMappingResource() resource.Resource {
 
 // destinationMappingResource is the resource implementation.
 type destinationMappingResource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // destinationMappingResourceModel maps the resource schema data.
@@ -48,6 +51,7 @@ type destinationMappingResourceModel struct {
 	Enabled           types.Bool    `tfsdk:"enabled"`
 	TableName         types.String  `tfsdk:"table_name"`
 	Parameters        types.Dynamic `tfsdk:"parameters"`
+	Instance          types.String  `tfsdk:"instance"`
 	LastUpdated       types.String  `tfsdk:"last_updated"`
 }
 
@@ -58,6 +62,71 @@ func (r *destinationMappingResource) refreshState(destinationMapping *adverity.D
 	state.TableName = types.StringValue(destinationMapping.TableName)
 }
 
+// ModifyPlan validates the parameters against the field metadata of the destination.
+func (r *destinationMappingResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var typeId types.Int64
+	var destinationId types.Int64
+	var parameters types.Dynamic
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("destination_type_id"), &typeId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("destination_id"), &destinationId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+	if resp.Diagnostics.HasError() || typeId.IsUnknown() || destinationId.IsUnknown() {
+		return
+	}
+
+	// Validate against the field metadata of the planned instance
+	client := r.providerData.planClient(ctx, req.Plan, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+
+	readFields := func() (map[string]adverity.FieldMetadata, error) {
+		return client.ReadDestinationMappingFields(ctx, int(typeId.ValueInt64()), int(destinationId.ValueInt64()))
+	}
+	utils.ValidateParametersWithMetadata([]utils.ParameterSource{utils.ParametersSource(parameters)}, readFields, adverity.PayloadFields(adverity.DestinationMappingConfig{}), &resp.Diagnostics)
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *destinationMappingResource) updatePayload(model destinationMappingResourceModel, diags *diag.Diagnostics) *adverity.DestinationMappingConfig {
+	payload := &adverity.DestinationMappingConfig{
+		DatastreamId: model.DatastreamId.ValueInt64Pointer(),
+		Enabled:      model.Enabled.ValueBoolPointer(),
+		Parameters:   utils.ExpandDynamicParameters(model.Parameters, path.Root("parameters"), diags),
+	}
+	if !model.TableName.IsUnknown() {
+		payload.TableName = model.TableName.ValueStringPointer()
+	}
+	return payload
+}
+
+// lockObjects serializes the changes of mappings of the same datastream or destination, which Terraform
+// applies in parallel, with each other and with the other changes of the datastreams. A mapping moving to
+// another datastream locks both datastreams, in ascending order to avoid deadlocks.
+func (r *destinationMappingResource) lockObjects(client *adverity.Client, destinationId types.Int64, datastreamIds ...types.Int64) func() {
+	ids := make([]int, 0, len(datastreamIds))
+	for _, id := range datastreamIds {
+		ids = append(ids, int(id.ValueInt64()))
+	}
+	slices.Sort(ids)
+
+	var unlocks []func()
+	for _, id := range slices.Compact(ids) {
+		unlocks = append(unlocks, client.LockDatastream(id))
+	}
+	unlocks = append(unlocks, client.LockDestination(int(destinationId.ValueInt64())))
+
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *destinationMappingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -66,18 +135,18 @@ func (r *destinationMappingResource) Configure(_ context.Context, req resource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
 }
 
 // Metadata returns the resource type name.
@@ -97,6 +166,7 @@ func (r *destinationMappingResource) Schema(_ context.Context, _ resource.Schema
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"instance": instanceAttribute(),
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the destination mapping.",
 				Computed:    true,
@@ -104,10 +174,16 @@ func (r *destinationMappingResource) Schema(_ context.Context, _ resource.Schema
 			"destination_type_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the destination mapping type.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"destination_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the destination mapping type.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"datastream_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the datastream.",
@@ -147,6 +223,16 @@ func (r *destinationMappingResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Lock the datastreams and the destination of the mapping
+	unlock := r.lockObjects(client, plan.DestinationId, plan.DatastreamId)
+	defer unlock()
+
 	// Generate API request body from plan
 	payload := &adverity.DestinationMappingConfig{
 		DatastreamId: plan.DatastreamId.ValueInt64Pointer(),
@@ -166,7 +252,7 @@ func (r *destinationMappingResource) Create(ctx context.Context, req resource.Cr
 	}
 
 	// Create new destination mapping
-	destinationMapping, err := r.client.CreateDestinationMapping(int(plan.DestinationTypeId.ValueInt64()), int(plan.DestinationId.ValueInt64()), payload)
+	destinationMapping, err := client.CreateDestinationMapping(ctx, int(plan.DestinationTypeId.ValueInt64()), int(plan.DestinationId.ValueInt64()), payload)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating destination mapping",
@@ -197,8 +283,14 @@ func (r *destinationMappingResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get refreshed destination mapping value from Adverity
-	destinationMapping, err := r.client.ReadDestinationMapping(int(state.DestinationTypeId.ValueInt64()), int(state.DestinationId.ValueInt64()), int(state.ID.ValueInt64()))
+	destinationMapping, err := r.providerData.readDestinationMapping(ctx, client, state.DestinationTypeId, state.DestinationId, state.ID)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Adverity destination mapping",
@@ -219,34 +311,43 @@ func (r *destinationMappingResource) Read(ctx context.Context, req resource.Read
 }
 
 func (r *destinationMappingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan destinationMappingResourceModel
+	// Retrieve values from plan and prior state
+	var plan, state destinationMappingResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Generate API request body from plan
-	payload := &adverity.DestinationMappingConfig{
-		DatastreamId: plan.DatastreamId.ValueInt64Pointer(),
-		Enabled:      plan.Enabled.ValueBoolPointer(),
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !plan.TableName.IsUnknown() {
-		payload.TableName = plan.TableName.ValueStringPointer()
-	}
+	// Lock the datastreams and the destination of the mapping
+	unlock := r.lockObjects(client, plan.DestinationId, state.DatastreamId, plan.DatastreamId)
+	defer unlock()
 
-	if !plan.Parameters.IsNull() {
-		parameters := utils.ExpandParameters(plan.Parameters.UnderlyingValue(), path.Root("parameters"), &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		payload.Parameters = &parameters
+	// Generate API request body with the changes from the prior state to the plan, which leaves
+	// the table name left to Adverity unchanged
+	patchPlan := plan
+	patchPlan.TableName = utils.KnownOrPrior(state.TableName, plan.TableName)
+	payload := utils.PatchPayload(r.updatePayload(state, &resp.Diagnostics), r.updatePayload(patchPlan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Update existing destination mapping
-	destinationMapping, err := r.client.UpdateDestinationMapping(int(plan.DestinationTypeId.ValueInt64()), int(plan.DestinationId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
+	// Update existing destination mapping, or read it if nothing changed
+	var destinationMapping *adverity.DestinationMappingResponse
+	var err error
+	if utils.EmptyPatch(payload) {
+		destinationMapping, err = client.ReadDestinationMapping(ctx, int(plan.DestinationTypeId.ValueInt64()), int(plan.DestinationId.ValueInt64()), int(plan.ID.ValueInt64()))
+	} else {
+		destinationMapping, err = client.UpdateDestinationMapping(ctx, int(plan.DestinationTypeId.ValueInt64()), int(plan.DestinationId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Adverity destination mapping",
@@ -276,8 +377,18 @@ func (r *destinationMappingResource) Delete(ctx context.Context, req resource.De
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Lock the datastreams and the destination of the mapping
+	unlock := r.lockObjects(client, state.DestinationId, state.DatastreamId)
+	defer unlock()
+
 	// Delete existing destination mapping
-	_, err := r.client.DeleteDestinationMapping(int(state.DestinationTypeId.ValueInt64()), int(state.DestinationId.ValueInt64()), int(state.ID.ValueInt64()))
+	_, err := client.DeleteDestinationMapping(ctx, int(state.DestinationTypeId.ValueInt64()), int(state.DestinationId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Adverity destination mapping",
@@ -288,8 +399,11 @@ func (r *destinationMappingResource) Delete(ctx context.Context, req resource.De
 }
 
 func (r *destinationMappingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
 	// Split the composite import ID (<destination_type_id>:<destination_id>:<id>) into its parts
-	parts := utils.SplitImportParts(req.ID, 3, "<destination_type_id>:<destination_id>:<id>", &resp.Diagnostics)
+	parts := utils.SplitImportParts(importID, 3, "<destination_type_id>:<destination_id>:<id>", &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
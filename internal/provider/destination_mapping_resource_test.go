@@ -0,0 +1,106 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccDestinationMappingResource(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(server, "adverity_destination_mapping", "mapping"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccDestinationMappingResourceConfig("campaigns"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_destination_mapping.test", tfjsonpath.New("table_name"), knownvalue.StringExact("campaigns")),
+					statecheck.ExpectKnownValue("adverity_destination_mapping.test", tfjsonpath.New("enabled"), knownvalue.Bool(true)),
+					statecheck.CompareValuePairs(
+						"adverity_destination_mapping.test", tfjsonpath.New("datastream_id"),
+						"adverity_datastream.test", tfjsonpath.New("id"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:            "adverity_destination_mapping.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateId("adverity_destination_mapping.test", "destination_type_id", "destination_id", "id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccDestinationMappingResourceConfig("campaigns_emea"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_destination_mapping.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_destination_mapping.test", tfjsonpath.New("table_name"), knownvalue.StringExact("campaigns_emea")),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccDestinationMappingResourceBatchRefresh(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+	providerConfig = strings.Replace(providerConfig, "provider \"adverity\" {", "provider \"adverity\" {\n  refresh_strategy = \"batch\"", 1)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccDestinationMappingResourceConfig("campaigns"),
+			},
+			// The datastream, destination and mapping are refreshed from the lists of the workspace and the destination
+			{
+				RefreshState: true,
+				Check: func(*terraform.State) error {
+					requests := server.Requests()
+					for _, want := range []string{"GET /api/datastreams/", "GET /api/targets/"} {
+						if !slices.Contains(requests, want) {
+							return fmt.Errorf("requests do not contain %q: %q", want, requests)
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccDestinationMappingResourceConfig(tableName string) string {
+	return testAccWorkspaceConfig + testAccAuthorizationResourceConfig("Google Ads") +
+		testAccDatastreamResourceConfig("Campaigns", testAccDailySchedule) +
+		testAccDestinationResourceConfig("Warehouse", "") + fmt.Sprintf(`
+resource "adverity_destination_mapping" "test" {
+  destination_type_id = adverity_destination.test.destination_type_id
+  destination_id      = adverity_destination.test.id
+  datastream_id       = adverity_datastream.test.id
+  table_name          = %[1]q
+}
+`, tableName)
+}
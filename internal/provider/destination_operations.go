@@ -0,0 +1,86 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The requests shared by the destination resource and the typed destination resources. Each adds an
+// error to diags and returns nil on failure.
+
+// createDestination creates a destination of the type from payload, or adopts the existing destination
+// with the same name (see createOrAdopt) and updates it to payload. Destinations have no field to carry
+// a client token, so a destination a failed create request may have created is identified by its name,
+// type and workspace. It is only adopted if it is the only such destination, since an existing one with
+// the same name cannot be told apart.
+func createDestination(ctx context.Context, client *adverity.Client, typeId, stackId types.Int64, name types.String, adoptExisting types.Bool, payload *adverity.DestinationConfig, diags *diag.Diagnostics) *adverity.DestinationResponse {
+	destination, existing := createOrAdopt(ctx, "destination", name.ValueString(), adoptExisting.ValueBool(),
+		func() (*adverity.DestinationResponse, error) {
+			return client.CreateDestination(ctx, int(typeId.ValueInt64()), payload)
+		},
+		func() ([]adverity.DestinationResponse, error) {
+			return client.FindDestinations(ctx, int(stackId.ValueInt64()), name.ValueString())
+		},
+		func(d adverity.DestinationResponse) int64 { return d.ID },
+		func(d adverity.DestinationResponse) bool {
+			// The destinations of other types are not found by their typed endpoint
+			_, err := client.ReadDestination(ctx, int(typeId.ValueInt64()), int(d.ID))
+			return err == nil
+		},
+		diags,
+	)
+	if diags.HasError() || !existing {
+		return destination
+	}
+
+	// Update the adopted destination to the plan
+	destination, err := client.UpdateDestination(ctx, int(typeId.ValueInt64()), int(destination.ID), payload)
+	if err != nil {
+		diags.AddError(
+			"Error updating Adverity destination",
+			"Could not update the adopted destination, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	return destination
+}
+
+// readDestinationState reads a destination to refresh the state of a resource (see readDestination).
+func (p *providerData) readDestinationState(ctx context.Context, client *adverity.Client, stackId, typeId, id types.Int64, fields []string, diags *diag.Diagnostics) *adverity.DestinationResponse {
+	destination, err := p.readDestination(ctx, client, stackId, typeId, id, fields)
+	if err != nil {
+		diags.AddError(
+			"Error reading Adverity destination",
+			"Could not read destination, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	return destination
+}
+
+// updateDestination sends the changes of a destination, or reads it if nothing changed.
+func updateDestination(ctx context.Context, client *adverity.Client, typeId, id types.Int64, payload *adverity.DestinationConfig, diags *diag.Diagnostics) *adverity.DestinationResponse {
+	var destination *adverity.DestinationResponse
+	var err error
+	if utils.EmptyPatch(payload) {
+		destination, err = client.ReadDestination(ctx, int(typeId.ValueInt64()), int(id.ValueInt64()))
+	} else {
+		destination, err = client.UpdateDestination(ctx, int(typeId.ValueInt64()), int(id.ValueInt64()), payload)
+	}
+	if err != nil {
+		diags.AddError(
+			"Error updating Adverity destination",
+			"Could not update destination, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+	return destination
+}
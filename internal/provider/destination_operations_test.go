@@ -0,0 +1,133 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/adverity/fakeserver"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Removing an optional attribute which Adverity computes keeps the value in Adverity instead of sending it as
+// removed, and an update without changes to the destination does not send a PATCH request.
+func TestDestinationResourceUpdateKeepsComputedValues(t *testing.T) {
+	server := testReplayServer(t)
+	client, err := adverity.NewClient(t.Context(), server.URL, adverity.WithToken(fakeserver.DefaultToken), adverity.WithCACertPEM([]byte(server.CertificatePEM())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, stack, headersFormatting := "Warehouse", int64(testReplayStackID), int64(3)
+	destination, err := client.CreateDestination(t.Context(), testAccDestinationTypeID, &adverity.DestinationConfig{Name: &name, StackID: &stack, HeadersFormatting: &headersFormatting})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New("test")()
+	var providerSchema provider.SchemaResponse
+	p.Schema(t.Context(), provider.SchemaRequest{}, &providerSchema)
+	ps, err := providerserver.NewProtocol6WithError(p)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	configured, err := ps.ConfigureProvider(t.Context(), &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.10.0",
+		Config: testProtoValue(t, providerSchema.Schema.Type().TerraformType(t.Context()), map[string]tftypes.Value{
+			"instance_url": tftypes.NewValue(tftypes.String, server.URL),
+			"auth_token":   tftypes.NewValue(tftypes.String, fakeserver.DefaultToken),
+			"ca_cert_pem":  tftypes.NewValue(tftypes.String, server.CertificatePEM()),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProtoDiagnostics(t, "ConfigureProvider()", configured.Diagnostics)
+
+	var schemaResp resource.SchemaResponse
+	NewDestinationResource().Schema(t.Context(), resource.SchemaRequest{}, &schemaResp)
+	typ := schemaResp.Schema.Type().TerraformType(t.Context())
+
+	config := map[string]tftypes.Value{
+		"destination_type_id": tftypes.NewValue(tftypes.Number, testAccDestinationTypeID),
+		"name":                tftypes.NewValue(tftypes.String, "Warehouse EMEA"),
+		"stack_id":            tftypes.NewValue(tftypes.Number, stack),
+	}
+	prior := maps.Clone(config)
+	prior["name"] = tftypes.NewValue(tftypes.String, name)
+	computed := map[string]tftypes.Value{
+		"id":                 tftypes.NewValue(tftypes.Number, destination.ID),
+		"headers_formatting": tftypes.NewValue(tftypes.String, "snake_lower"),
+		"last_updated":       tftypes.NewValue(tftypes.String, "Monday, 01-Jan-26 00:00:00 UTC"),
+	}
+	maps.Copy(prior, computed)
+	// Terraform proposes the prior values of computed attributes which are not configured
+	proposed := maps.Clone(config)
+	maps.Copy(proposed, computed)
+
+	// headers_formatting is removed from the configuration along with the rename
+	planned, err := ps.PlanResourceChange(t.Context(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "adverity_destination",
+		PriorState:       testProtoValue(t, typ, prior),
+		ProposedNewState: testProtoValue(t, typ, proposed),
+		Config:           testProtoValue(t, typ, config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProtoDiagnostics(t, "PlanResourceChange()", planned.Diagnostics)
+
+	applied, err := ps.ApplyResourceChange(t.Context(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     "adverity_destination",
+		PriorState:   testProtoValue(t, typ, prior),
+		PlannedState: planned.PlannedState,
+		Config:       testProtoValue(t, typ, config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProtoDiagnostics(t, "ApplyResourceChange()", applied.Diagnostics)
+
+	var values map[string]tftypes.Value
+	if state, err := applied.NewState.Unmarshal(typ); err != nil || state.As(&values) != nil {
+		t.Fatalf("NewState = %v, want an object", err)
+	}
+	if !values["headers_formatting"].Equal(computed["headers_formatting"]) {
+		t.Errorf("headers_formatting = %s, want the prior value", values["headers_formatting"])
+	}
+	updated, err := client.ReadDestination(t.Context(), testAccDestinationTypeID, int(destination.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Warehouse EMEA" || updated.HeadersFormatting != headersFormatting {
+		t.Errorf("destination = %q with headers formatting %d, want the renamed destination with %d", updated.Name, updated.HeadersFormatting, headersFormatting)
+	}
+
+	// Without changes, the destination is read instead of sending an empty PATCH request
+	r := &destinationResource{providerData: &providerData{defaultClient: client}}
+	raw, err := applied.NewState.Unmarshal(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+	start := len(server.Requests())
+	resp := resource.UpdateResponse{State: state}
+	r.Update(t.Context(), resource.UpdateRequest{Plan: tfsdk.Plan(state), State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() diagnostics = %v", resp.Diagnostics)
+	}
+	want := []string{"GET /api/target-types/30/targets/" + strconv.FormatInt(destination.ID, 10) + "/"}
+	if got := server.Requests()[start:]; !slices.Equal(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
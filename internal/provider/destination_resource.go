@@ -11,11 +11,15 @@ import (
 	"terraform-provider-adverity/internal/adverity"
 	"terraform-provider-adverity/internal/provider/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -24,6 +28,7 @@ var (
 	_ resource.Resource                = &destinationResource{}
 	_ resource.ResourceWithConfigure   = &destinationResource{}
 	_ resource.ResourceWithImportState = &destinationResource{}
+	_ resource.ResourceWithModifyPlan  = &destinationResource{}
 )
 
 // NewDestinationResource is a helper function to simplify the provider implementation.
@@ -33,7 +38,7 @@ func NewDestinationResource() resource.Resource {
 
 // destinationResource is the resource implementation.
 type destinationResource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // destinationResourceModel maps the resource schema data.
@@ -47,9 +52,12 @@ type destinationResourceModel struct {
 	//ColumnNamesToLowerCase types.Bool    `tfsdk:"column_names_to_lowercase"`
 	//ForceString            types.Bool    `tfsdk:"force_string"`
 	//FormatHeaders          types.Bool    `tfsdk:"format_headers"`
-	//HeadersFormatting      types.Int64   `tfsdk:"headers_formatting"`
-	Parameters  types.Dynamic `tfsdk:"parameters"`
-	LastUpdated types.String  `tfsdk:"last_updated"`
+	HeadersFormatting   types.String  `tfsdk:"headers_formatting"`
+	Parameters          types.Dynamic `tfsdk:"parameters"`
+	SensitiveParameters types.Dynamic `tfsdk:"sensitive_parameters"`
+	AdoptExisting       types.Bool    `tfsdk:"adopt_existing"`
+	Instance            types.String  `tfsdk:"instance"`
+	LastUpdated         types.String  `tfsdk:"last_updated"`
 }
 
 func (r *destinationResource) refreshState(destination *adverity.DestinationResponse, state *destinationResourceModel) {
@@ -61,7 +69,58 @@ func (r *destinationResource) refreshState(destination *adverity.DestinationResp
 	//state.ColumnNamesToLowerCase = types.BoolValue(destination.ColumnNamesToLowerCase)
 	//state.ForceString = types.BoolValue(destination.ForceString)
 	//state.FormatHeaders = types.BoolValue(destination.FormatHeaders)
-	//state.HeadersFormatting = types.Int64Value(destination.HeadersFormatting)
+	state.HeadersFormatting = utils.FlattenEnum(adverity.HeadersFormattings, &destination.HeadersFormatting)
+}
+
+// ModifyPlan validates the parameters and the code of headers_formatting against the field metadata of the destination type.
+func (r *destinationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var typeId types.Int64
+	var parameters, sensitiveParameters types.Dynamic
+	var headersFormatting types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("destination_type_id"), &typeId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("headers_formatting"), &headersFormatting)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("sensitive_parameters"), &sensitiveParameters)...)
+	if resp.Diagnostics.HasError() || typeId.IsUnknown() {
+		return
+	}
+
+	// Validate against the field metadata of the planned instance
+	client := r.providerData.planClient(ctx, req.Plan, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+
+	readFields := func() (map[string]adverity.FieldMetadata, error) {
+		return client.ReadDestinationFields(ctx, int(typeId.ValueInt64()))
+	}
+	sources := []utils.ParameterSource{
+		utils.ParametersSource(parameters),
+		{Path: path.Root("sensitive_parameters"), Value: sensitiveParameters, Sensitive: true},
+	}
+	utils.ValidateParametersWithMetadata(sources, readFields, adverity.PayloadFields(adverity.DestinationConfig{}), &resp.Diagnostics)
+	utils.ValidateEnumChoice(path.Root("headers_formatting"), headersFormatting, adverity.HeadersFormattings, readFields, "headers_formatting", "parameters", &resp.Diagnostics)
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *destinationResource) updatePayload(model destinationResourceModel, diags *diag.Diagnostics) *adverity.DestinationConfig {
+	return &adverity.DestinationConfig{
+		Name:    model.Name.ValueStringPointer(),
+		StackID: model.StackID.ValueInt64Pointer(),
+		AuthID:  model.AuthID.ValueInt64Pointer(),
+		//SchemaMapping:          model.SchemaMapping.ValueBoolPointer(),
+		//ColumnNamesToLowerCase: model.ColumnNamesToLowerCase.ValueBoolPointer(),
+		//ForceString:            model.ForceString.ValueBoolPointer(),
+		//FormatHeaders:          model.FormatHeaders.ValueBoolPointer(),
+		HeadersFormatting:   utils.ExpandEnum(adverity.HeadersFormattings, model.HeadersFormatting),
+		Parameters:          utils.ExpandDynamicParameters(model.Parameters, path.Root("parameters"), diags),
+		SensitiveParameters: utils.ExpandDynamicParameters(model.SensitiveParameters, path.Root("sensitive_parameters"), diags),
+	}
 }
 
 // Configure adds the provider configured client to the resource.
@@ -72,18 +131,18 @@ func (r *destinationResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
 }
 
 // Metadata returns the resource type name.
@@ -103,6 +162,8 @@ func (r *destinationResource) Schema(_ context.Context, _ resource.SchemaRequest
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"adopt_existing": adoptExistingAttribute("destination"),
+			"instance":       instanceAttribute(),
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the destination.",
 				Computed:    true,
@@ -110,6 +171,9 @@ func (r *destinationResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"destination_type_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the destination type.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description: "Name of the destination.",
@@ -118,15 +182,37 @@ func (r *destinationResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"stack_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the workspace.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					stackRequiresReplace("destination"),
+				},
 			},
 			"auth_id": schema.Int64Attribute{
 				Description: "Numeric identifier of the authentication.",
 				Optional:    true,
 			},
+			"headers_formatting": schema.StringAttribute{
+				Description: "How to format the column headers. " +
+					"Only `snake_lower` is supported, which replaces spaces by underscores and converts letters to lowercase. " +
+					"Other codes are set with the `headers_formatting` parameter, which must not be set together with this attribute.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(adverity.HeadersFormattings.Names()...),
+				},
+			},
 			"parameters": schema.DynamicAttribute{
 				Description: "Additional destination parameters.",
 				Optional:    true,
 			},
+			"sensitive_parameters": schema.DynamicAttribute{
+				Description: "Additional destination parameters with secret values (e.g. passwords, client secrets or service account keys). " +
+					"They are merged with parameters, but never shown in the plan output or logged. Each key must only be set in one of both attributes.",
+				Optional:  true,
+				Sensitive: true,
+			},
 		},
 	}
 }
@@ -141,6 +227,12 @@ func (r *destinationResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	payload := &adverity.DestinationConfig{
 		Name:    plan.Name.ValueStringPointer(),
@@ -150,7 +242,7 @@ func (r *destinationResource) Create(ctx context.Context, req resource.CreateReq
 		//ColumnNamesToLowerCase: plan.ColumnNamesToLowerCase.ValueBoolPointer(),
 		//ForceString:            plan.ForceString.ValueBoolPointer(),
 		//FormatHeaders:          plan.FormatHeaders.ValueBoolPointer(),
-		//HeadersFormatting:      plan.HeadersFormatting.ValueInt64Pointer(),
+		HeadersFormatting: utils.ExpandEnum(adverity.HeadersFormattings, plan.HeadersFormatting),
 	}
 
 	if !plan.Parameters.IsNull() {
@@ -161,13 +253,17 @@ func (r *destinationResource) Create(ctx context.Context, req resource.CreateReq
 		payload.Parameters = &parameters
 	}
 
-	// Create new destination
-	destination, err := r.client.CreateDestination(int(plan.DestinationTypeId.ValueInt64()), payload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating destination",
-			"Could not create destination, unexpected error: "+err.Error(),
-		)
+	if !plan.SensitiveParameters.IsNull() {
+		sensitiveParameters := utils.ExpandParameters(plan.SensitiveParameters.UnderlyingValue(), path.Root("sensitive_parameters"), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		payload.SensitiveParameters = &sensitiveParameters
+	}
+
+	// Create new destination, or adopt the existing one
+	destination := createDestination(ctx, client, plan.DestinationTypeId, plan.StackID, plan.Name, plan.AdoptExisting, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -193,13 +289,15 @@ func (r *destinationResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get refreshed destination value from Adverity
-	destination, err := r.client.ReadDestination(int(state.DestinationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading Adverity destination",
-			"Could not read destination, unexpected error: "+err.Error(),
-		)
+	destination := r.providerData.readDestinationState(ctx, client, state.StackID, state.DestinationTypeId, state.ID, destinationFields, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -215,41 +313,31 @@ func (r *destinationResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *destinationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan destinationResourceModel
+	// Retrieve values from plan and prior state
+	var plan, state destinationResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Generate API request body from plan
-	payload := &adverity.DestinationConfig{
-		Name:    plan.Name.ValueStringPointer(),
-		StackID: plan.StackID.ValueInt64Pointer(),
-		AuthID:  plan.AuthID.ValueInt64Pointer(),
-		//SchemaMapping:          plan.SchemaMapping.ValueBoolPointer(),
-		//ColumnNamesToLowerCase: plan.ColumnNamesToLowerCase.ValueBoolPointer(),
-		//ForceString:            plan.ForceString.ValueBoolPointer(),
-		//FormatHeaders:          plan.FormatHeaders.ValueBoolPointer(),
-		//HeadersFormatting:      plan.HeadersFormatting.ValueInt64Pointer(),
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !plan.Parameters.IsNull() {
-		parameters := utils.ExpandParameters(plan.Parameters.UnderlyingValue(), path.Root("parameters"), &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		payload.Parameters = &parameters
+	// Generate API request body with the changes from the prior state to the plan
+	payload := utils.PatchPayload(r.updatePayload(state, &resp.Diagnostics), r.updatePayload(plan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Update existing destination
-	destination, err := r.client.UpdateDestination(int(plan.DestinationTypeId.ValueInt64()), int(plan.ID.ValueInt64()), payload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating Adverity destination",
-			"Could not update destination, unexpected error: "+err.Error(),
-		)
+	destination := updateDestination(ctx, client, plan.DestinationTypeId, plan.ID, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -274,8 +362,14 @@ func (r *destinationResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing destination
-	_, err := r.client.DeleteDestination(int(state.DestinationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
+	_, err := client.DeleteDestination(ctx, int(state.DestinationTypeId.ValueInt64()), int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Adverity destination",
@@ -286,8 +380,11 @@ func (r *destinationResource) Delete(ctx context.Context, req resource.DeleteReq
 }
 
 func (r *destinationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
 	// Split the composite import ID (<destination_type_id>:<id>) into its parts
-	parts := utils.SplitImportParts(req.ID, 2, "<destination_type_id>:<id>", &resp.Diagnostics)
+	parts := utils.SplitImportParts(importID, 2, "<destination_type_id>:<id>", &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
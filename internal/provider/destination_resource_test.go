@@ -0,0 +1,94 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccDestinationResource(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(server, "adverity_destination", "target"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("BigQuery") +
+					testAccDestinationResourceConfig("Warehouse", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_destination.test", tfjsonpath.New("name"), knownvalue.StringExact("Warehouse")),
+					statecheck.ExpectKnownValue("adverity_destination.test", tfjsonpath.New("headers_formatting"), knownvalue.Null()),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:            "adverity_destination.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateId("adverity_destination.test", "destination_type_id", "id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "parameters"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("BigQuery") +
+					testAccDestinationResourceConfig("Warehouse EMEA", "snake_lower"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_destination.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_destination.test", tfjsonpath.New("name"), knownvalue.StringExact("Warehouse EMEA")),
+					statecheck.ExpectKnownValue("adverity_destination.test", tfjsonpath.New("headers_formatting"), knownvalue.StringExact("snake_lower")),
+				},
+			},
+			// Replace testing: Adverity cannot move destinations to another workspace
+			{
+				Config: providerConfig + testAccWorkspaceConfig + testAccAuthorizationResourceConfig("BigQuery") +
+					strings.Replace(testAccDestinationResourceConfig("Warehouse EMEA", "snake_lower"), "adverity_workspace.test.id", "1", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_destination.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_destination.test", tfjsonpath.New("stack_id"), knownvalue.Int64Exact(1)),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// testAccDestinationResourceConfig returns the configuration of a destination, without headers_formatting if it is empty.
+func testAccDestinationResourceConfig(name, headersFormatting string) string {
+	if headersFormatting != "" {
+		headersFormatting = fmt.Sprintf("headers_formatting  = %q", headersFormatting)
+	}
+	return fmt.Sprintf(`
+resource "adverity_destination" "test" {
+  destination_type_id = %[3]d
+  name                = %[1]q
+  stack_id            = adverity_workspace.test.id
+  auth_id             = adverity_authorization.test.id
+  %[2]s
+
+  parameters = {
+    project = "analytics"
+    dataset = "marketing"
+  }
+}
+`, name, headersFormatting, testAccDestinationTypeID)
+}
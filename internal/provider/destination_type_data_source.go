@@ -6,10 +6,8 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
-
-	"terraform-provider-adverity/internal/adverity"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -28,13 +26,14 @@ func NewDestinationTypeDataSource() datasource.DataSource {
 
 // destinationTypeDataSource is the data source implementation.
 type destinationTypeDataSource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // destinationTypeDataSourceModel maps the data source schema data.
 type destinationTypeDataSourceModel struct {
 	SearchTerm types.String `tfsdk:"search_term"`
 	Results    types.Map    `tfsdk:"results"`
+	Instance   types.String `tfsdk:"instance"`
 }
 
 // Configure adds the provider configured client to the data source.
@@ -45,17 +44,17 @@ func (d *destinationTypeDataSource) Configure(_ context.Context, req datasource.
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.providerData = data
 }
 
 // Metadata returns the data source type name.
@@ -68,6 +67,7 @@ func (d *destinationTypeDataSource) Schema(_ context.Context, _ datasource.Schem
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of destination types.",
 		Attributes: map[string]schema.Attribute{
+			"instance": instanceDataSourceAttribute(),
 			"search_term": schema.StringAttribute{
 				Description: "Search term to filter on.",
 				Required:    true,
@@ -91,7 +91,13 @@ func (d *destinationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 		return
 	}
 
-	destinationTypes, err := d.client.QueryDestinationTypes(data.SearchTerm.ValueString())
+	// Select the client of the instance
+	client := d.providerData.client(data.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	destinationTypes, err := client.QueryDestinationTypes(ctx, data.SearchTerm.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error querying Adverity destination types",
@@ -5,21 +5,33 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"terraform-provider-adverity/internal/adverity"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure AdverityProvider satisfies various provider interfaces.
 var _ provider.Provider = &AdverityProvider{}
+var _ provider.ProviderWithFunctions = &AdverityProvider{}
+var _ provider.ProviderWithEphemeralResources = &AdverityProvider{}
 
 // AdverityProvider defines the provider implementation.
 type AdverityProvider struct {
@@ -31,6 +43,27 @@ type AdverityProvider struct {
 
 // AdverityProviderModel describes the provider data model.
 type AdverityProviderModel struct {
+	InstanceUrl        types.String            `tfsdk:"instance_url"`
+	AuthToken          types.String            `tfsdk:"auth_token"`
+	AuthTokenFile      types.String            `tfsdk:"auth_token_file"`
+	AuthTokenCommand   types.List              `tfsdk:"auth_token_command"`
+	Username           types.String            `tfsdk:"username"`
+	Password           types.String            `tfsdk:"password"`
+	ProxyUrl           types.String            `tfsdk:"proxy_url"`
+	CACertPEM          types.String            `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String            `tfsdk:"ca_cert_file"`
+	ClientCert         types.String            `tfsdk:"client_cert"`
+	ClientKey          types.String            `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool              `tfsdk:"insecure_skip_verify"`
+	AllowInsecureHttp  types.Bool              `tfsdk:"allow_insecure_http"`
+	ExtraHeaders       types.Map               `tfsdk:"extra_headers"`
+	RefreshStrategy    types.String            `tfsdk:"refresh_strategy"`
+	Instances          []AdverityInstanceModel `tfsdk:"instances"`
+}
+
+// AdverityInstanceModel describes a named instance of the provider.
+type AdverityInstanceModel struct {
+	Name        types.String `tfsdk:"name"`
 	InstanceUrl types.String `tfsdk:"instance_url"`
 	AuthToken   types.String `tfsdk:"auth_token"`
 }
@@ -42,7 +75,8 @@ func (p *AdverityProvider) Metadata(ctx context.Context, req provider.MetadataRe
 
 func (p *AdverityProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Interact with Adverity.",
+		Description: "Interact with Adverity. The provider authenticates with the first of auth_token, auth_token_file, auth_token_command or username and password which is set. " +
+			"Authentication configured in the provider block takes precedence over the environment variables, which are only used if none of these attributes is set.",
 		Attributes: map[string]schema.Attribute{
 			"instance_url": schema.StringAttribute{
 				Description: "Instance URL pointing to Adverity API (e.g. <your-instance>.datatap.adverity.com). May also be provided via ADVERITY_INSTANCE_URL environment variable.",
@@ -52,6 +86,143 @@ func (p *AdverityProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Description: "Authentication token for Adverity API. May also be provided via ADVERITY_AUTH_TOKEN environment variable.",
 				Optional:    true,
 				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("auth_token_file"),
+						path.MatchRoot("auth_token_command"),
+						path.MatchRoot("username"),
+						path.MatchRoot("password"),
+					),
+				},
+			},
+			"auth_token_file": schema.StringAttribute{
+				Description: "Path to a file containing the authentication token for Adverity API, surrounding whitespace is ignored. " +
+					"May also be provided via ADVERITY_AUTH_TOKEN_FILE environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("auth_token_command"),
+						path.MatchRoot("username"),
+						path.MatchRoot("password"),
+					),
+				},
+			},
+			"auth_token_command": schema.ListAttribute{
+				Description: "Command and arguments of a credential helper (e.g. the Vault or 1Password CLI) which prints the authentication token for Adverity API. " +
+					"The command is run again to refresh the token when Adverity rejects it during a run. " +
+					"May also be provided via ADVERITY_AUTH_TOKEN_COMMAND environment variable, split at whitespace.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(
+						path.MatchRoot("username"),
+						path.MatchRoot("password"),
+					),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Username to obtain an authentication token with. May also be provided via ADVERITY_USERNAME environment variable.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("password")),
+				},
+			},
+			"password": schema.StringAttribute{
+				Description: "Password to obtain an authentication token with. May also be provided via ADVERITY_PASSWORD environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("username")),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of an HTTP(S) proxy to send the requests through, e.g. http://proxy.example.com:3128. " +
+					"Defaults to the proxy from the HTTPS_PROXY and NO_PROXY environment variables.",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded CA certificates to trust in addition to the system certificates, e.g. the CA of a TLS inspecting proxy.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_file")),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a file with PEM encoded CA certificates to trust in addition to the system certificates.",
+				Optional:    true,
+			},
+			"client_cert": schema.StringAttribute{
+				Description: "PEM encoded client certificate to authenticate the TLS connections with (mTLS).",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				Description: "PEM encoded private key of the client certificate.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Whether to skip the verification of the server certificate. " +
+					"This makes the connections vulnerable to man-in-the-middle attacks, only use it for testing and prefer ca_cert_pem or ca_cert_file instead.",
+				Optional: true,
+			},
+			"allow_insecure_http": schema.BoolAttribute{
+				Description: "Whether to allow instance URLs with plain HTTP, e.g. for local mock servers or internal reverse proxies during development. " +
+					"The auth tokens and credentials are sent unencrypted, so HTTPS is required by default. " +
+					"May also be provided via ADVERITY_ALLOW_INSECURE_HTTP environment variable.",
+				Optional: true,
+			},
+			"extra_headers": schema.MapAttribute{
+				Description: "Additional headers to send with every request, e.g. tenant-specific headers. " +
+					"The Authorization, Content-Type, User-Agent and X-Request-ID headers are set by the provider and cannot be replaced.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.NoneOfCaseInsensitive("Authorization", "Content-Type", "User-Agent", "X-Request-ID")),
+				},
+			},
+			"refresh_strategy": schema.StringAttribute{
+				Description: "How datastreams, destinations and destination mappings are refreshed: \"individual\" (default) reads each object with its own request. " +
+					"\"batch\" lists all datastreams of a workspace, all destinations of a type in a workspace and all mappings of a destination with the first read and reads the other objects from these lists, " +
+					"which reduces the requests and the time to plan large workspaces. Each listed object is read from the lists once per run, later reads of the object request it again. " +
+					"Destinations whose list entry lacks a parameter in the state are read individually.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(refreshStrategyIndividual, refreshStrategyBatch),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"instances": schema.ListNestedBlock{
+				Description: "Named Adverity instances, e.g. one per region, which resources select with their instance attribute. " +
+					"Without an instance attribute, resources use the instance configured by instance_url, which is optional if instances are configured.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the instance, referenced by the instance attribute of resources.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"instance_url": schema.StringAttribute{
+							Description: "Instance URL pointing to Adverity API (e.g. <your-instance>.datatap.adverity.com).",
+							Required:    true,
+						},
+						"auth_token": schema.StringAttribute{
+							Description: "Authentication token for the instance.",
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -92,6 +263,60 @@ func (p *AdverityProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
+	if config.AuthTokenFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_token_file"),
+			"Unknown Adverity auth token file",
+			"The provider cannot create the Adverity API client as there is an unknown configuration value for the Adverity auth token file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the ADVERITY_AUTH_TOKEN_FILE environment variable.",
+		)
+	}
+
+	if config.AuthTokenCommand.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_token_command"),
+			"Unknown Adverity auth token command",
+			"The provider cannot create the Adverity API client as there is an unknown configuration value for the Adverity auth token command. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the ADVERITY_AUTH_TOKEN_COMMAND environment variable.",
+		)
+	}
+
+	if config.AllowInsecureHttp.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("allow_insecure_http"),
+			"Unknown Adverity allow insecure HTTP setting",
+			"The provider cannot create the Adverity API client as there is an unknown configuration value for allowing plain HTTP instance URLs. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the ADVERITY_ALLOW_INSECURE_HTTP environment variable.",
+		)
+	}
+
+	if config.Username.IsUnknown() || config.Password.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Adverity credentials",
+			"The provider cannot create the Adverity API client as there is an unknown configuration value for the Adverity username or password. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the ADVERITY_USERNAME and ADVERITY_PASSWORD environment variables.",
+		)
+	}
+
+	if config.ProxyUrl.IsUnknown() || config.CACertPEM.IsUnknown() || config.CACertFile.IsUnknown() || config.ClientCert.IsUnknown() || config.ClientKey.IsUnknown() || config.InsecureSkipVerify.IsUnknown() || config.ExtraHeaders.IsUnknown() || config.RefreshStrategy.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Adverity client settings",
+			"The provider cannot create the Adverity API client as there is an unknown configuration value for the proxy, CA certificates, client certificate, certificate verification, extra headers or refresh strategy. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	for i, instance := range config.Instances {
+		if instance.Name.IsUnknown() || instance.InstanceUrl.IsUnknown() || instance.AuthToken.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("instances").AtListIndex(i),
+				"Unknown Adverity instance",
+				"The provider cannot create the Adverity API client of the instance as there is an unknown configuration value for its name, instance URL or auth token. "+
+					"Either target apply the source of the value first or set the value statically in the configuration.",
+			)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -101,102 +326,255 @@ func (p *AdverityProvider) Configure(ctx context.Context, req provider.Configure
 
 	instanceUrl := os.Getenv("ADVERITY_INSTANCE_URL")
 	authToken := os.Getenv("ADVERITY_AUTH_TOKEN")
+	authTokenFile := os.Getenv("ADVERITY_AUTH_TOKEN_FILE")
+	authTokenCommand := strings.Fields(os.Getenv("ADVERITY_AUTH_TOKEN_COMMAND"))
+	username := os.Getenv("ADVERITY_USERNAME")
+	password := os.Getenv("ADVERITY_PASSWORD")
+
+	allowInsecureHttp := false
+	if v := os.Getenv("ADVERITY_ALLOW_INSECURE_HTTP"); v != "" {
+		var err error
+		if allowInsecureHttp, err = strconv.ParseBool(v); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid ADVERITY_ALLOW_INSECURE_HTTP environment variable",
+				"The value of the ADVERITY_ALLOW_INSECURE_HTTP environment variable must be true or false: "+err.Error(),
+			)
+			return
+		}
+	}
 
 	if !config.InstanceUrl.IsNull() {
 		instanceUrl = config.InstanceUrl.ValueString()
 	}
+	if !config.AllowInsecureHttp.IsNull() {
+		allowInsecureHttp = config.AllowInsecureHttp.ValueBool()
+	}
 
-	if !config.AuthToken.IsNull() {
+	// Authentication configured in the provider block replaces the authentication from the environment
+	if !config.AuthToken.IsNull() || !config.AuthTokenFile.IsNull() || !config.AuthTokenCommand.IsNull() || !config.Username.IsNull() || !config.Password.IsNull() {
 		authToken = config.AuthToken.ValueString()
+		authTokenFile = config.AuthTokenFile.ValueString()
+		authTokenCommand = nil
+		if !config.AuthTokenCommand.IsNull() {
+			resp.Diagnostics.Append(config.AuthTokenCommand.ElementsAs(ctx, &authTokenCommand, false)...)
+		}
+		username = config.Username.ValueString()
+		password = config.Password.ValueString()
 	}
 
-	// If any of the expected configurations are missing, return
-	// errors with provider-specific guidance.
-
-	if instanceUrl == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("instance_url"),
-			"Missing Adverity instance URL",
-			"The provider cannot create the Adverity API client as there is a missing or empty value for the Adverity instance URL. "+
-				"Set the host value in the configuration or use the ADVERITY_INSTANCE_URL environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+	// The headers and transport settings apply to the clients of all instances
+	sharedOptions := []adverity.ClientOption{
+		adverity.WithUserAgent(fmt.Sprintf("terraform-provider-adverity/%s terraform/%s", p.version, req.TerraformVersion)),
+	}
+	if !config.ExtraHeaders.IsNull() {
+		extraHeaders := make(map[string]string, len(config.ExtraHeaders.Elements()))
+		resp.Diagnostics.Append(config.ExtraHeaders.ElementsAs(ctx, &extraHeaders, false)...)
+		sharedOptions = append(sharedOptions, adverity.WithHeaders(extraHeaders))
+	}
+	if !config.ProxyUrl.IsNull() {
+		sharedOptions = append(sharedOptions, adverity.WithProxy(config.ProxyUrl.ValueString()))
+	}
+	if !config.CACertPEM.IsNull() {
+		sharedOptions = append(sharedOptions, adverity.WithCACertPEM([]byte(config.CACertPEM.ValueString())))
+	}
+	if !config.CACertFile.IsNull() {
+		sharedOptions = append(sharedOptions, adverity.WithCACertFile(config.CACertFile.ValueString()))
+	}
+	if !config.ClientCert.IsNull() {
+		sharedOptions = append(sharedOptions, adverity.WithClientCertificate([]byte(config.ClientCert.ValueString()), []byte(config.ClientKey.ValueString())))
+	}
+	if config.InsecureSkipVerify.ValueBool() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS certificate verification is disabled",
+			"The provider does not verify the certificates of the Adverity instances, so anyone on the network path can intercept the connections, including the auth tokens and credentials. "+
+				"Only use insecure_skip_verify for testing, and trust the CA of a TLS inspecting proxy with ca_cert_pem or ca_cert_file instead.",
 		)
-	} else {
-		parsedUrl, err := url.Parse(instanceUrl)
-		switch {
-		case err != nil:
+		sharedOptions = append(sharedOptions, adverity.WithInsecureSkipVerify())
+	}
+
+	data := &providerData{
+		instances:         make(map[string]*adverity.Client, len(config.Instances)),
+		sharedOptions:     sharedOptions,
+		allowInsecureHttp: allowInsecureHttp,
+	}
+	if config.RefreshStrategy.ValueString() == refreshStrategyBatch {
+		data.batch = &batchRefresh{}
+	}
+
+	// The instance configured by instance_url is optional if named instances are configured
+	if instanceUrl != "" || len(config.Instances) == 0 {
+		// If any of the expected configurations are missing, return
+		// errors with provider-specific guidance.
+
+		if instanceUrl == "" {
 			resp.Diagnostics.AddAttributeError(
 				path.Root("instance_url"),
-				"Invalid Adverity instance URL",
-				"The instance URL could not be parsed: "+err.Error(),
+				"Missing Adverity instance URL",
+				"The provider cannot create the Adverity API client as there is a missing or empty value for the Adverity instance URL. "+
+					"Set the host value in the configuration, use the ADVERITY_INSTANCE_URL environment variable or configure instances. "+
+					"If either is already set, ensure the value is not empty.",
 			)
-		case parsedUrl.Scheme != "https":
-			resp.Diagnostics.AddAttributeError(
-				path.Root("instance_url"),
-				"Insecure Adverity instance URL",
-				"The instance URL must use HTTPS."+
-					"Got scheme: "+parsedUrl.Scheme+". "+
-					"Set the value to https://<your-instance>.datatap.adverity.com.",
+		} else {
+			validateInstanceUrl(path.Root("instance_url"), instanceUrl, allowInsecureHttp, &resp.Diagnostics)
+		}
+
+		var clientOptions []adverity.ClientOption
+		switch {
+		case authToken != "":
+			clientOptions = append(clientOptions, adverity.WithToken(authToken))
+		case authTokenFile != "":
+			clientOptions = append(clientOptions, adverity.WithTokenFile(authTokenFile))
+		case len(authTokenCommand) > 0:
+			clientOptions = append(clientOptions, adverity.WithTokenCommand(authTokenCommand))
+		case username != "" && password != "":
+			clientOptions = append(clientOptions, adverity.WithCredentials(username, password))
+		case username != "" || password != "":
+			resp.Diagnostics.AddError(
+				"Incomplete Adverity credentials",
+				"The provider cannot obtain an Adverity auth token as either the username or the password is missing or empty. "+
+					"Set both in the configuration or use the ADVERITY_USERNAME and ADVERITY_PASSWORD environment variables.",
 			)
-		case parsedUrl.Host == "":
+		default:
 			resp.Diagnostics.AddAttributeError(
-				path.Root("instance_url"),
-				"Invalid Adverity instance URL",
-				"The instance URL must include a host, e.g. https://<your-instance>.datatap.adverity.com.",
+				path.Root("auth_token"),
+				"Missing Adverity auth token",
+				"The provider cannot create the Adverity API client as there is a missing or empty value for the Adverity auth token. "+
+					"Set the auth_token value in the configuration or use the ADVERITY_AUTH_TOKEN environment variable, "+
+					"read it from a file with auth_token_file (or ADVERITY_AUTH_TOKEN_FILE), run a credential helper with auth_token_command (or ADVERITY_AUTH_TOKEN_COMMAND), "+
+					"or set username and password (or the ADVERITY_USERNAME and ADVERITY_PASSWORD environment variables) to obtain a token. "+
+					"If either is already set, ensure the value is not empty.",
 			)
 		}
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		ctx = tflog.SetField(ctx, "adverity_instance_url", instanceUrl)
+		ctx = tflog.SetField(ctx, "adverity_auth_token", authToken)
+		ctx = tflog.SetField(ctx, "adverity_auth_token_file", authTokenFile)
+		ctx = tflog.SetField(ctx, "adverity_username", username)
+		ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "adverity_auth_token")
+
+		tflog.Debug(ctx, "Creating Adverity API client")
+
+		// Create a new Adverity client using the configuration values
+		client, err := adverity.NewClient(ctx, instanceUrl, append(clientOptions, sharedOptions...)...)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to create Adverity API client",
+				"An unexpected error occurred when creating the Adverity API client. "+
+					"If the error is not clear, please contact the provider developers.\n\n"+
+					"Adverity client error: "+err.Error(),
+			)
+			return
+		}
+		data.defaultClient = client
 	}
 
-	if authToken == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("auth_token"),
-			"Missing Adverity auth token",
-			"The provider cannot create the Adverity API client as there is a missing or empty value for the Adverity auth token. "+
-				"Set the username value in the configuration or use the ADVERITY_AUTH_TOKEN environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
+	// Create a client per named instance
+	for i, instance := range config.Instances {
+		instancePath := path.Root("instances").AtListIndex(i)
+		name := instance.Name.ValueString()
+		if _, ok := data.instances[name]; ok {
+			resp.Diagnostics.AddAttributeError(
+				instancePath.AtName("name"),
+				"Duplicate Adverity instance",
+				fmt.Sprintf("The instance %q is configured more than once.", name),
+			)
+			continue
+		}
+
+		var urlDiags diag.Diagnostics
+		validateInstanceUrl(instancePath.AtName("instance_url"), instance.InstanceUrl.ValueString(), allowInsecureHttp, &urlDiags)
+		resp.Diagnostics.Append(urlDiags...)
+		if urlDiags.HasError() {
+			continue
+		}
+
+		tflog.Debug(ctx, "Creating Adverity API client", map[string]any{"adverity_instance": name, "adverity_instance_url": instance.InstanceUrl.ValueString()})
+		client, err := adverity.NewClient(ctx, instance.InstanceUrl.ValueString(), append([]adverity.ClientOption{adverity.WithToken(instance.AuthToken.ValueString())}, sharedOptions...)...)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				instancePath,
+				"Unable to create Adverity API client",
+				fmt.Sprintf("An unexpected error occurred when creating the Adverity API client of the instance %q.\n\nAdverity client error: %s", name, err.Error()),
+			)
+			continue
+		}
+		data.instances[name] = client
 	}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	ctx = tflog.SetField(ctx, "adverity_instance_url", instanceUrl)
-	ctx = tflog.SetField(ctx, "adverity_auth_token", authToken)
-	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "adverity_auth_token")
+	// Make the Adverity clients available during DataSource and Resource type Configure methods.
+	resp.DataSourceData = data
+	resp.ResourceData = data
+	resp.EphemeralResourceData = data
 
-	tflog.Debug(ctx, "Creating Adverity API client")
+	tflog.Info(ctx, "Configured Adverity API client", map[string]any{"success": true})
+}
 
-	// Create a new Adverity client using the configuration values
-	client, err := adverity.NewClient(&instanceUrl, &authToken)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to create Adverity API client",
-			"An unexpected error occurred when creating the Adverity API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Adverity client error: "+err.Error(),
+// validateInstanceUrl validates that an instance URL uses HTTPS and includes a host.
+// Plain HTTP is accepted with a warning if allowInsecureHttp is set.
+func validateInstanceUrl(attributePath path.Path, instanceUrl string, allowInsecureHttp bool, diags *diag.Diagnostics) {
+	parsedUrl, err := url.Parse(instanceUrl)
+	switch {
+	case err != nil:
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid Adverity instance URL",
+			"The instance URL could not be parsed: "+err.Error(),
+		)
+	case parsedUrl.Scheme == "http" && allowInsecureHttp && parsedUrl.Host != "":
+		diags.AddAttributeWarning(
+			attributePath,
+			"Insecure Adverity instance URL",
+			"The instance URL uses plain HTTP, so the auth tokens, credentials and data are sent unencrypted. "+
+				"Only use allow_insecure_http for local development, e.g. with mock servers.",
+		)
+	case parsedUrl.Scheme != "https" && !(parsedUrl.Scheme == "http" && allowInsecureHttp):
+		diags.AddAttributeError(
+			attributePath,
+			"Insecure Adverity instance URL",
+			"The instance URL must use HTTPS. "+
+				"Got scheme: "+parsedUrl.Scheme+". "+
+				"Set the value to https://<your-instance>.datatap.adverity.com, or set allow_insecure_http to use plain HTTP during development.",
+		)
+	case parsedUrl.Host == "":
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid Adverity instance URL",
+			"The instance URL must include a host, e.g. https://<your-instance>.datatap.adverity.com.",
 		)
-		return
 	}
-
-	// Make the Adverity client available during DataSource and Resource type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
-
-	tflog.Info(ctx, "Configured Adverity API client", map[string]any{"success": true})
 }
 
 // Resources defines the resources implemented in the provider.
 func (p *AdverityProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
+	resources := []func() resource.Resource{
 		NewExampleResource,
 		NewWorkspaceResource,
 		NewConnectionResource,
 		NewAuthorizationResource,
 		NewDatastreamResource,
+		NewDatastreamScheduleResource,
 		NewDestinationResource,
 		NewDestinationMappingResource,
 	}
+
+	// Typed connector resources generated from the fixtures in internal/connectorgen
+	return append(resources, typedConnectorResources...)
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *AdverityProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewApiTokenEphemeralResource,
+	}
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -210,6 +588,14 @@ func (p *AdverityProvider) DataSources(ctx context.Context) []func() datasource.
 	}
 }
 
+// Functions defines the functions implemented in the provider.
+func (p *AdverityProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewScheduleNextRunsFunction,
+		NewScheduleFetchRangeFunction,
+	}
+}
+
 // New is a helper function to simplify provider server and testing implementation.
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
@@ -0,0 +1,115 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// providerData is passed to the resources, data sources and ephemeral resources. It holds the
+// client of the instance configured by instance_url and a client per named instance.
+type providerData struct {
+	// defaultClient is nil if only named instances are configured.
+	defaultClient *adverity.Client
+	instances     map[string]*adverity.Client
+	// sharedOptions are the header, proxy and TLS settings of the provider, e.g. for clients of other instances.
+	sharedOptions []adverity.ClientOption
+	// allowInsecureHttp is the allow_insecure_http setting, e.g. for instance URLs of ephemeral resources.
+	allowInsecureHttp bool
+	// batch serves the reads from lists of the objects if refresh_strategy is batch, nil otherwise.
+	batch *batchRefresh
+}
+
+// client returns the client of a named instance, or the default client if no instance is set.
+func (p *providerData) client(instance types.String, diags *diag.Diagnostics) *adverity.Client {
+	if instance.IsNull() || instance.IsUnknown() {
+		if p.defaultClient == nil {
+			diags.AddAttributeError(
+				path.Root("instance"),
+				"Missing Adverity instance",
+				"The provider has no instance_url configured, so the instance must be set to one of: "+p.instanceNames()+".",
+			)
+		}
+		return p.defaultClient
+	}
+
+	client, ok := p.instances[instance.ValueString()]
+	if !ok {
+		diags.AddAttributeError(
+			path.Root("instance"),
+			"Unknown Adverity instance",
+			fmt.Sprintf("The instance %q is not configured in the provider, expected one of: %s.", instance.ValueString(), p.instanceNames()),
+		)
+		return nil
+	}
+
+	return client
+}
+
+// planClient returns the client of the instance in a plan, or nil if the instance is not known yet or not configured.
+func (p *providerData) planClient(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) *adverity.Client {
+	var instance types.String
+	diags.Append(plan.GetAttribute(ctx, path.Root("instance"), &instance)...)
+	if diags.HasError() || instance.IsUnknown() {
+		return nil
+	}
+	return p.client(instance, diags)
+}
+
+func (p *providerData) instanceNames() string {
+	if len(p.instances) == 0 {
+		return "(no instances configured)"
+	}
+	return strings.Join(slices.Sorted(maps.Keys(p.instances)), ", ")
+}
+
+// instanceDescription documents the instance attribute.
+const instanceDescription = "Name of the provider instance to manage the object in, as configured in an instances block of the provider. " +
+	"Defaults to the instance configured by instance_url."
+
+// instanceAttribute returns the instance attribute of a resource. Changing the instance replaces the resource.
+func instanceAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: instanceDescription,
+		Optional:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// instanceDataSourceAttribute returns the instance attribute of a data source.
+func instanceDataSourceAttribute() datasourceschema.StringAttribute {
+	return datasourceschema.StringAttribute{
+		Description: instanceDescription,
+		Optional:    true,
+	}
+}
+
+// importInstance splits an optional instance prefix (<instance>/<id>) from the import ID, sets the
+// instance in the state and returns the remaining ID.
+func importInstance(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) string {
+	instance, id := utils.SplitImportInstance(req.ID)
+	if instance != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance"), instance)...)
+	}
+	return id
+}
@@ -4,10 +4,19 @@
 package provider
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
+	"terraform-provider-adverity/internal/adverity/fakeserver"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 // testAccProtoV6ProviderFactories is used to instantiate a provider during acceptance testing.
@@ -35,3 +44,118 @@ provider "adverity" {
 }
 `
 )
+
+// Types offered by the fake Adverity API of the acceptance tests.
+const (
+	testAccAuthorizationTypeID = 10
+	testAccDatastreamTypeID    = 20
+	testAccDestinationTypeID   = 30
+)
+
+// testAccFakeServer starts an in-memory Adverity API for the test and returns it together with
+// a provider configuration pointing at it, so the resources can be tested offline.
+func testAccFakeServer(t *testing.T) (*fakeserver.Server, string) {
+	t.Helper()
+
+	server := fakeserver.New()
+	t.Cleanup(server.Close)
+
+	server.AddConnectionType(fakeserver.Type{ID: testAccAuthorizationTypeID, Name: "Google Ads", Slug: "google-ads"})
+	server.AddDatastreamType(fakeserver.Type{ID: testAccDatastreamTypeID, Name: "Google Ads Insights", Slug: "google-ads-insights"})
+	server.AddTargetType(fakeserver.Type{ID: testAccDestinationTypeID, Name: "Google BigQuery", Slug: "bigquery"})
+
+	config := fmt.Sprintf(`
+provider "adverity" {
+  instance_url = %q
+  auth_token   = %q
+  ca_cert_pem  = %q
+}
+`, server.URL, fakeserver.DefaultToken, server.CertificatePEM())
+
+	return server, config
+}
+
+// testAccWorkspaceConfig is a workspace below the root workspace of the fake Adverity API,
+// for the objects of the acceptance tests.
+const testAccWorkspaceConfig = `
+resource "adverity_workspace" "test" {
+  name      = "Acceptance Tests"
+  parent_id = 1
+}
+`
+
+// testAccImportStateId joins the attributes of a resource in the state into a composite import ID.
+func testAccImportStateId(resourceName string, attributes ...string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		parts := make([]string, 0, len(attributes))
+		for _, attribute := range attributes {
+			parts = append(parts, rs.Primary.Attributes[attribute])
+		}
+
+		return strings.Join(parts, ":"), nil
+	}
+}
+
+// testAccCheckDestroyed checks that the objects of all resources of a type were deleted from the fake Adverity API.
+func testAccCheckDestroyed(server *fakeserver.Server, resourceType, kind string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			id, err := strconv.ParseInt(rs.Primary.Attributes["id"], 10, 64)
+			if err != nil {
+				return err
+			}
+			if server.Exists(kind, id) {
+				return fmt.Errorf("%s %d still exists", kind, id)
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccResourceID returns the numeric ID of a resource in the state.
+func testAccResourceID(s *terraform.State, resourceName string) (int64, error) {
+	rs, ok := s.RootModule().Resources[resourceName]
+	if !ok {
+		return 0, fmt.Errorf("resource %s not found in state", resourceName)
+	}
+	return strconv.ParseInt(rs.Primary.Attributes["id"], 10, 64)
+}
+
+func TestValidateInstanceUrl(t *testing.T) {
+	tests := map[string]struct {
+		url               string
+		allowInsecureHttp bool
+		wantError         bool
+		wantWarning       bool
+	}{
+		"https":                      {url: "https://example.datatap.adverity.com"},
+		"http":                       {url: "http://localhost:8080", wantError: true},
+		"http allowed":               {url: "http://localhost:8080", allowInsecureHttp: true, wantWarning: true},
+		"other scheme allowed":       {url: "ftp://localhost", allowInsecureHttp: true, wantError: true},
+		"missing host":               {url: "https://", wantError: true},
+		"missing host, http allowed": {url: "http://", allowInsecureHttp: true, wantError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateInstanceUrl(path.Root("instance_url"), test.url, test.allowInsecureHttp, &diags)
+			if diags.HasError() != test.wantError {
+				t.Errorf("errors = %v, want error %t", diags.Errors(), test.wantError)
+			}
+			if gotWarning := diags.WarningsCount() > 0; gotWarning != test.wantWarning {
+				t.Errorf("warnings = %v, want warning %t", diags.Warnings(), test.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"terraform-provider-adverity/internal/schedule"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &scheduleFetchRangeFunction{}
+
+var fetchRangeAttributeTypes = map[string]attr.Type{
+	"start": types.StringType,
+	"end":   types.StringType,
+}
+
+// NewScheduleFetchRangeFunction is a helper function to simplify the provider implementation.
+func NewScheduleFetchRangeFunction() function.Function {
+	return &scheduleFetchRangeFunction{}
+}
+
+// scheduleFetchRangeFunction is the function implementation.
+type scheduleFetchRangeFunction struct{}
+
+func (f *scheduleFetchRangeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "schedule_fetch_range"
+}
+
+func (f *scheduleFetchRangeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the date range a run of a datastream schedule would fetch.",
+		Description: "Returns the inclusive date range (`start` and `end` in YYYY-MM-DD format) a run of a datastream schedule at the moment `at` would fetch, without calling the Adverity API. " +
+			"The day of the run starts at `delta_start_of_day` and is moved back by `offset_days`. " +
+			"The range then covers `delta_interval` units of `delta_type` (assumed to be 1 for days, 2 for ISO weeks, 3 for months and 4 for years, which the Adverity API does not confirm), ending `delta_interval_start` units before the unit of that day, where 0 fetches the current unit up to that day. " +
+			"Only custom time ranges (`time_range_preset = 0` or `time_range = \"custom\"`) are supported, which can additionally pin the range with `fixed_start` and `fixed_end`, since the delta fields implied by the other presets are not known. " +
+			"Fails if the schedule does not run at `at` yet because of `not_before_date` and `not_before_time`.",
+		Parameters: []function.Parameter{
+			scheduleParameter(),
+			function.StringParameter{
+				Name:        "at",
+				Description: "Moment of the run in RFC 3339 format (e.g. `timestamp()` or `2025-01-01T03:30:00Z`).",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: fetchRangeAttributeTypes,
+		},
+	}
+}
+
+func (f *scheduleFetchRangeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var scheduleValue types.Dynamic
+	var at string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &scheduleValue, &at))
+	if resp.Error != nil {
+		return
+	}
+
+	s, err := expandScheduleArgument(scheduleValue)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	atTime, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("at must be a timestamp in RFC 3339 format, got %q", at))
+		return
+	}
+
+	dateRange, err := schedule.FetchRange(s, atTime)
+	if err != nil {
+		resp.Error = function.NewFuncError("Could not calculate the fetch range of the schedule: " + err.Error())
+		return
+	}
+
+	result := types.ObjectValueMust(fetchRangeAttributeTypes, map[string]attr.Value{
+		"start": types.StringValue(dateRange.Start.Format(time.DateOnly)),
+		"end":   types.StringValue(dateRange.End.Format(time.DateOnly)),
+	})
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,162 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+	"terraform-provider-adverity/internal/schedule"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxScheduleRuns limits the number of runs a single call may return.
+const maxScheduleRuns = 1000
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &scheduleNextRunsFunction{}
+
+// NewScheduleNextRunsFunction is a helper function to simplify the provider implementation.
+func NewScheduleNextRunsFunction() function.Function {
+	return &scheduleNextRunsFunction{}
+}
+
+// scheduleNextRunsFunction is the function implementation.
+type scheduleNextRunsFunction struct{}
+
+func (f *scheduleNextRunsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "schedule_next_runs"
+}
+
+func (f *scheduleNextRunsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the next run times of a datastream schedule.",
+		Description: "Returns the next `count` run times (RFC 3339, UTC) of a datastream schedule at or after `from`, without calling the Adverity API. " +
+			"Intervals greater than 1 (e.g. every 2 days) are counted from `not_before_date`, or from 1970-01-01 if it is not set. " +
+			"Fails if the schedule does not run within 10 years, e.g. on the 31st every 12 months counted from April.",
+		Parameters: []function.Parameter{
+			scheduleParameter(),
+			function.Int64Parameter{
+				Name:        "count",
+				Description: fmt.Sprintf("Number of runs to return (1 to %d).", maxScheduleRuns),
+			},
+			function.StringParameter{
+				Name:        "from",
+				Description: "Moment to start from in RFC 3339 format (e.g. `timestamp()` or `2025-01-01T00:00:00Z`).",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *scheduleNextRunsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var scheduleValue types.Dynamic
+	var count int64
+	var from string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &scheduleValue, &count, &from))
+	if resp.Error != nil {
+		return
+	}
+
+	s, err := expandScheduleArgument(scheduleValue)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	if count < 1 || count > maxScheduleRuns {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("count must be between 1 and %d, got %d", maxScheduleRuns, count))
+		return
+	}
+	fromTime, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("from must be a timestamp in RFC 3339 format, got %q", from))
+		return
+	}
+
+	runs, err := schedule.NextRuns(s, fromTime, int(count))
+	if err != nil {
+		resp.Error = function.NewFuncError("Could not calculate the runs of the schedule: " + err.Error())
+		return
+	}
+
+	result := make([]attr.Value, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, types.StringValue(run.Format(time.RFC3339)))
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, types.ListValueMust(types.StringType, result)))
+}
+
+// scheduleParameter returns the schedule parameter shared by the schedule functions.
+func scheduleParameter() function.DynamicParameter {
+	return function.DynamicParameter{
+		Name: "schedule",
+		Description: "Schedule object shaped like the `schedule` block of the `adverity_datastream` resource, " +
+			"e.g. `{ cron_expression = \"30 3 * * 1-5\", time_range = \"custom\", fixed_start = \"2025-01-01\" }` or a schedule of `adverity_datastream.example.schedule`.",
+	}
+}
+
+// expandScheduleArgument maps a schedule object to the API representation, translating a cron expression
+// into the cron fields it represents and readable enums into their numeric codes. Attributes of the schedule block which don't affect when and what a
+// schedule fetches (key and id) are ignored, while unknown attributes are rejected to catch typos.
+func expandScheduleArgument(value types.Dynamic) (adverity.Schedule, error) {
+	var s adverity.Schedule
+
+	converted, err := utils.ConvertValue(value.UnderlyingValue())
+	if err != nil {
+		return s, fmt.Errorf("schedule must be an object: %w", err)
+	}
+	attributes, ok := converted.(map[string]interface{})
+	if !ok {
+		return s, fmt.Errorf("schedule must be an object")
+	}
+
+	cronExpression := attributes["cron_expression"]
+	timeRange := attributes["time_range"]
+	for _, name := range []string{"key", "id", "cron_expression", "time_range"} {
+		delete(attributes, name)
+	}
+
+	payload, err := json.Marshal(attributes)
+	if err != nil {
+		return s, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return s, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	if cronExpression != nil {
+		expr, ok := cronExpression.(string)
+		if !ok {
+			return s, fmt.Errorf("cron_expression must be a string")
+		}
+		cron, err := schedule.ParseCronExpression(expr)
+		if err != nil {
+			return s, fmt.Errorf("unsupported cron expression %q: %w", expr, err)
+		}
+		cron.Apply(&s)
+	}
+	if timeRange != nil {
+		code, ok := adverity.TimeRanges.Code(fmt.Sprint(timeRange))
+		if !ok {
+			return s, fmt.Errorf("time_range must be one of %q", adverity.TimeRanges.Names())
+		}
+		s.TimeRangePreset = &code
+	}
+
+	return s, nil
+}
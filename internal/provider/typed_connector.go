@@ -0,0 +1,265 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strconv"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Typed connector resources are generated from the recorded OPTIONS responses in
+// internal/connectorgen/fixtures. Add a fixture and run go generate to add a connector.
+//go:generate go run ../connectorgen -fixtures ../connectorgen/fixtures -output .
+
+// typedConnector describes a destination or datastream type whose parameters are exposed
+// as typed attributes instead of a free-form parameters attribute.
+type typedConnector struct {
+	// TypeName is appended to the provider type name, e.g. bigquery_destination.
+	TypeName string
+	// TypeID is the destination or datastream type id the fixture was recorded for.
+	TypeID int64
+	// Title is the display name of the connector.
+	Title string
+	// Attributes are the typed parameter attributes.
+	Attributes map[string]schema.Attribute
+}
+
+// schemaAttributes merges the common attributes of a resource with the typed parameter attributes.
+func (c typedConnector) schemaAttributes(common map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes := maps.Clone(common)
+	maps.Copy(attributes, c.Attributes)
+	return attributes
+}
+
+// expandTypedParameters maps the typed parameter attributes, keyed by parameter key, onto parameters.
+// Null values are not sent, so Adverity applies its defaults.
+func expandTypedParameters(values map[string]attr.Value, diags *diag.Diagnostics) []adverity.Parameter {
+	parameters := make([]adverity.Parameter, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value := values[key]
+		if dynamic, ok := value.(types.Dynamic); ok {
+			value = dynamic.UnderlyingValue()
+		}
+		if value == nil || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		converted, err := utils.ConvertValue(value)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root(key),
+				"Invalid parameter",
+				fmt.Sprintf("Failed to convert the parameter %q: %s", key, err.Error()),
+			)
+			continue
+		}
+		parameters = append(parameters, adverity.Parameter{Key: key, Value: converted})
+	}
+
+	return parameters
+}
+
+// refreshTypedParameter maps the parameter key of a response onto the typed parameter attribute name.
+//
+// Parameters missing from the response are kept, as are values which the response holds in another
+// representation (e.g. "10" for 10), so a refresh does not show changes which are none. Attributes which
+// are not set are only filled in on import, since Adverity returns its defaults for parameters which were
+// not sent.
+func refreshTypedParameter[T attr.Value](ctx context.Context, fields map[string]json.RawMessage, key, name string, imported bool, target *T, diags *diag.Diagnostics) {
+	raw, ok := fields[key]
+	current := attr.Value(*target)
+	if !ok || current.IsUnknown() || (current.IsNull() && !imported) {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		diags.AddAttributeWarning(path.Root(name), "Could not refresh parameter", fmt.Sprintf("Could not decode the parameter %q: %s", key, err))
+		return
+	}
+
+	if dynamic, ok := current.(types.Dynamic); ok {
+		current = dynamic.UnderlyingValue()
+	}
+	if current != nil && !current.IsNull() {
+		if converted, err := utils.ConvertValue(current); err == nil && sameParameterValue(converted, value) {
+			return
+		}
+	}
+
+	refreshed, err := typedParameterValue(ctx, (*target).Type(ctx), value)
+	if err == nil {
+		if v, ok := refreshed.(T); ok {
+			*target = v
+			return
+		}
+		err = fmt.Errorf("unexpected value type %T", refreshed)
+	}
+	diags.AddAttributeWarning(
+		path.Root(name),
+		"Could not refresh parameter",
+		fmt.Sprintf("The parameter %q returned by Adverity does not fit the attribute, so the prior value is kept: %s", key, err),
+	)
+}
+
+// typedParameterValue converts a decoded JSON value onto the attribute type. Dynamic attributes get the
+// closest Terraform type of the value.
+func typedParameterValue(ctx context.Context, t attr.Type, value any) (attr.Value, error) {
+	tfType := t.TerraformType(ctx)
+	if tfType.Is(tftypes.DynamicPseudoType) {
+		tfType = inferTerraformType(value)
+	}
+
+	tfValue, err := terraformValue(tfType, value)
+	if err != nil {
+		return nil, err
+	}
+	return t.ValueFromTerraform(ctx, tfValue)
+}
+
+// terraformValue converts a decoded JSON value onto the Terraform type, accepting the representations
+// Adverity uses interchangeably for scalar parameters.
+func terraformValue(t tftypes.Type, value any) (tftypes.Value, error) {
+	if value == nil {
+		return tftypes.NewValue(t, nil), nil
+	}
+
+	switch {
+	case t.Is(tftypes.String):
+		switch v := value.(type) {
+		case string:
+			return tftypes.NewValue(t, v), nil
+		case json.Number:
+			return tftypes.NewValue(t, v.String()), nil
+		case bool:
+			return tftypes.NewValue(t, strconv.FormatBool(v)), nil
+		}
+	case t.Is(tftypes.Number):
+		var s string
+		switch v := value.(type) {
+		case json.Number:
+			s = v.String()
+		case string:
+			s = v
+		}
+		if n, ok := new(big.Float).SetString(s); ok {
+			return tftypes.NewValue(t, n), nil
+		}
+	case t.Is(tftypes.Bool):
+		switch v := value.(type) {
+		case bool:
+			return tftypes.NewValue(t, v), nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return tftypes.NewValue(t, b), nil
+			}
+		}
+	case t.Is(tftypes.List{}):
+		if list, ok := value.([]any); ok {
+			listType, _ := t.(tftypes.List)
+			elements := make([]tftypes.Value, 0, len(list))
+			for _, element := range list {
+				v, err := terraformValue(listType.ElementType, element)
+				if err != nil {
+					return tftypes.Value{}, err
+				}
+				elements = append(elements, v)
+			}
+			return tftypes.NewValue(t, elements), nil
+		}
+	case t.Is(tftypes.Tuple{}):
+		if list, ok := value.([]any); ok {
+			tupleType, _ := t.(tftypes.Tuple)
+			elements := make([]tftypes.Value, 0, len(list))
+			for i, element := range list {
+				v, err := terraformValue(tupleType.ElementTypes[i], element)
+				if err != nil {
+					return tftypes.Value{}, err
+				}
+				elements = append(elements, v)
+			}
+			return tftypes.NewValue(t, elements), nil
+		}
+	case t.Is(tftypes.Object{}):
+		if object, ok := value.(map[string]any); ok {
+			objectType, _ := t.(tftypes.Object)
+			attributes := make(map[string]tftypes.Value, len(object))
+			for key, element := range object {
+				v, err := terraformValue(objectType.AttributeTypes[key], element)
+				if err != nil {
+					return tftypes.Value{}, err
+				}
+				attributes[key] = v
+			}
+			return tftypes.NewValue(t, attributes), nil
+		}
+	}
+
+	return tftypes.Value{}, fmt.Errorf("cannot use %v (%T) as %s", value, value, t)
+}
+
+// inferTerraformType returns the Terraform type of a decoded JSON value, using tuples and objects for
+// lists and objects since their elements may differ in type.
+func inferTerraformType(value any) tftypes.Type {
+	switch v := value.(type) {
+	case string:
+		return tftypes.String
+	case json.Number:
+		return tftypes.Number
+	case bool:
+		return tftypes.Bool
+	case []any:
+		elements := make([]tftypes.Type, 0, len(v))
+		for _, element := range v {
+			elements = append(elements, inferTerraformType(element))
+		}
+		return tftypes.Tuple{ElementTypes: elements}
+	case map[string]any:
+		attributes := make(map[string]tftypes.Type, len(v))
+		for key, element := range v {
+			attributes[key] = inferTerraformType(element)
+		}
+		return tftypes.Object{AttributeTypes: attributes}
+	default:
+		return tftypes.DynamicPseudoType
+	}
+}
+
+// sameParameterValue reports whether a converted attribute value and a decoded JSON value are the same
+// parameter value, comparing scalars by their string forms like the validation of choices.
+func sameParameterValue(converted, value any) bool {
+	switch v := value.(type) {
+	case []any:
+		list, ok := converted.([]interface{})
+		return ok && slices.EqualFunc(list, v, sameParameterValue)
+	case map[string]any:
+		object, ok := converted.(map[string]interface{})
+		return ok && maps.EqualFunc(object, v, sameParameterValue)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return fmt.Sprint(converted) == fmt.Sprint(f)
+		}
+	case nil:
+		return converted == nil
+	}
+	return fmt.Sprint(converted) == fmt.Sprint(value)
+}
@@ -0,0 +1,169 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRefreshTypedParameter(t *testing.T) {
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, 0, len(values))
+		for _, v := range values {
+			elements = append(elements, types.StringValue(v))
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+	limit := func(n int64) types.Dynamic {
+		return types.DynamicValue(types.ObjectValueMust(
+			map[string]attr.Type{"limit": types.NumberType},
+			map[string]attr.Value{"limit": types.NumberValue(big.NewFloat(float64(n)))},
+		))
+	}
+
+	tests := map[string]struct {
+		prior       attr.Value
+		raw         string // the parameter in the response, missing if empty
+		imported    bool
+		want        attr.Value
+		wantWarning bool
+	}{
+		"changed string":              {prior: types.StringValue("campaign"), raw: `"ad_group"`, want: types.StringValue("ad_group")},
+		"string returned as number":   {prior: types.StringValue("10"), raw: `10`, want: types.StringValue("10")},
+		"changed integer":             {prior: types.Int64Value(7), raw: `30`, want: types.Int64Value(30)},
+		"integer returned as string":  {prior: types.Int64Value(7), raw: `"7"`, want: types.Int64Value(7)},
+		"integer changed as string":   {prior: types.Int64Value(7), raw: `"30"`, want: types.Int64Value(30)},
+		"changed boolean":             {prior: types.BoolValue(true), raw: `"false"`, want: types.BoolValue(false)},
+		"boolean returned as string":  {prior: types.BoolValue(true), raw: `"true"`, want: types.BoolValue(true)},
+		"changed list":                {prior: list("a"), raw: `["a", "b"]`, want: list("a", "b")},
+		"unchanged list":              {prior: list("a", "b"), raw: `["a", "b"]`, want: list("a", "b")},
+		"changed dynamic value":       {prior: limit(5), raw: `{"limit": 10}`, want: limit(10)},
+		"unchanged dynamic value":     {prior: limit(5), raw: `{"limit": 5}`, want: limit(5)},
+		"removed value":               {prior: types.StringValue("campaign"), raw: `null`, want: types.StringNull()},
+		"missing from the response":   {prior: types.StringValue("campaign"), want: types.StringValue("campaign")},
+		"unset attribute":             {prior: types.Int64Null(), raw: `30`, want: types.Int64Null()},
+		"unset attribute on import":   {prior: types.Int64Null(), raw: `30`, imported: true, want: types.Int64Value(30)},
+		"dynamic attribute on import": {prior: types.DynamicNull(), raw: `{"limit": 5}`, imported: true, want: limit(5)},
+		"value of another type":       {prior: types.Int64Value(7), raw: `"seven"`, want: types.Int64Value(7), wantWarning: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fields := map[string]json.RawMessage{"other": json.RawMessage(`1`)}
+			if test.raw != "" {
+				fields["key"] = json.RawMessage(test.raw)
+			}
+
+			var diags diag.Diagnostics
+			got := test.prior
+			refreshTypedParameter(t.Context(), fields, "key", "name", test.imported, &got, &diags)
+
+			if !got.Equal(test.want) {
+				t.Errorf("refreshed value = %s, want %s", got, test.want)
+			}
+			if diags.HasError() {
+				t.Errorf("diagnostics = %v", diags)
+			}
+			if gotWarning := diags.WarningsCount() > 0; gotWarning != test.wantWarning {
+				t.Errorf("has warning = %v, want %v: %v", gotWarning, test.wantWarning, diags)
+			}
+		})
+	}
+}
+
+// A refresh of a typed datastream shows the parameters changed outside of Terraform, and fills them in on import.
+func TestTypedDatastreamReadRefreshesParameters(t *testing.T) {
+	client := testAdoptClient(t, nil)
+	r, ok := NewGoogleAdsDatastreamResource().(*typedDatastreamResource[googleAdsDatastreamModel, *googleAdsDatastreamModel])
+	if !ok {
+		t.Fatal("unexpected resource implementation")
+	}
+	r.providerData = &providerData{defaultClient: client}
+
+	plan := googleAdsDatastreamModel{
+		typedDatastreamModel: typedDatastreamModel{
+			DatastreamTypeId: types.Int64Value(testAccDatastreamTypeID),
+			ID:               types.Int64Unknown(),
+			Name:             types.StringValue("Campaigns"),
+			Description:      types.StringUnknown(),
+			StackID:          types.Int64Value(testReplayStackID),
+			AuthID:           types.Int64Value(testReplayAuthorizationID),
+			Enabled:          types.BoolUnknown(),
+			DataType:         types.StringUnknown(),
+			AdoptExisting:    types.BoolNull(),
+			Instance:         types.StringNull(),
+			LastUpdated:      types.StringUnknown(),
+		},
+		Accounts:               types.ListValueMust(types.StringType, []attr.Value{types.StringValue("1234567890")}),
+		ConversionWindow:       types.Int64Value(30),
+		Fields:                 types.ListValueMust(types.StringType, []attr.Value{types.StringValue("metrics.clicks")}),
+		IncludeZeroImpressions: types.BoolNull(),
+		LoginCustomerID:        types.StringNull(),
+		ReportType:             types.StringValue("campaign"),
+	}
+	state := testReplayCreate(t, r, plan)
+	var created googleAdsDatastreamModel
+	if diags := state.Get(t.Context(), &created); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// Change the report type outside of Terraform, and set a parameter the configuration leaves to Adverity
+	_, err := client.UpdateDatastream(t.Context(), testAccDatastreamTypeID, int(created.ID.ValueInt64()), &adverity.DatastreamUpdateConfig{
+		Parameters: &[]adverity.Parameter{{Key: "report_type", Value: "ad_group"}, {Key: "login_customer_id", Value: "9876543210"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(state tfsdk.State) googleAdsDatastreamModel {
+		t.Helper()
+		resp := resource.ReadResponse{State: state}
+		r.Read(t.Context(), resource.ReadRequest{State: state}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Read() diagnostics = %v", resp.Diagnostics)
+		}
+		var refreshed googleAdsDatastreamModel
+		if diags := resp.State.Get(t.Context(), &refreshed); diags.HasError() {
+			t.Fatal(diags)
+		}
+		return refreshed
+	}
+
+	refreshed := read(state)
+	if got := refreshed.ReportType.ValueString(); got != "ad_group" {
+		t.Errorf("report_type = %q, want the changed ad_group", got)
+	}
+	if !refreshed.LoginCustomerID.IsNull() {
+		t.Errorf("login_customer_id = %s, want it to stay unset", refreshed.LoginCustomerID)
+	}
+	if !refreshed.Accounts.Equal(plan.Accounts) || !refreshed.ConversionWindow.Equal(plan.ConversionWindow) {
+		t.Errorf("accounts, conversion_window = %s, %s, want them unchanged", refreshed.Accounts, refreshed.ConversionWindow)
+	}
+
+	// An import only sets the identifiers, all parameters are taken from the response
+	imported := testReplayState(t, r, nil)
+	diags := imported.SetAttribute(t.Context(), path.Root("datastream_type_id"), created.DatastreamTypeId)
+	diags.Append(imported.SetAttribute(t.Context(), path.Root("id"), created.ID)...)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	refreshed = read(imported)
+	if got := refreshed.LoginCustomerID.ValueString(); got != "9876543210" {
+		t.Errorf("login_customer_id after import = %q, want 9876543210", got)
+	}
+	if !refreshed.Accounts.Equal(plan.Accounts) || refreshed.ReportType.ValueString() != "ad_group" {
+		t.Errorf("accounts, report_type after import = %s, %s, want the values of the datastream", refreshed.Accounts, refreshed.ReportType)
+	}
+}
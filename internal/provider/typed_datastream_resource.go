@@ -0,0 +1,408 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// typedDatastream is implemented by the generated models of typed datastream resources.
+type typedDatastream interface {
+	datastream() *typedDatastreamModel
+	parameters(diags *diag.Diagnostics) []adverity.Parameter
+	refreshParameters(ctx context.Context, fields map[string]json.RawMessage, imported bool, diags *diag.Diagnostics)
+}
+
+// typedDatastreamResource is the resource implementation shared by the generated datastream resources.
+// M is the generated model, which embeds typedDatastreamModel. Schedules are managed by the
+// adverity_datastream_schedule resource.
+type typedDatastreamResource[M any, P interface {
+	*M
+	typedDatastream
+}] struct {
+	providerData *providerData
+	connector    typedConnector
+}
+
+// typedDatastreamModel maps the schema data common to all typed datastreams.
+type typedDatastreamModel struct {
+	DatastreamTypeId types.Int64  `tfsdk:"datastream_type_id"`
+	ID               types.Int64  `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	StackID          types.Int64  `tfsdk:"stack_id"`
+	AuthID           types.Int64  `tfsdk:"auth_id"`
+	Enabled          types.Bool   `tfsdk:"enabled"`
+	DataType         types.String `tfsdk:"datatype"`
+	AdoptExisting    types.Bool   `tfsdk:"adopt_existing"`
+	Instance         types.String `tfsdk:"instance"`
+	LastUpdated      types.String `tfsdk:"last_updated"`
+}
+
+func (m *typedDatastreamModel) datastream() *typedDatastreamModel {
+	return m
+}
+
+func (m *typedDatastreamModel) refreshState(datastream *adverity.DatastreamResponse) {
+	m.ID = types.Int64Value(datastream.ID)
+	m.Name = types.StringValue(datastream.Name)
+	m.Description = types.StringValue(datastream.Description)
+	m.StackID = types.Int64Value(datastream.StackID)
+	m.AuthID = types.Int64Value(datastream.AuthID)
+	m.Enabled = types.BoolValue(datastream.Enabled)
+	m.DataType = types.StringValue(datastream.DataType)
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *typedDatastreamResource[M, P]) updatePayload(model P, diags *diag.Diagnostics) *adverity.DatastreamUpdateConfig {
+	m := model.datastream()
+	payload := &adverity.DatastreamUpdateConfig{
+		Name:        m.Name.ValueStringPointer(),
+		Description: m.Description.ValueStringPointer(),
+		StackID:     m.StackID.ValueInt64Pointer(),
+		AuthID:      m.AuthID.ValueInt64Pointer(),
+	}
+	if !m.DataType.IsUnknown() {
+		payload.DataType = m.DataType.ValueStringPointer()
+	}
+	parameters := model.parameters(diags)
+	payload.Parameters = &parameters
+	return payload
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *typedDatastreamResource[M, P]) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+// Metadata returns the resource type name.
+func (r *typedDatastreamResource[M, P]) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.connector.TypeName
+}
+
+// Schema defines the schema for the resource.
+func (r *typedDatastreamResource[M, P]) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: fmt.Sprintf("Manages a %s datastream. Its schedules are managed with the `adverity_datastream_schedule` resource.", r.connector.Title),
+		Attributes: r.connector.schemaAttributes(map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "Numeric identifier of the datastream.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"adopt_existing": adoptExistingAttribute("datastream"),
+			"instance":       instanceAttribute(),
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the datastream.",
+				Computed:    true,
+			},
+			"datastream_type_id": schema.Int64Attribute{
+				Description: fmt.Sprintf("Numeric identifier of the %s datastream type. Defaults to `%d`, only set it if the type has a different identifier on your instance.", r.connector.Title, r.connector.TypeID),
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(r.connector.TypeID),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the datastream.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the datastream.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"stack_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the workspace. Changing it moves the datastream to the workspace in place, keeping its extracts and history.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					stackMove("datastream", path.Root("auth_id"), "authorization",
+						"Destination mappings of the datastream are kept, their destinations are not moved."),
+				},
+			},
+			"auth_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the authorization.",
+				Required:    true,
+			},
+			"datatype": schema.StringAttribute{
+				Description: "Type of the datastream ('Live' or 'Staging').",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("Live", "Staging"),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether to enable the datastream.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		}),
+	}
+}
+
+// Create a new resource.
+func (r *typedDatastreamResource[M, P]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan M
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&plan).datastream().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&plan).datastream()
+
+	// Generate API request body from plan
+	payload := &adverity.DatastreamCreateConfig{
+		Name:        model.Name.ValueStringPointer(),
+		Description: model.Description.ValueStringPointer(),
+		StackID:     model.StackID.ValueInt64Pointer(),
+		AuthID:      model.AuthID.ValueInt64Pointer(),
+		Enabled:     model.Enabled.ValueBoolPointer(),
+	}
+	if !model.DataType.IsUnknown() {
+		payload.DataType = model.DataType.ValueStringPointer()
+	}
+	parameters := P(&plan).parameters(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	payload.Parameters = &parameters
+
+	// Create new datastream, or adopt the existing one
+	datastream, existing := createDatastream(ctx, client, model.DatastreamTypeId, model.StackID, model.Name, model.AdoptExisting, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Hold the datastream until its schedules are set up
+	unlock := client.LockDatastream(int(datastream.ID))
+	defer unlock()
+
+	if existing {
+		// Update the adopted datastream to the plan, its schedules are left to the adverity_datastream_schedule resource
+		updatePayload := r.updatePayload(&plan, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		datastream = adoptDatastream(ctx, client, datastream, &adverity.DatastreamScheduleConfig{Enabled: model.Enabled.ValueBoolPointer()}, updatePayload, &resp.Diagnostics)
+	} else {
+		// Remove the default schedule created by Adverity, schedules are managed
+		// by the adverity_datastream_schedule resource (see datastreamResource.Create).
+		datastream = removeDefaultSchedules(ctx, client, datastream, model.Enabled, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Map response body to schema and populate computed attribute values
+	model.refreshState(datastream)
+	model.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *typedDatastreamResource[M, P]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state M
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&state).datastream().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&state).datastream()
+
+	// Get refreshed datastream value from Adverity
+	datastream := r.providerData.readDatastreamState(ctx, client, model.StackID, model.DatastreamTypeId, model.ID, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite state with refreshed attributes, and all parameters right after an import
+	imported := model.Name.IsNull()
+	model.refreshState(datastream)
+	P(&state).refreshParameters(ctx, datastream.Fields, imported, &resp.Diagnostics)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *typedDatastreamResource[M, P]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and prior state
+	var plan, state M
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&plan).datastream().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&plan).datastream()
+
+	// The enabled flag and the datastream are updated with separate requests,
+	// which must not interleave with other changes of the datastream
+	unlock := client.LockDatastream(int(model.ID.ValueInt64()))
+	defer unlock()
+
+	// Generate API request body with the changes from the prior state to the plan, which leaves
+	// the values left to Adverity unchanged
+	patchPlan := plan
+	P(&patchPlan).datastream().DataType = utils.KnownOrPrior(P(&state).datastream().DataType, model.DataType)
+	payload := utils.PatchPayload(r.updatePayload(&state, &resp.Diagnostics), r.updatePayload(&patchPlan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The enabled flag is only accepted by the schedule endpoint, the schedules themselves are left untouched
+	schedulePayload := &adverity.DatastreamScheduleConfig{
+		Enabled: utils.PatchValue(P(&state).datastream().Enabled, model.Enabled, types.Bool.ValueBoolPointer),
+	}
+
+	// Update existing datastream
+	datastream := updateDatastream(ctx, client, model.DatastreamTypeId, model.ID, schedulePayload, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update resource state with updated attributes and timestamp
+	model.refreshState(datastream)
+	model.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *typedDatastreamResource[M, P]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state M
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&state).datastream().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&state).datastream()
+
+	// Delete existing datastream
+	_, err := client.DeleteDatastream(ctx, int(model.DatastreamTypeId.ValueInt64()), int(model.ID.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting Adverity datastream",
+			"Could not delete datastream, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *typedDatastreamResource[M, P]) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
+	// The datastream type id is optional in the import ID (<id> or <datastream_type_id>:<id>)
+	typeId := r.connector.TypeID
+	idPart := importID
+	if strings.Contains(importID, ":") {
+		parts := utils.SplitImportParts(importID, 2, "<datastream_type_id>:<id>", &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		typeId = utils.ParseImportPartInt(parts[0], "datastream_type_id", &resp.Diagnostics)
+		idPart = parts[1]
+	}
+	id := utils.ParseImportPartInt(idPart, "id", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set the parsed values in state
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("datastream_type_id"), typeId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,354 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+	"terraform-provider-adverity/internal/provider/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// typedDestination is implemented by the generated models of typed destination resources.
+type typedDestination interface {
+	destination() *typedDestinationModel
+	parameters(diags *diag.Diagnostics) []adverity.Parameter
+	sensitiveParameters(diags *diag.Diagnostics) []adverity.Parameter
+	refreshParameters(ctx context.Context, fields map[string]json.RawMessage, imported bool, diags *diag.Diagnostics)
+}
+
+// typedDestinationResource is the resource implementation shared by the generated destination resources.
+// M is the generated model, which embeds typedDestinationModel.
+type typedDestinationResource[M any, P interface {
+	*M
+	typedDestination
+}] struct {
+	providerData *providerData
+	connector    typedConnector
+}
+
+// typedDestinationModel maps the schema data common to all typed destinations.
+type typedDestinationModel struct {
+	DestinationTypeId types.Int64  `tfsdk:"destination_type_id"`
+	ID                types.Int64  `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	StackID           types.Int64  `tfsdk:"stack_id"`
+	AuthID            types.Int64  `tfsdk:"auth_id"`
+	HeadersFormatting types.String `tfsdk:"headers_formatting"`
+	AdoptExisting     types.Bool   `tfsdk:"adopt_existing"`
+	Instance          types.String `tfsdk:"instance"`
+	LastUpdated       types.String `tfsdk:"last_updated"`
+}
+
+func (m *typedDestinationModel) destination() *typedDestinationModel {
+	return m
+}
+
+func (m *typedDestinationModel) refreshState(destination *adverity.DestinationResponse) {
+	m.ID = types.Int64Value(destination.ID)
+	m.Name = types.StringValue(destination.Name)
+	m.StackID = types.Int64Value(destination.StackID)
+	m.AuthID = types.Int64Value(destination.AuthID)
+	m.HeadersFormatting = utils.FlattenEnum(adverity.HeadersFormattings, &destination.HeadersFormatting)
+}
+
+func (m *typedDestinationModel) payload() *adverity.DestinationConfig {
+	return &adverity.DestinationConfig{
+		Name:              m.Name.ValueStringPointer(),
+		StackID:           m.StackID.ValueInt64Pointer(),
+		AuthID:            m.AuthID.ValueInt64Pointer(),
+		HeadersFormatting: utils.ExpandEnum(adverity.HeadersFormattings, m.HeadersFormatting),
+	}
+}
+
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *typedDestinationResource[M, P]) updatePayload(model P, diags *diag.Diagnostics) *adverity.DestinationConfig {
+	payload := model.destination().payload()
+	parameters := model.parameters(diags)
+	sensitiveParameters := model.sensitiveParameters(diags)
+	payload.Parameters = &parameters
+	payload.SensitiveParameters = &sensitiveParameters
+	return payload
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *typedDestinationResource[M, P]) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = data
+}
+
+// Metadata returns the resource type name.
+func (r *typedDestinationResource[M, P]) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.connector.TypeName
+}
+
+// Schema defines the schema for the resource.
+func (r *typedDestinationResource[M, P]) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: fmt.Sprintf("Manages a %s destination.", r.connector.Title),
+		Attributes: r.connector.schemaAttributes(map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "Numeric identifier of the destination.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"adopt_existing": adoptExistingAttribute("destination"),
+			"instance":       instanceAttribute(),
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the destination.",
+				Computed:    true,
+			},
+			"destination_type_id": schema.Int64Attribute{
+				Description: fmt.Sprintf("Numeric identifier of the %s destination type. Defaults to `%d`, only set it if the type has a different identifier on your instance.", r.connector.Title, r.connector.TypeID),
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(r.connector.TypeID),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the destination.",
+				Required:    true,
+			},
+			"stack_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the workspace.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					stackRequiresReplace("destination"),
+				},
+			},
+			"auth_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the authentication.",
+				Optional:    true,
+			},
+			"headers_formatting": schema.StringAttribute{
+				Description: "How to format the column headers. " +
+					"Only `snake_lower` is supported, which replaces spaces by underscores and converts letters to lowercase.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(adverity.HeadersFormattings.Names()...),
+				},
+			},
+		}),
+	}
+}
+
+// Create a new resource.
+func (r *typedDestinationResource[M, P]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan M
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&plan).destination().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&plan).destination()
+
+	// Generate API request body from plan
+	payload := model.payload()
+	parameters := P(&plan).parameters(&resp.Diagnostics)
+	sensitiveParameters := P(&plan).sensitiveParameters(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	payload.Parameters = &parameters
+	payload.SensitiveParameters = &sensitiveParameters
+
+	// Create new destination, or adopt the existing one
+	destination := createDestination(ctx, client, model.DestinationTypeId, model.StackID, model.Name, model.AdoptExisting, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Map response body to schema and populate computed attribute values
+	model.refreshState(destination)
+	model.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *typedDestinationResource[M, P]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state M
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&state).destination().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&state).destination()
+
+	// Get refreshed destination value from Adverity, including the parameters in the state
+	fields := slices.Clone(destinationFields)
+	for _, parameter := range P(&state).parameters(&resp.Diagnostics) {
+		fields = append(fields, parameter.Key)
+	}
+	destination := r.providerData.readDestinationState(ctx, client, model.StackID, model.DestinationTypeId, model.ID, fields, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overwrite state with refreshed attributes, and all parameters right after an import
+	imported := model.Name.IsNull()
+	model.refreshState(destination)
+	P(&state).refreshParameters(ctx, destination.Fields, imported, &resp.Diagnostics)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *typedDestinationResource[M, P]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and prior state
+	var plan, state M
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&plan).destination().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&plan).destination()
+
+	// Generate API request body with the changes from the prior state to the plan
+	payload := utils.PatchPayload(r.updatePayload(&state, &resp.Diagnostics), r.updatePayload(&plan, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update existing destination
+	destination := updateDestination(ctx, client, model.DestinationTypeId, model.ID, payload, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update resource state with updated attributes and timestamp
+	model.refreshState(destination)
+	model.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *typedDestinationResource[M, P]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state M
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Select the client of the instance
+	client := r.providerData.client(P(&state).destination().Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := P(&state).destination()
+
+	// Delete existing destination
+	_, err := client.DeleteDestination(ctx, int(model.DestinationTypeId.ValueInt64()), int(model.ID.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting Adverity destination",
+			"Could not delete destination, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *typedDestinationResource[M, P]) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split the optional instance prefix (<instance>/...) from the import ID
+	importID := importInstance(ctx, req, resp)
+
+	// The destination type id is optional in the import ID (<id> or <destination_type_id>:<id>)
+	typeId := r.connector.TypeID
+	idPart := importID
+	if strings.Contains(importID, ":") {
+		parts := utils.SplitImportParts(importID, 2, "<destination_type_id>:<id>", &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		typeId = utils.ParseImportPartInt(parts[0], "destination_type_id", &resp.Diagnostics)
+		idPart = parts[1]
+	}
+	id := utils.ParseImportPartInt(idPart, "id", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set the parsed values in state
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("destination_type_id"), typeId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
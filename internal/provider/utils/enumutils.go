@@ -0,0 +1,68 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"fmt"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// FlattenEnum returns the readable name of a numeric code, or null if the code is not set or unknown to the provider.
+func FlattenEnum(e adverity.Enum, code *int64) types.String {
+	if code == nil {
+		return types.StringNull()
+	}
+	name, ok := e.Name(*code)
+	if !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(name)
+}
+
+// ExpandEnum returns the numeric code of a readable name, or nil if the name is null, unknown or not supported.
+func ExpandEnum(e adverity.Enum, name types.String) *int64 {
+	if name.IsNull() || name.IsUnknown() {
+		return nil
+	}
+	code, ok := e.Code(name.ValueString())
+	if !ok {
+		return nil
+	}
+	return &code
+}
+
+// ValidateEnumChoice checks the code of a configured readable name against the choices of the field in the metadata
+// returned by readFields. The provider cannot confirm its codes against every instance, so a code which is not offered
+// only adds a warning naming the alternative attribute to set the numeric code with instead.
+// If the metadata cannot be read, a warning is added and the code is not validated.
+func ValidateEnumChoice(p path.Path, name types.String, e adverity.Enum, readFields func() (map[string]adverity.FieldMetadata, error), field string, alternative string, diags *diag.Diagnostics) {
+	code := ExpandEnum(e, name)
+	if code == nil {
+		return
+	}
+
+	fields, err := readFields()
+	if err != nil {
+		diags.AddAttributeWarning(
+			p,
+			"Could not validate readable value",
+			fmt.Sprintf("Could not read the field metadata to validate %s: %s", field, err),
+		)
+		return
+	}
+
+	if err := e.CheckChoice(fields[field], *code); err != nil {
+		diags.AddAttributeWarning(
+			p,
+			"Unconfirmed readable value",
+			fmt.Sprintf("The value %q stands for the code %d of %s, which this Adverity instance may not support: %s. "+
+				"Set the numeric code with %s if it is interpreted differently.", name.ValueString(), *code, field, err, alternative),
+		)
+	}
+}
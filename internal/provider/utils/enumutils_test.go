@@ -0,0 +1,121 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFlattenEnum(t *testing.T) {
+	code, unsupported := int64(3), int64(1)
+	tests := map[string]struct {
+		code *int64
+		want types.String
+	}{
+		"known code":       {code: &code, want: types.StringValue("snake_lower")},
+		"unsupported code": {code: &unsupported, want: types.StringNull()},
+		"unset code":       {code: nil, want: types.StringNull()},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FlattenEnum(adverity.HeadersFormattings, test.code); !got.Equal(test.want) {
+				t.Errorf("FlattenEnum() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExpandEnum(t *testing.T) {
+	code := int64(3)
+	tests := map[string]struct {
+		name types.String
+		want *int64
+	}{
+		"known name":   {name: types.StringValue("snake_lower"), want: &code},
+		"unknown name": {name: types.StringValue("camel"), want: nil},
+		"null":         {name: types.StringNull(), want: nil},
+		"unknown":      {name: types.StringUnknown(), want: nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ExpandEnum(adverity.HeadersFormattings, test.name)
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				t.Errorf("ExpandEnum() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateEnumChoice(t *testing.T) {
+	fields := map[string]adverity.FieldMetadata{
+		"headers_formatting": {Type: "choice", Choices: []adverity.FieldChoice{
+			{Value: float64(0), DisplayName: "No formatting"},
+			{Value: float64(1), DisplayName: "Snake case"},
+		}},
+	}
+	readFields := func() (map[string]adverity.FieldMetadata, error) { return fields, nil }
+
+	tests := map[string]struct {
+		name        types.String
+		readFields  func() (map[string]adverity.FieldMetadata, error)
+		wantWarning bool
+		wantNoRead  bool
+	}{
+		"offered choice": {
+			name: types.StringValue("snake_lower"),
+			readFields: func() (map[string]adverity.FieldMetadata, error) {
+				return map[string]adverity.FieldMetadata{"headers_formatting": {Type: "choice", Choices: []adverity.FieldChoice{{Value: float64(3)}}}}, nil
+			},
+		},
+		"missing choice": {
+			name:        types.StringValue("snake_lower"),
+			readFields:  readFields,
+			wantWarning: true,
+		},
+		"unreadable metadata": {
+			name:        types.StringValue("snake_lower"),
+			readFields:  func() (map[string]adverity.FieldMetadata, error) { return nil, errors.New("forbidden") },
+			wantWarning: true,
+		},
+		"not set": {
+			name:       types.StringNull(),
+			wantNoRead: true,
+		},
+		"unknown": {
+			name:       types.StringUnknown(),
+			wantNoRead: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			read := test.readFields
+			if test.wantNoRead {
+				read = func() (map[string]adverity.FieldMetadata, error) {
+					t.Error("the metadata is read without a readable value")
+					return nil, nil
+				}
+			}
+
+			var diags diag.Diagnostics
+			ValidateEnumChoice(path.Root("headers_formatting"), test.name, adverity.HeadersFormattings, read, "headers_formatting", "parameters", &diags)
+
+			if diags.HasError() {
+				t.Errorf("has error, want none: %v", diags)
+			}
+			if got := diags.WarningsCount() > 0; got != test.wantWarning {
+				t.Errorf("has warning = %v, want %v: %v", got, test.wantWarning, diags)
+			}
+		})
+	}
+}
@@ -38,3 +38,13 @@ func ParseImportPartInt(value string, fieldName string, diagnostics *diag.Diagno
 	}
 	return parsed
 }
+
+// SplitImportInstance splits an optional instance prefix (<instance>/<id>) from an import ID.
+// The instance is empty if the ID has no prefix.
+func SplitImportInstance(id string) (instance string, rest string) {
+	instance, rest, found := strings.Cut(id, "/")
+	if !found {
+		return "", id
+	}
+	return instance, rest
+}
@@ -0,0 +1,317 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Values accepted by boolean fields besides true and false (see Django REST framework's BooleanField).
+var (
+	trueValues  = []string{"true", "True", "TRUE", "on", "On", "ON", "yes", "Yes", "YES", "y", "Y", "t", "T", "1"}
+	falseValues = []string{"false", "False", "FALSE", "off", "Off", "OFF", "no", "No", "NO", "n", "N", "f", "F", "0"}
+)
+
+// ParameterSource is an attribute holding parameters. The values of sensitive sources are never
+// included in diagnostics.
+type ParameterSource struct {
+	Path      path.Path
+	Value     types.Dynamic
+	Sensitive bool
+}
+
+// ParametersSource returns the source of the parameters attribute.
+func ParametersSource(value types.Dynamic) ParameterSource {
+	return ParameterSource{Path: path.Root("parameters"), Value: value}
+}
+
+// ValidateParametersWithMetadata validates the parameters of all sources against the field metadata returned by readFields.
+// The metadata is also read if no parameters are set, to report the required parameters, but not if the parameters are
+// not yet known. If the metadata cannot be read, a warning is added and the parameters are not validated.
+func ValidateParametersWithMetadata(sources []ParameterSource, readFields func() (map[string]adverity.FieldMetadata, error), managed []string, diags *diag.Diagnostics) {
+	if !slices.ContainsFunc(sources, func(source ParameterSource) bool { return known(source.Value) }) &&
+		slices.ContainsFunc(sources, func(source ParameterSource) bool { return source.Value.IsUnknown() }) {
+		return
+	}
+
+	fields, err := readFields()
+	if err != nil {
+		diags.AddAttributeWarning(
+			path.Root("parameters"),
+			"Could not validate parameters",
+			"Could not read the parameter metadata from Adverity, so the parameters are only validated when they are applied: "+err.Error(),
+		)
+		return
+	}
+
+	ValidateParameters(sources, fields, managed, diags)
+}
+
+// parameterEntry is a converted parameter with the source it was set in.
+type parameterEntry struct {
+	source  ParameterSource
+	value   interface{}
+	unknown bool
+}
+
+// ValidateParameters validates the keys, types, required fields and choice values of the parameters
+// of all sources against the field metadata returned by an OPTIONS request. Each key must only be set
+// in one source. Fields set by other attributes of the resource (managed) are not expected in
+// parameters. Unknown values are skipped, and required fields are only checked once all sources are known,
+// including when no parameters are set at all.
+func ValidateParameters(sources []ParameterSource, fields map[string]adverity.FieldMetadata, managed []string, diags *diag.Diagnostics) {
+	if len(fields) == 0 {
+		return
+	}
+
+	entries := make(map[string]parameterEntry)
+	complete := true
+	for _, source := range sources {
+		if source.Value.IsNull() {
+			continue
+		}
+		object, ok := source.Value.UnderlyingValue().(types.Object)
+		if source.Value.IsUnknown() || !ok || object.IsUnknown() {
+			complete = false
+			continue // reported by ExpandParameters
+		}
+
+		converted, err := ConvertObject(object)
+		if err != nil {
+			complete = false
+			continue // reported by ExpandParameters
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(converted)) {
+			if other, ok := entries[key]; ok {
+				diags.AddAttributeError(
+					source.Path.AtName(key),
+					"Duplicate parameter",
+					fmt.Sprintf("The parameter %q is set in both %s and %s.", key, other.source.Path, source.Path),
+				)
+				continue
+			}
+			entries[key] = parameterEntry{
+				source:  source,
+				value:   converted[key],
+				unknown: object.Attributes()[key].IsUnknown(),
+			}
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		entry := entries[key]
+		p := entry.source.Path.AtName(key)
+
+		field, ok := fields[key]
+		switch {
+		case !ok:
+			detail := fmt.Sprintf("The parameter %q is not supported by this type.", key)
+			if suggestion := closestField(key, fields); suggestion != "" {
+				detail += fmt.Sprintf(" Did you mean %q?", suggestion)
+			}
+			diags.AddAttributeError(p, "Unsupported parameter", detail)
+		case field.ReadOnly:
+			diags.AddAttributeError(p, "Read-only parameter", fmt.Sprintf("The parameter %q is read-only and cannot be set.", key))
+		case entry.unknown:
+			// validated once known
+		default:
+			validateField(p, entry.value, field, entry.source.Sensitive, diags)
+		}
+	}
+
+	if !complete {
+		return
+	}
+
+	var missing []string
+	for key, field := range fields {
+		if !field.Required || field.ReadOnly || slices.Contains(managed, key) {
+			continue
+		}
+		if _, ok := entries[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		name := fmt.Sprintf("%q", key)
+		if label := fields[key].Label; label != "" {
+			name += fmt.Sprintf(" (%s)", label)
+		}
+		diags.AddAttributeError(
+			path.Root("parameters"),
+			"Missing required parameter",
+			fmt.Sprintf("The parameter %s is required by this type.", name),
+		)
+	}
+}
+
+// validateField validates a converted value against its field metadata. Null values are accepted.
+// Sensitive values are not included in the diagnostics.
+func validateField(p path.Path, value interface{}, field adverity.FieldMetadata, sensitive bool, diags *diag.Diagnostics) {
+	if value == nil {
+		return
+	}
+
+	invalidType := func(expected string) {
+		diags.AddAttributeError(p, "Invalid parameter type", fmt.Sprintf("Expected %s, got: %s.", expected, describeValue(value, sensitive)))
+	}
+
+	switch field.Type {
+	case "string", "email", "url", "slug", "regex", "date", "datetime", "time", "duration":
+		switch value.(type) {
+		case string, int64, float64:
+		default:
+			invalidType("a string")
+		}
+	case "integer":
+		switch v := value.(type) {
+		case int64:
+		case string:
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				invalidType("an integer")
+			}
+		default:
+			invalidType("an integer")
+		}
+	case "float", "decimal":
+		switch v := value.(type) {
+		case int64, float64:
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				invalidType("a number")
+			}
+		default:
+			invalidType("a number")
+		}
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+		case string, int64:
+			if s := fmt.Sprint(v); !slices.Contains(trueValues, s) && !slices.Contains(falseValues, s) {
+				invalidType("a boolean")
+			}
+		default:
+			invalidType("a boolean")
+		}
+	case "choice":
+		validateChoice(p, value, field.Choices, sensitive, diags)
+	case "multiple choice":
+		list, ok := value.([]interface{})
+		if !ok {
+			invalidType("a list")
+			return
+		}
+		for i, v := range list {
+			if v != nil {
+				validateChoice(p.AtListIndex(i), v, field.Choices, sensitive, diags)
+			}
+		}
+	case "list":
+		list, ok := value.([]interface{})
+		if !ok {
+			invalidType("a list")
+			return
+		}
+		if field.Child != nil {
+			for i, v := range list {
+				validateField(p.AtListIndex(i), v, *field.Child, sensitive, diags)
+			}
+		}
+	case "nested object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			invalidType("an object")
+			return
+		}
+		for key, v := range object {
+			if child, ok := field.Children[key]; ok {
+				validateField(p.AtName(key), v, child, sensitive, diags)
+			} else if len(field.Children) > 0 {
+				diags.AddAttributeError(p.AtName(key), "Unsupported parameter", fmt.Sprintf("The parameter %q is not supported.", key))
+			}
+		}
+	}
+}
+
+// validateChoice validates that a value is one of the choices, comparing their string forms
+// since Terraform numbers and strings are often used interchangeably for choice values.
+func validateChoice(p path.Path, value interface{}, choices []adverity.FieldChoice, sensitive bool, diags *diag.Diagnostics) {
+	if len(choices) == 0 {
+		return
+	}
+
+	valid := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		if fmt.Sprint(choice.Value) == fmt.Sprint(value) {
+			return
+		}
+		valid = append(valid, fmt.Sprintf("%v (%s)", choice.Value, choice.DisplayName))
+	}
+
+	diags.AddAttributeError(
+		p,
+		"Invalid parameter value",
+		fmt.Sprintf("The value %s is not a valid choice, expected one of: %s.", describeValue(value, sensitive), strings.Join(valid, ", ")),
+	)
+}
+
+// describeValue formats a value and its type for diagnostics, omitting sensitive values.
+func describeValue(value interface{}, sensitive bool) string {
+	if sensitive {
+		return fmt.Sprintf("(sensitive value) (%T)", value)
+	}
+	return fmt.Sprintf("%v (%T)", value, value)
+}
+
+func known(value types.Dynamic) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
+
+// closestField returns the field with the smallest edit distance to key, if it is close enough to be a typo.
+func closestField(key string, fields map[string]adverity.FieldMetadata) string {
+	best, bestDistance := "", 4
+	for name, field := range fields {
+		if field.ReadOnly {
+			continue
+		}
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,274 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testParameters returns a parameters value as decoded from an HCL object.
+func testParameters(t *testing.T, attributes map[string]attr.Value) types.Dynamic {
+	t.Helper()
+
+	attributeTypes := make(map[string]attr.Type, len(attributes))
+	for name, value := range attributes {
+		attributeTypes[name] = value.Type(t.Context())
+	}
+	return types.DynamicValue(types.ObjectValueMust(attributeTypes, attributes))
+}
+
+// testDiagnostics returns the summary and detail of each error by the string form of its path.
+func testDiagnostics(t *testing.T, diags diag.Diagnostics) map[string]string {
+	t.Helper()
+
+	got := make(map[string]string, diags.ErrorsCount())
+	for _, d := range diags.Errors() {
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		if !ok {
+			t.Fatalf("diagnostic %v has no path", d)
+		}
+		got[withPath.Path().String()] += d.Summary() + ": " + d.Detail()
+	}
+	return got
+}
+
+func TestValidateParameters(t *testing.T) {
+	fields := map[string]adverity.FieldMetadata{
+		"id":       {Type: "integer", ReadOnly: true},
+		"name":     {Type: "string", Required: true},
+		"project":  {Type: "string", Required: true, Label: "Project"},
+		"dataset":  {Type: "string"},
+		"count":    {Type: "integer"},
+		"ratio":    {Type: "float"},
+		"enabled":  {Type: "boolean"},
+		"region":   {Type: "choice", Choices: []adverity.FieldChoice{{Value: "EU", DisplayName: "Europe"}, {Value: "US", DisplayName: "United States"}}},
+		"mode":     {Type: "choice", Choices: []adverity.FieldChoice{{Value: float64(1), DisplayName: "Append"}}},
+		"metrics":  {Type: "multiple choice", Choices: []adverity.FieldChoice{{Value: "clicks", DisplayName: "Clicks"}, {Value: "cost", DisplayName: "Cost"}}},
+		"accounts": {Type: "list", Child: &adverity.FieldMetadata{Type: "integer"}},
+		"options":  {Type: "nested object", Children: map[string]adverity.FieldMetadata{"limit": {Type: "integer"}}},
+		"password": {Type: "string"},
+	}
+	managed := []string{"name"}
+	project := types.StringValue("analytics")
+
+	tests := map[string]struct {
+		parameters types.Dynamic
+		sensitive  types.Dynamic
+		// wantErrors are the paths with an error and a part of its summary and detail
+		wantErrors map[string]string
+	}{
+		"valid parameters": {
+			parameters: testParameters(t, map[string]attr.Value{
+				"project":  project,
+				"dataset":  types.StringValue("marketing"),
+				"count":    types.StringValue("10"),
+				"ratio":    types.NumberValue(big.NewFloat(0.5)),
+				"enabled":  types.StringValue("yes"),
+				"region":   types.StringValue("EU"),
+				"mode":     types.StringValue("1"),
+				"metrics":  types.TupleValueMust([]attr.Type{types.StringType}, []attr.Value{types.StringValue("cost")}),
+				"accounts": types.TupleValueMust([]attr.Type{types.NumberType}, []attr.Value{types.NumberValue(big.NewFloat(7))}),
+				"options":  testParameters(t, map[string]attr.Value{"limit": types.NumberValue(big.NewFloat(5))}).UnderlyingValue(),
+			}),
+			sensitive: types.DynamicNull(),
+		},
+		"null values are accepted": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project, "count": types.StringNull()}),
+			sensitive:  types.DynamicNull(),
+		},
+		"unsupported parameter with suggestion": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project, "datset": types.StringValue("marketing")}),
+			sensitive:  types.DynamicNull(),
+			wantErrors: map[string]string{`parameters.datset`: `Unsupported parameter: The parameter "datset" is not supported by this type. Did you mean "dataset"?`},
+		},
+		"unsupported parameter without suggestion": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project, "warehouse": types.StringValue("main")}),
+			sensitive:  types.DynamicNull(),
+			wantErrors: map[string]string{`parameters.warehouse`: "is not supported by this type."},
+		},
+		"read-only parameter": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project, "id": types.StringValue("1")}),
+			sensitive:  types.DynamicNull(),
+			wantErrors: map[string]string{`parameters.id`: "Read-only parameter"},
+		},
+		"duplicate parameter": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project, "password": types.StringValue("secret")}),
+			sensitive:  testParameters(t, map[string]attr.Value{"password": types.StringValue("secret")}),
+			wantErrors: map[string]string{`sensitive_parameters.password`: "is set in both parameters and sensitive_parameters"},
+		},
+		"invalid types": {
+			parameters: testParameters(t, map[string]attr.Value{
+				"project":  project,
+				"count":    types.StringValue("ten"),
+				"ratio":    types.BoolValue(true),
+				"enabled":  types.StringValue("maybe"),
+				"accounts": types.StringValue("7"),
+				"options":  types.StringValue("limit=5"),
+			}),
+			sensitive: types.DynamicNull(),
+			wantErrors: map[string]string{
+				`parameters.count`:    "Expected an integer, got: ten (string).",
+				`parameters.ratio`:    "Expected a number",
+				`parameters.enabled`:  "Expected a boolean",
+				`parameters.accounts`: "Expected a list",
+				`parameters.options`:  "Expected an object",
+			},
+		},
+		"invalid nested values": {
+			parameters: testParameters(t, map[string]attr.Value{
+				"project":  project,
+				"metrics":  types.TupleValueMust([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("cost"), types.StringValue("views")}),
+				"accounts": types.TupleValueMust([]attr.Type{types.StringType}, []attr.Value{types.StringValue("first")}),
+				"options":  testParameters(t, map[string]attr.Value{"limit": types.StringValue("5"), "offset": types.StringValue("1")}).UnderlyingValue(),
+			}),
+			sensitive: types.DynamicNull(),
+			wantErrors: map[string]string{
+				`parameters.metrics[1]`:     "expected one of: clicks (Clicks), cost (Cost).",
+				`parameters.accounts[0]`:    "Expected an integer",
+				`parameters.options.offset`: `The parameter "offset" is not supported.`,
+			},
+		},
+		"invalid choice": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project, "region": types.StringValue("APAC")}),
+			sensitive:  types.DynamicNull(),
+			wantErrors: map[string]string{`parameters.region`: "The value APAC (string) is not a valid choice, expected one of: EU (Europe), US (United States)."},
+		},
+		"sensitive values are not shown": {
+			parameters: testParameters(t, map[string]attr.Value{"project": project}),
+			sensitive:  testParameters(t, map[string]attr.Value{"region": types.StringValue("APAC")}),
+			wantErrors: map[string]string{`sensitive_parameters.region`: "The value (sensitive value) (string) is not a valid choice"},
+		},
+		"missing required parameter": {
+			parameters: testParameters(t, map[string]attr.Value{"dataset": types.StringValue("marketing")}),
+			sensitive:  types.DynamicNull(),
+			wantErrors: map[string]string{`parameters`: `The parameter "project" (Project) is required by this type.`},
+		},
+		"required parameter in another source": {
+			parameters: types.DynamicNull(),
+			sensitive:  testParameters(t, map[string]attr.Value{"project": project}),
+		},
+		"no parameters": {
+			parameters: types.DynamicNull(),
+			sensitive:  types.DynamicNull(),
+			wantErrors: map[string]string{`parameters`: `The parameter "project" (Project) is required by this type.`},
+		},
+		"unknown value of a required parameter": {
+			parameters: testParameters(t, map[string]attr.Value{"project": types.StringUnknown(), "count": types.StringUnknown()}),
+			sensitive:  types.DynamicNull(),
+		},
+		"unknown source": {
+			parameters: types.DynamicNull(),
+			sensitive:  types.DynamicUnknown(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sources := []ParameterSource{
+				ParametersSource(test.parameters),
+				{Path: path.Root("sensitive_parameters"), Value: test.sensitive, Sensitive: true},
+			}
+
+			var diags diag.Diagnostics
+			ValidateParameters(sources, fields, managed, &diags)
+
+			got := testDiagnostics(t, diags)
+			if len(got) != len(test.wantErrors) {
+				t.Fatalf("errors = %v, want errors for %v", got, test.wantErrors)
+			}
+			for p, want := range test.wantErrors {
+				if !strings.Contains(got[p], want) {
+					t.Errorf("error of %s = %q, want it to contain %q", p, got[p], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateParametersWithMetadata(t *testing.T) {
+	fields := map[string]adverity.FieldMetadata{"project": {Type: "string", Required: true}}
+
+	tests := map[string]struct {
+		parameters  types.Dynamic
+		readErr     error
+		wantRead    bool
+		wantError   bool
+		wantWarning bool
+	}{
+		"parameters": {
+			parameters: testParameters(t, map[string]attr.Value{"project": types.StringValue("analytics")}),
+			wantRead:   true,
+		},
+		"no parameters": {
+			parameters: types.DynamicNull(),
+			wantRead:   true,
+			wantError:  true,
+		},
+		"unknown parameters": {
+			parameters: types.DynamicUnknown(),
+		},
+		"unreadable metadata": {
+			parameters:  types.DynamicNull(),
+			readErr:     errors.New("forbidden"),
+			wantRead:    true,
+			wantWarning: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			read := false
+			readFields := func() (map[string]adverity.FieldMetadata, error) {
+				read = true
+				return fields, test.readErr
+			}
+
+			var diags diag.Diagnostics
+			ValidateParametersWithMetadata([]ParameterSource{ParametersSource(test.parameters)}, readFields, nil, &diags)
+
+			if read != test.wantRead {
+				t.Errorf("read metadata = %v, want %v", read, test.wantRead)
+			}
+			if got := diags.HasError(); got != test.wantError {
+				t.Errorf("has error = %v, want %v: %v", got, test.wantError, diags)
+			}
+			if got := diags.WarningsCount() > 0; got != test.wantWarning {
+				t.Errorf("has warning = %v, want %v: %v", got, test.wantWarning, diags)
+			}
+		})
+	}
+}
+
+func TestClosestField(t *testing.T) {
+	fields := map[string]adverity.FieldMetadata{
+		"dataset":    {Type: "string"},
+		"dataset_id": {Type: "string", ReadOnly: true},
+		"project":    {Type: "string"},
+		"projects":   {Type: "string"},
+	}
+
+	tests := map[string]string{
+		"datset":     "dataset",
+		"Dataset":    "dataset",
+		"dataset_ix": "dataset", // read-only fields are not suggested
+		"projectx":   "project", // ties are broken by name
+		"warehouse":  "",
+	}
+
+	for key, want := range tests {
+		if got := closestField(key, fields); got != want {
+			t.Errorf("closestField(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"reflect"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var parametersType = reflect.TypeOf((*[]adverity.Parameter)(nil))
+
+// PatchPayload returns the changes of the planned payload to the prior payload, which is built from the
+// prior state the same way, so PATCH requests only contain what actually changed. Fields equal to the
+// prior payload are left out, parameters are only sent if they were added or changed, and removed
+// fields and parameters (also within nested parameters) are sent as null, which removes them in Adverity.
+// Payloads are structs of pointer fields, whose Parameters field is flattened into the JSON object (see
+// adverity.FlattenedMarshal), so removed fields are sent as parameters with a null value. Use EmptyPatch
+// to skip the request if nothing changed.
+func PatchPayload[T any](prior, planned *T) *T {
+	patch := *planned
+	p := reflect.ValueOf(&patch).Elem()
+	o := reflect.ValueOf(prior).Elem()
+
+	// A parameter may move between the parameter fields (e.g. from parameters to sensitive_parameters),
+	// so only parameters which are not planned in any field are removed
+	plannedKeys := make(map[string]bool)
+	for i := 0; i < p.NumField(); i++ {
+		if params, ok := p.Field(i).Interface().(*[]adverity.Parameter); ok && params != nil {
+			for _, param := range *params {
+				plannedKeys[param.Key] = true
+			}
+		}
+	}
+
+	var removed []adverity.Parameter
+	for i := 0; i < p.NumField(); i++ {
+		field, priorField := p.Field(i), o.Field(i)
+		if field.Type() == parametersType {
+			params, _ := field.Interface().(*[]adverity.Parameter)
+			priorParams, _ := priorField.Interface().(*[]adverity.Parameter)
+			field.Set(reflect.ValueOf(patchParameters(priorParams, params, plannedKeys)))
+			continue
+		}
+		if field.Kind() != reflect.Ptr {
+			continue
+		}
+
+		switch {
+		case field.IsNil():
+			name, _, _ := strings.Cut(p.Type().Field(i).Tag.Get("json"), ",")
+			if !priorField.IsNil() && name != "" && name != "-" {
+				removed = append(removed, adverity.Parameter{Key: name, Value: nil})
+			}
+		case !priorField.IsNil() && reflect.DeepEqual(field.Elem().Interface(), priorField.Elem().Interface()):
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+
+	if params := p.FieldByName("Parameters"); len(removed) > 0 && params.Type() == parametersType {
+		if changed, _ := params.Interface().(*[]adverity.Parameter); changed != nil {
+			removed = append(*changed, removed...)
+		}
+		params.Set(reflect.ValueOf(&removed))
+	}
+
+	return &patch
+}
+
+// EmptyPatch returns whether a payload returned by PatchPayload contains no changes, so no PATCH request is needed.
+func EmptyPatch[T any](patch *T) bool {
+	p := reflect.ValueOf(patch).Elem()
+	for i := 0; i < p.NumField(); i++ {
+		if field := p.Field(i); field.Kind() == reflect.Ptr && !field.IsNil() {
+			return false
+		}
+	}
+	return true
+}
+
+// patchParameters returns the added and changed parameters and the removed parameters with a null value,
+// or nil if no parameter changed. All prior parameters are removed if there are no planned parameters.
+func patchParameters(prior, planned *[]adverity.Parameter, plannedKeys map[string]bool) *[]adverity.Parameter {
+	priorValues := make(map[string]interface{})
+	if prior != nil {
+		for _, p := range *prior {
+			priorValues[p.Key] = p.Value
+		}
+	}
+
+	var changed []adverity.Parameter
+	var plannedParams []adverity.Parameter
+	if planned != nil {
+		plannedParams = *planned
+	}
+	for _, p := range plannedParams {
+		if value, ok := priorValues[p.Key]; !ok || !reflect.DeepEqual(value, p.Value) {
+			changed = append(changed, adverity.Parameter{Key: p.Key, Value: patchValue(value, p.Value)})
+		}
+		delete(priorValues, p.Key)
+	}
+	for key := range priorValues {
+		if !plannedKeys[key] {
+			changed = append(changed, adverity.Parameter{Key: key, Value: nil})
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	return &changed
+}
+
+// patchValue returns the planned value of a changed parameter. The parameters removed from a nested
+// object are added with a null value, so they are removed along with the changes of the object.
+func patchValue(prior, planned interface{}) interface{} {
+	priorObject, ok := prior.(map[string]interface{})
+	plannedObject, plannedOk := planned.(map[string]interface{})
+	if !ok || !plannedOk {
+		return planned
+	}
+
+	patch := make(map[string]interface{}, len(plannedObject))
+	for key, value := range plannedObject {
+		patch[key] = patchValue(priorObject[key], value)
+	}
+	for key := range priorObject {
+		if _, ok := plannedObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
+
+// KnownOrPrior returns the planned value of an attribute, or the prior value if it is unknown. Update payloads
+// leave out unknown values, which would be sent as removed by PatchPayload, so the payload of the plan is built
+// with the prior values instead, which leaves them out of the patch.
+func KnownOrPrior[V attr.Value](prior, planned V) V {
+	if planned.IsUnknown() {
+		return prior
+	}
+	return planned
+}
+
+// PatchValue returns the planned value of an attribute, or nil if it is unknown or equal to the prior state.
+// It is used for the attributes which are not sent with the payload of a resource but separately, e.g.
+// the enabled flag of a datastream.
+func PatchValue[V attr.Value, T any](prior, planned V, value func(V) *T) *T {
+	if planned.IsUnknown() || planned.Equal(prior) {
+		return nil
+	}
+	return value(planned)
+}
+
+// ExpandDynamicParameters returns the parameters of a dynamic parameters attribute for a payload, or nil if it is null.
+func ExpandDynamicParameters(params types.Dynamic, path path.Path, diags *diag.Diagnostics) *[]adverity.Parameter {
+	if params.IsNull() {
+		return nil
+	}
+	parameters := ExpandParameters(params.UnderlyingValue(), path, diags)
+	return &parameters
+}
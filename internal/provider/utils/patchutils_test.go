@@ -0,0 +1,171 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPatchPayload(t *testing.T) {
+	name, renamed := "Warehouse", "Warehouse EMEA"
+	stack := int64(1)
+	prior := &adverity.DestinationConfig{
+		Name:                &name,
+		StackID:             &stack,
+		Parameters:          &[]adverity.Parameter{{Key: "project", Value: "analytics"}, {Key: "dataset", Value: "marketing"}, {Key: "region", Value: "EU"}},
+		SensitiveParameters: &[]adverity.Parameter{{Key: "password", Value: "secret"}},
+	}
+	planned := &adverity.DestinationConfig{
+		Name:    &renamed,
+		StackID: &stack,
+		// dataset is changed, region is removed and password is moved from the sensitive parameters
+		Parameters: &[]adverity.Parameter{{Key: "project", Value: "analytics"}, {Key: "dataset", Value: "sales"}, {Key: "password", Value: "secret"}},
+	}
+
+	data, err := json.Marshal(PatchPayload(prior, planned))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{"name": renamed, "dataset": "sales", "region": nil, "password": "secret"}
+	if len(got) != len(want) {
+		t.Fatalf("PatchPayload() = %s, want %v", data, want)
+	}
+	for key, value := range want {
+		if v, ok := got[key]; !ok || v != value {
+			t.Errorf("PatchPayload()[%q] = %v, want %v", key, v, value)
+		}
+	}
+
+	// Nothing is sent if nothing changed
+	if patch := PatchPayload(prior, prior); !EmptyPatch(patch) {
+		data, _ := json.Marshal(patch)
+		t.Errorf("PatchPayload() without changes = %s, want an empty patch", data)
+	}
+	if EmptyPatch(PatchPayload(prior, planned)) {
+		t.Error("EmptyPatch() = true, want the changes")
+	}
+}
+
+// testPatch returns the JSON object of the patch of two payloads.
+func testPatch[T any](t *testing.T, prior, planned *T) map[string]any {
+	t.Helper()
+
+	data, err := json.Marshal(PatchPayload(prior, planned))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestPatchPayloadRemovedFields(t *testing.T) {
+	title, description, dataType := "Campaigns", "Daily campaigns", "Live"
+	retention := int64(30)
+
+	tests := map[string]struct {
+		prior   *adverity.DatastreamUpdateConfig
+		planned *adverity.DatastreamUpdateConfig
+		want    map[string]any
+	}{
+		"removed field": {
+			prior:   &adverity.DatastreamUpdateConfig{Name: &title, Description: &description},
+			planned: &adverity.DatastreamUpdateConfig{Name: &title},
+			want:    map[string]any{"description": nil},
+		},
+		"removed fields with changed parameters": {
+			prior: &adverity.DatastreamUpdateConfig{
+				Name: &title, DataType: &dataType, RetentionNumber: &retention,
+				Parameters: &[]adverity.Parameter{{Key: "report_type", Value: "campaign"}},
+			},
+			planned: &adverity.DatastreamUpdateConfig{
+				Name:       &title,
+				Parameters: &[]adverity.Parameter{{Key: "report_type", Value: "ad_group"}},
+			},
+			want: map[string]any{"datatype": nil, "retention_number": nil, "report_type": "ad_group"},
+		},
+		"removed field without parameters": {
+			prior:   &adverity.DatastreamUpdateConfig{Name: &title, RetentionNumber: &retention, Parameters: &[]adverity.Parameter{{Key: "report_type", Value: "campaign"}}},
+			planned: &adverity.DatastreamUpdateConfig{Name: &title},
+			want:    map[string]any{"retention_number": nil, "report_type": nil},
+		},
+		"added field": {
+			prior:   &adverity.DatastreamUpdateConfig{Name: &title},
+			planned: &adverity.DatastreamUpdateConfig{Name: &title, Description: &description},
+			want:    map[string]any{"description": description},
+		},
+		"unset field": {
+			prior:   &adverity.DatastreamUpdateConfig{Name: &title},
+			planned: &adverity.DatastreamUpdateConfig{Name: &title},
+			want:    map[string]any{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := testPatch(t, test.prior, test.planned)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("PatchPayload() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPatchPayloadNestedParameters(t *testing.T) {
+	prior := &adverity.DestinationConfig{
+		Parameters: &[]adverity.Parameter{
+			{Key: "options", Value: map[string]any{"limit": float64(5), "offset": float64(1), "sort": map[string]any{"field": "date", "order": "asc"}}},
+			{Key: "filters", Value: map[string]any{"country": "AT"}},
+			{Key: "labels", Value: []any{"a", "b"}},
+		},
+	}
+	planned := &adverity.DestinationConfig{
+		Parameters: &[]adverity.Parameter{
+			// offset and the sort order are removed, the limit is changed
+			{Key: "options", Value: map[string]any{"limit": float64(10), "sort": map[string]any{"field": "date"}}},
+			// the whole object is removed from the filters
+			{Key: "filters", Value: map[string]any{}},
+			// lists are replaced as a whole
+			{Key: "labels", Value: []any{"a"}},
+		},
+	}
+
+	want := map[string]any{
+		"options": map[string]any{"limit": float64(10), "offset": nil, "sort": map[string]any{"field": "date", "order": nil}},
+		"filters": map[string]any{"country": nil},
+		"labels":  []any{"a"},
+	}
+	if got := testPatch(t, prior, planned); !reflect.DeepEqual(got, want) {
+		t.Errorf("PatchPayload() = %v, want %v", got, want)
+	}
+
+	// A removed nested object is sent as null like any removed parameter
+	planned.Parameters = &[]adverity.Parameter{(*prior.Parameters)[0], (*prior.Parameters)[2]}
+	if got := testPatch(t, prior, planned); !reflect.DeepEqual(got, map[string]any{"filters": nil}) {
+		t.Errorf("PatchPayload() = %v, want the filters removed", got)
+	}
+}
+
+func TestKnownOrPrior(t *testing.T) {
+	prior := types.StringValue("Live")
+	if got := KnownOrPrior(prior, types.StringUnknown()); !got.Equal(prior) {
+		t.Errorf("KnownOrPrior() of an unknown value = %s, want the prior value", got)
+	}
+	if got := KnownOrPrior(prior, types.StringNull()); !got.IsNull() {
+		t.Errorf("KnownOrPrior() of a null value = %s, want null", got)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-adverity/internal/schedule"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type cronExpressionValidator struct{}
+
+func CronExpression() validator.String {
+	return cronExpressionValidator{}
+}
+
+func (v cronExpressionValidator) Description(_ context.Context) string {
+	return "Standard five-field cron expression representable as an Adverity schedule"
+}
+
+func (v cronExpressionValidator) MarkdownDescription(_ context.Context) string {
+	return "Standard five-field cron expression representable as an Adverity schedule"
+}
+
+func (v cronExpressionValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := schedule.ParseCronExpression(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Unsupported cron expression",
+			fmt.Sprintf("The cron expression %q cannot be translated into an Adverity schedule: %s.", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
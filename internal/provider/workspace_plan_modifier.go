@@ -0,0 +1,99 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ planmodifier.Int64 = workspaceMoveModifier{}
+
+// stackRequiresReplace returns a plan modifier which replaces an object when it is assigned to another
+// workspace, since Adverity cannot move the object. Setting stack_id for an object without one in the
+// state (e.g. after an import) or removing it from the configuration does not replace the object.
+func stackRequiresReplace(object string) planmodifier.Int64 {
+	description := fmt.Sprintf("Changing the workspace replaces the %s, since Adverity cannot move it to another workspace.", object)
+
+	return int64planmodifier.RequiresReplaceIf(
+		func(_ context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+			// The framework only calls this for values which differ from the state, an unknown workspace may differ as well
+			resp.RequiresReplace = !req.StateValue.IsNull() && !req.PlanValue.IsNull()
+		},
+		description,
+		description,
+	)
+}
+
+// workspaceMoveModifier warns when an object is moved to another workspace in place. Adverity keeps the
+// extracts and history of a moved object, but does not move the objects it references (e.g. the
+// authorization of a datastream) or the objects referencing it, which may have to be moved as well.
+type workspaceMoveModifier struct {
+	// object is the kind of the moved object, e.g. datastream.
+	object string
+	// reference is an optional attribute referencing an object which is not moved along, e.g. auth_id.
+	reference path.Path
+	// referenced is the kind of the object referenced by the reference attribute, e.g. authorization.
+	referenced string
+	// dependents describes the objects referencing the moved object, which are not moved along.
+	dependents string
+}
+
+// stackMove returns a plan modifier which warns about the objects which are not moved along when
+// the object is moved to another workspace.
+func stackMove(object string, reference path.Path, referenced, dependents string) planmodifier.Int64 {
+	return workspaceMoveModifier{object: object, reference: reference, referenced: referenced, dependents: dependents}
+}
+
+func (m workspaceMoveModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("Changing the workspace moves the %s in place, keeping its extracts and history.", m.object)
+}
+
+func (m workspaceMoveModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m workspaceMoveModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	// Nothing is moved on create, on destroy or when the workspace does not change
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.Equal(req.StateValue) {
+		return
+	}
+
+	target := "another workspace"
+	if !req.PlanValue.IsUnknown() {
+		target = fmt.Sprintf("workspace %d", req.PlanValue.ValueInt64())
+	}
+
+	details := []string{fmt.Sprintf("The %s is moved from workspace %d to %s in place, keeping its extracts and history.",
+		m.object, req.StateValue.ValueInt64(), target)}
+
+	if m.referenced != "" {
+		var reference types.Int64
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, m.reference, &reference)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !reference.IsNull() && !reference.IsUnknown() {
+			details = append(details, fmt.Sprintf("It keeps using %s %d (%s), which is not moved along. "+
+				"If the %s is not available in %s, move it as well or use another %s.",
+				m.referenced, reference.ValueInt64(), m.reference, m.referenced, target, m.referenced))
+		}
+	}
+	if m.dependents != "" {
+		details = append(details, m.dependents)
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		req.Path,
+		fmt.Sprintf("%s%s moves to another workspace", strings.ToUpper(m.object[:1]), m.object[1:]),
+		strings.Join(details, " "),
+	)
+}
@@ -0,0 +1,65 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestWorkspaceMoveModifier(t *testing.T) {
+	r := &datastreamResource{}
+	prior := testReplayDatastreamPlan()
+	prior.ID = types.Int64Value(7)
+	prior.StackID = types.Int64Value(5)
+	state := testReplayState(t, r, prior)
+
+	tests := []struct {
+		name    string
+		stack   types.Int64
+		want    []string
+		noWarns bool
+	}{
+		{name: "unchanged", stack: types.Int64Value(5), noWarns: true},
+		{name: "removed", stack: types.Int64Null(), noWarns: true},
+		{name: "moved", stack: types.Int64Value(8), want: []string{"from workspace 5 to workspace 8", "authorization 2 (auth_id)", "Destination mappings"}},
+		{name: "unknown", stack: types.Int64Unknown(), want: []string{"to another workspace"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned := prior
+			planned.StackID = tt.stack
+			req := planmodifier.Int64Request{
+				Path:       path.Root("stack_id"),
+				State:      state,
+				StateValue: prior.StackID,
+				Plan:       tfsdk.Plan(testReplayState(t, r, planned)),
+				PlanValue:  tt.stack,
+			}
+			resp := planmodifier.Int64Response{PlanValue: tt.stack}
+			stackMove("datastream", path.Root("auth_id"), "authorization", "Destination mappings of the datastream are kept.").PlanModifyInt64(t.Context(), req, &resp)
+
+			if tt.noWarns {
+				if resp.Diagnostics.WarningsCount() != 0 {
+					t.Errorf("diagnostics = %v, want no warning", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.WarningsCount() != 1 {
+				t.Fatalf("diagnostics = %v, want a warning", resp.Diagnostics)
+			}
+			detail := resp.Diagnostics.Warnings()[0].Detail()
+			for _, want := range tt.want {
+				if !strings.Contains(detail, want) {
+					t.Errorf("warning = %q, want it to contain %q", detail, want)
+				}
+			}
+		})
+	}
+}
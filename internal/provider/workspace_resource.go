@@ -11,6 +11,7 @@ import (
 	"terraform-provider-adverity/internal/adverity"
 	"terraform-provider-adverity/internal/provider/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -33,7 +34,7 @@ func NewWorkspaceResource() resource.Resource {
 
 // workspaceResource is the resource implementation.
 type workspaceResource struct {
-	client *adverity.Client
+	providerData *providerData
 }
 
 // workspaceResourceModel maps the resource schema data.
@@ -44,6 +45,7 @@ type workspaceResourceModel struct {
 	DatalakeID  types.Int64   `tfsdk:"datalake_id"`
 	ParentID    types.Int64   `tfsdk:"parent_id"`
 	Parameters  types.Dynamic `tfsdk:"parameters"`
+	Instance    types.String  `tfsdk:"instance"`
 	LastUpdated types.String  `tfsdk:"last_updated"`
 }
 
@@ -54,6 +56,16 @@ func (r *workspaceResource) refreshState(workspace *adverity.WorkspaceResponse,
 	state.ParentID = types.Int64Value(workspace.ParentID)
 }
 
+// updatePayload builds the payload of an update request from the plan or the prior state.
+func (r *workspaceResource) updatePayload(model workspaceResourceModel, diags *diag.Diagnostics) *adverity.WorkspaceConfig {
+	return &adverity.WorkspaceConfig{
+		Name:       model.Name.ValueStringPointer(),
+		DatalakeID: model.DatalakeID.ValueInt64Pointer(),
+		ParentID:   model.ParentID.ValueInt64Pointer(),
+		Parameters: utils.ExpandDynamicParameters(model.Parameters, path.Root("parameters"), diags),
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *workspaceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -62,18 +74,18 @@ func (r *workspaceResource) Configure(_ context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*adverity.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *adverity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = data
 }
 
 // Metadata returns the resource type name.
@@ -93,6 +105,7 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"instance": instanceAttribute(),
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the workspace.",
 				Computed:    true,
@@ -131,6 +144,12 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	payload := &adverity.WorkspaceConfig{
 		Name:       plan.Name.ValueStringPointer(),
@@ -147,7 +166,7 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	// Create new workspace
-	workspace, err := r.client.CreateWorkspace(payload)
+	workspace, err := client.CreateWorkspace(ctx, payload)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating workspace",
@@ -178,8 +197,14 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get refreshed workspace value from Adverity
-	workspace, err := r.client.ReadWorkspace(state.Slug.ValueString())
+	workspace, err := client.ReadWorkspace(ctx, state.Slug.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Adverity workspace",
@@ -200,39 +225,36 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan workspaceResourceModel
+	// Retrieve values from plan and prior state
+	var plan, state workspaceResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Generate API request body from plan
-	payload := &adverity.WorkspaceConfig{
-		Name:       plan.Name.ValueStringPointer(),
-		DatalakeID: plan.DatalakeID.ValueInt64Pointer(),
-		ParentID:   plan.ParentID.ValueInt64Pointer(),
-	}
-
-	if !plan.Parameters.IsNull() {
-		parameters := utils.ExpandParameters(plan.Parameters.UnderlyingValue(), path.Root("parameters"), &resp.Diagnostics)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		payload.Parameters = &parameters
+	// Select the client of the instance
+	client := r.providerData.client(plan.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Retrieve slug from state
-	var slug types.String
-	diags = req.State.GetAttribute(ctx, path.Root("slug"), &slug)
-	resp.Diagnostics.Append(diags...)
+	// Generate API request body with the changes from the prior state to the plan
+	payload := utils.PatchPayload(r.updatePayload(state, &resp.Diagnostics), r.updatePayload(plan, &resp.Diagnostics))
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Update existing workspace
-	workspace, err := r.client.UpdateWorkspace(slug.ValueString(), payload)
+	// Update existing workspace, which is identified by the slug from the prior state, or read it if nothing changed
+	var workspace *adverity.WorkspaceResponse
+	var err error
+	if utils.EmptyPatch(payload) {
+		workspace, err = client.ReadWorkspace(ctx, state.Slug.ValueString())
+	} else {
+		workspace, err = client.UpdateWorkspace(ctx, state.Slug.ValueString(), payload)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Adverity workspace",
@@ -262,8 +284,14 @@ func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	// Select the client of the instance
+	client := r.providerData.client(state.Instance, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete existing workspace
-	_, err := r.client.DeleteWorkspace(state.Slug.ValueString())
+	_, err := client.DeleteWorkspace(ctx, state.Slug.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting Adverity workspace",
@@ -274,6 +302,7 @@ func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteReque
 }
 
 func (r *workspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import slug and save to slug attribute, since slug is used for workspaces retrieval
-	resource.ImportStatePassthroughID(ctx, path.Root("slug"), req, resp)
+	// Split the optional instance prefix (<instance>/<slug>) from the import ID
+	slug := importInstance(ctx, req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("slug"), slug)...)
 }
@@ -0,0 +1,90 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccWorkspaceResource(t *testing.T) {
+	server, providerConfig := testAccFakeServer(t)
+	var id int64
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(server, "adverity_workspace", "stack"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceResourceConfig("Marketing"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_workspace.test", tfjsonpath.New("name"), knownvalue.StringExact("Marketing")),
+					statecheck.ExpectKnownValue("adverity_workspace.test", tfjsonpath.New("slug"), knownvalue.StringExact("marketing")),
+					statecheck.ExpectKnownValue("adverity_workspace.test", tfjsonpath.New("parent_id"), knownvalue.Int64Exact(1)),
+				},
+				Check: func(s *terraform.State) (err error) {
+					id, err = testAccResourceID(s, "adverity_workspace.test")
+					return err
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:            "adverity_workspace.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateId("adverity_workspace.test", "slug"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Drift testing: a rename outside of Terraform is reverted
+			{
+				PreConfig: func() {
+					if err := server.Modify("stack", id, map[string]any{"name": "Renamed in Adverity"}); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: providerConfig + testAccWorkspaceResourceConfig("Marketing"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_workspace.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_workspace.test", tfjsonpath.New("name"), knownvalue.StringExact("Marketing")),
+				},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccWorkspaceResourceConfig("Marketing EMEA"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("adverity_workspace.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("adverity_workspace.test", tfjsonpath.New("name"), knownvalue.StringExact("Marketing EMEA")),
+					statecheck.ExpectKnownValue("adverity_workspace.test", tfjsonpath.New("slug"), knownvalue.StringExact("marketing")),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccWorkspaceResourceConfig(name string) string {
+	return fmt.Sprintf(`
+resource "adverity_workspace" "test" {
+  name      = %[1]q
+  parent_id = 1
+}
+`, name)
+}
@@ -0,0 +1,276 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package schedule translates and evaluates Adverity datastream schedules.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+// Cron holds the cron fields of an Adverity schedule.
+type Cron struct {
+	Type          string
+	Interval      int64
+	IntervalStart int64
+	StartOfDay    string
+}
+
+// Apply sets the cron fields of the given schedule.
+func (c Cron) Apply(s *adverity.Schedule) {
+	s.CronType = &c.Type
+	s.CronInterval = &c.Interval
+	s.CronIntervalStart = &c.IntervalStart
+	s.CronStartOfDay = &c.StartOfDay
+}
+
+var weekdayNames = map[string]int64{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// ParseCronExpression translates a standard five-field cron expression
+// (minute, hour, day of month, month, day of week) into Adverity cron fields.
+//
+// Only expressions with an Adverity equivalent are supported:
+//
+//	M *|*/N|A/N * * *   every N hours (N must divide 24), starting at hour A
+//	M H * * *           every day
+//	M H * * 1-5         every weekday
+//	M H * * D           every week on weekday D
+//	M H D * *           every month on day D
+func ParseCronExpression(expr string) (Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("expected 5 fields (minute, hour, day of month, month, day of week), got %d", len(fields))
+	}
+	minuteField, hourField, domField, monthField, dowField := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	minute, err := parseNumber(minuteField, 0, 59)
+	if err != nil {
+		return Cron{}, fmt.Errorf("minute: %w (Adverity schedules run at a single minute)", err)
+	}
+	if monthField != "*" {
+		return Cron{}, fmt.Errorf("month: Adverity schedules cannot be restricted to specific months, got %q", monthField)
+	}
+
+	// Hourly schedules
+	if hourField == "*" || strings.Contains(hourField, "/") {
+		start, interval, err := parseHourStep(hourField)
+		if err != nil {
+			return Cron{}, fmt.Errorf("hour: %w", err)
+		}
+		if domField != "*" || dowField != "*" {
+			return Cron{}, fmt.Errorf("hourly schedules cannot be restricted to specific days")
+		}
+		return Cron{
+			Type:          adverity.CronTypeHour,
+			Interval:      interval,
+			IntervalStart: start,
+			StartOfDay:    fmt.Sprintf("00:%02d:00", minute),
+		}, nil
+	}
+
+	hour, err := parseNumber(hourField, 0, 23)
+	if err != nil {
+		return Cron{}, fmt.Errorf("hour: %w", err)
+	}
+	startOfDay := fmt.Sprintf("%02d:%02d:00", hour, minute)
+
+	switch {
+	case domField == "*" && dowField == "*":
+		return Cron{Type: adverity.CronTypeDay, Interval: 1, IntervalStart: 1, StartOfDay: startOfDay}, nil
+
+	case domField == "*":
+		if isWeekdayRange(dowField) {
+			return Cron{Type: adverity.CronTypeWeekday, Interval: 1, IntervalStart: 1, StartOfDay: startOfDay}, nil
+		}
+		weekday, err := parseWeekday(dowField)
+		if err != nil {
+			return Cron{}, fmt.Errorf("day of week: %w", err)
+		}
+		return Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: weekday, StartOfDay: startOfDay}, nil
+
+	case dowField == "*":
+		day, err := parseNumber(domField, 1, 31)
+		if err != nil {
+			return Cron{}, fmt.Errorf("day of month: %w", err)
+		}
+		return Cron{Type: adverity.CronTypeMonth, Interval: 1, IntervalStart: day, StartOfDay: startOfDay}, nil
+
+	default:
+		return Cron{}, fmt.Errorf("day of month and day of week cannot both be restricted")
+	}
+}
+
+// FormatCronExpression translates Adverity cron fields into a standard cron expression.
+// It is the inverse of ParseCronExpression and returns an error for schedules which
+// cannot be expressed in cron syntax (e.g. every 2 days).
+func FormatCronExpression(c Cron) (string, error) {
+	hour, minute, err := parseStartOfDay(c.StartOfDay)
+	if err != nil {
+		return "", err
+	}
+
+	if c.Type != adverity.CronTypeHour && c.Interval != 1 {
+		return "", fmt.Errorf("a cron type of %q with an interval of %d cannot be expressed in cron syntax", c.Type, c.Interval)
+	}
+
+	switch c.Type {
+	case adverity.CronTypeHour:
+		if c.Interval < 1 || 24%c.Interval != 0 {
+			return "", fmt.Errorf("an hourly interval of %d cannot be expressed in cron syntax", c.Interval)
+		}
+		if c.IntervalStart < 0 || c.IntervalStart >= c.Interval {
+			return "", fmt.Errorf("an hourly interval start of %d cannot be expressed in cron syntax", c.IntervalStart)
+		}
+		switch {
+		case c.Interval == 1:
+			return fmt.Sprintf("%d * * * *", minute), nil
+		case c.IntervalStart == 0:
+			return fmt.Sprintf("%d */%d * * *", minute, c.Interval), nil
+		default:
+			return fmt.Sprintf("%d %d/%d * * *", minute, c.IntervalStart, c.Interval), nil
+		}
+	case adverity.CronTypeDay:
+		return fmt.Sprintf("%d %d * * *", minute, hour), nil
+	case adverity.CronTypeWeekday:
+		return fmt.Sprintf("%d %d * * 1-5", minute, hour), nil
+	case adverity.CronTypeWeek:
+		if c.IntervalStart < 1 || c.IntervalStart > 7 {
+			return "", fmt.Errorf("invalid weekday %d, expected 1 (Monday) to 7 (Sunday)", c.IntervalStart)
+		}
+		return fmt.Sprintf("%d %d * * %d", minute, hour, c.IntervalStart%7), nil
+	case adverity.CronTypeMonth:
+		if c.IntervalStart < 1 || c.IntervalStart > 31 {
+			return "", fmt.Errorf("invalid day of month %d", c.IntervalStart)
+		}
+		return fmt.Sprintf("%d %d %d * *", minute, hour, c.IntervalStart), nil
+	default:
+		return "", fmt.Errorf("unsupported cron type %q", c.Type)
+	}
+}
+
+// CronFromSchedule returns the cron fields of a schedule, deriving them from the cron preset if
+// the cron type is not set.
+func CronFromSchedule(s adverity.Schedule) (Cron, error) {
+	c := Cron{Interval: 1, StartOfDay: "00:00:00"}
+
+	switch {
+	case s.CronType != nil:
+		c.Type = *s.CronType
+	case s.CronPreset != nil:
+		preset, ok := adverity.CronPresets[*s.CronPreset]
+		if !ok {
+			return Cron{}, fmt.Errorf("unsupported cron preset %q", *s.CronPreset)
+		}
+		c.Type = preset.Type
+		c.Interval = preset.Interval
+	default:
+		return Cron{}, fmt.Errorf("schedule has neither a cron type nor a cron preset")
+	}
+
+	if s.CronInterval != nil {
+		c.Interval = *s.CronInterval
+	}
+	if s.CronIntervalStart != nil {
+		c.IntervalStart = *s.CronIntervalStart
+	} else if c.Type == adverity.CronTypeWeek || c.Type == adverity.CronTypeMonth {
+		c.IntervalStart = 1
+	}
+	if s.CronStartOfDay != nil {
+		c.StartOfDay = *s.CronStartOfDay
+	}
+
+	return c, nil
+}
+
+// EquivalentCronExpressions reports whether two cron expressions translate to the same Adverity cron fields.
+func EquivalentCronExpressions(a, b string) bool {
+	ca, errA := ParseCronExpression(a)
+	cb, errB := ParseCronExpression(b)
+	return errA == nil && errB == nil && ca == cb
+}
+
+func parseNumber(field string, lower, upper int64) (int64, error) {
+	n, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a single number, got %q", field)
+	}
+	if n < lower || n > upper {
+		return 0, fmt.Errorf("%d is out of range %d-%d", n, lower, upper)
+	}
+	return n, nil
+}
+
+func parseHourStep(field string) (int64, int64, error) {
+	if field == "*" {
+		return 0, 1, nil
+	}
+
+	base, step, _ := strings.Cut(field, "/")
+	interval, err := parseNumber(step, 1, 23)
+	if err != nil {
+		return 0, 0, fmt.Errorf("step: %w", err)
+	}
+	if 24%interval != 0 {
+		return 0, 0, fmt.Errorf("step %d does not divide 24, so the runs are not evenly spaced across days", interval)
+	}
+
+	var start int64
+	if base != "*" {
+		start, err = parseNumber(base, 0, 23)
+		if err != nil {
+			return 0, 0, fmt.Errorf("start: %w", err)
+		}
+		if start >= interval {
+			return 0, 0, fmt.Errorf("start %d must be lower than the step %d", start, interval)
+		}
+	}
+
+	return start, interval, nil
+}
+
+func parseWeekday(field string) (int64, error) {
+	if n, ok := weekdayNames[strings.ToUpper(field)]; ok {
+		field = strconv.FormatInt(n, 10)
+	}
+	n, err := parseNumber(field, 0, 7)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 7, nil // Adverity uses ISO weekdays where Sunday is 7
+	}
+	return n, nil
+}
+
+func isWeekdayRange(field string) bool {
+	from, to, ok := strings.Cut(field, "-")
+	if !ok {
+		return false
+	}
+	f, errFrom := parseWeekday(from)
+	t, errTo := parseWeekday(to)
+	return errFrom == nil && errTo == nil && f == 1 && t == 5
+}
+
+func parseStartOfDay(value string) (int64, int64, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, 0, fmt.Errorf("invalid start of day %q, expected HH:MM:SS", value)
+	}
+	hour, errHour := parseNumber(parts[0], 0, 23)
+	minute, errMinute := parseNumber(parts[1], 0, 59)
+	second, errSecond := parseNumber(parts[2], 0, 59)
+	if errHour != nil || errMinute != nil || errSecond != nil {
+		return 0, 0, fmt.Errorf("invalid start of day %q, expected HH:MM:SS", value)
+	}
+	if second != 0 {
+		return 0, 0, fmt.Errorf("a start of day with seconds (%s) cannot be expressed in cron syntax", value)
+	}
+	return hour, minute, nil
+}
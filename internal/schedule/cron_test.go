@@ -0,0 +1,409 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schedule
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestParseCronExpression(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expr string
+		want Cron
+	}{
+		"every hour": {
+			expr: "15 * * * *",
+			want: Cron{Type: adverity.CronTypeHour, Interval: 1, IntervalStart: 0, StartOfDay: "00:15:00"},
+		},
+		"every 3 hours": {
+			expr: "0 */3 * * *",
+			want: Cron{Type: adverity.CronTypeHour, Interval: 3, IntervalStart: 0, StartOfDay: "00:00:00"},
+		},
+		"every 6 hours starting at 2": {
+			expr: "45 2/6 * * *",
+			want: Cron{Type: adverity.CronTypeHour, Interval: 6, IntervalStart: 2, StartOfDay: "00:45:00"},
+		},
+		"every 12 hours": {
+			expr: "5 */12 * * *",
+			want: Cron{Type: adverity.CronTypeHour, Interval: 12, IntervalStart: 0, StartOfDay: "00:05:00"},
+		},
+		"every day": {
+			expr: "30 3 * * *",
+			want: Cron{Type: adverity.CronTypeDay, Interval: 1, IntervalStart: 1, StartOfDay: "03:30:00"},
+		},
+		"every day at midnight": {
+			expr: "0 0 * * *",
+			want: Cron{Type: adverity.CronTypeDay, Interval: 1, IntervalStart: 1, StartOfDay: "00:00:00"},
+		},
+		"every weekday": {
+			expr: "30 3 * * 1-5",
+			want: Cron{Type: adverity.CronTypeWeekday, Interval: 1, IntervalStart: 1, StartOfDay: "03:30:00"},
+		},
+		"every weekday by name": {
+			expr: "30 3 * * MON-FRI",
+			want: Cron{Type: adverity.CronTypeWeekday, Interval: 1, IntervalStart: 1, StartOfDay: "03:30:00"},
+		},
+		"every monday": {
+			expr: "0 6 * * 1",
+			want: Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 1, StartOfDay: "06:00:00"},
+		},
+		"every sunday as 0": {
+			expr: "0 6 * * 0",
+			want: Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 7, StartOfDay: "06:00:00"},
+		},
+		"every sunday as 7": {
+			expr: "0 6 * * 7",
+			want: Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 7, StartOfDay: "06:00:00"},
+		},
+		"every saturday by lowercase name": {
+			expr: "0 6 * * sat",
+			want: Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 6, StartOfDay: "06:00:00"},
+		},
+		"every month on the 1st": {
+			expr: "0 4 1 * *",
+			want: Cron{Type: adverity.CronTypeMonth, Interval: 1, IntervalStart: 1, StartOfDay: "04:00:00"},
+		},
+		"every month on the 31st": {
+			expr: "59 23 31 * *",
+			want: Cron{Type: adverity.CronTypeMonth, Interval: 1, IntervalStart: 31, StartOfDay: "23:59:00"},
+		},
+		"extra whitespace": {
+			expr: "  30   3 *  * *  ",
+			want: Cron{Type: adverity.CronTypeDay, Interval: 1, IntervalStart: 1, StartOfDay: "03:30:00"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCronExpression(testCase.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.want {
+				t.Errorf("got %+v, want %+v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestParseCronExpression_Unrepresentable(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expr    string
+		wantErr string
+	}{
+		"too few fields": {
+			expr:    "30 3 * *",
+			wantErr: "expected 5 fields",
+		},
+		"six fields with seconds": {
+			expr:    "0 30 3 * * *",
+			wantErr: "expected 5 fields",
+		},
+		"empty": {
+			expr:    "",
+			wantErr: "expected 5 fields",
+		},
+		"minute step": {
+			expr:    "*/15 * * * *",
+			wantErr: "minute",
+		},
+		"minute list": {
+			expr:    "0,30 3 * * *",
+			wantErr: "minute",
+		},
+		"minute out of range": {
+			expr:    "60 3 * * *",
+			wantErr: "out of range",
+		},
+		"hour out of range": {
+			expr:    "0 24 * * *",
+			wantErr: "out of range",
+		},
+		"hour list": {
+			expr:    "0 3,15 * * *",
+			wantErr: "hour",
+		},
+		"hour range": {
+			expr:    "0 9-17 * * *",
+			wantErr: "hour",
+		},
+		"hour step not dividing 24": {
+			expr:    "0 */5 * * *",
+			wantErr: "does not divide 24",
+		},
+		"hour step start beyond step": {
+			expr:    "0 7/6 * * *",
+			wantErr: "must be lower than the step",
+		},
+		"hourly on specific weekday": {
+			expr:    "0 */6 * * 1",
+			wantErr: "cannot be restricted to specific days",
+		},
+		"specific month": {
+			expr:    "0 3 1 1 *",
+			wantErr: "month",
+		},
+		"day of month step": {
+			expr:    "0 3 */2 * *",
+			wantErr: "day of month",
+		},
+		"day of month out of range": {
+			expr:    "0 3 32 * *",
+			wantErr: "out of range",
+		},
+		"day of month and day of week": {
+			expr:    "0 3 1 * 1",
+			wantErr: "cannot both be restricted",
+		},
+		"weekday list": {
+			expr:    "0 3 * * 1,3,5",
+			wantErr: "day of week",
+		},
+		"weekend range": {
+			expr:    "0 3 * * 6-7",
+			wantErr: "day of week",
+		},
+		"unknown weekday name": {
+			expr:    "0 3 * * FOO",
+			wantErr: "day of week",
+		},
+		"macro": {
+			expr:    "@daily",
+			wantErr: "expected 5 fields",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseCronExpression(testCase.expr)
+			if err == nil {
+				t.Fatalf("expected error containing %q, got none", testCase.wantErr)
+			}
+			if !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Errorf("expected error containing %q, got %q", testCase.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestFormatCronExpression(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		cron    Cron
+		want    string
+		wantErr string
+	}{
+		"every hour": {
+			cron: Cron{Type: adverity.CronTypeHour, Interval: 1, StartOfDay: "00:15:00"},
+			want: "15 * * * *",
+		},
+		"every 3 hours": {
+			cron: Cron{Type: adverity.CronTypeHour, Interval: 3, StartOfDay: "00:00:00"},
+			want: "0 */3 * * *",
+		},
+		"every 6 hours starting at 2": {
+			cron: Cron{Type: adverity.CronTypeHour, Interval: 6, IntervalStart: 2, StartOfDay: "00:45:00"},
+			want: "45 2/6 * * *",
+		},
+		"every day": {
+			cron: Cron{Type: adverity.CronTypeDay, Interval: 1, StartOfDay: "03:30:00"},
+			want: "30 3 * * *",
+		},
+		"every weekday": {
+			cron: Cron{Type: adverity.CronTypeWeekday, Interval: 1, StartOfDay: "03:30:00"},
+			want: "30 3 * * 1-5",
+		},
+		"every sunday": {
+			cron: Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 7, StartOfDay: "06:00:00"},
+			want: "0 6 * * 0",
+		},
+		"every month": {
+			cron: Cron{Type: adverity.CronTypeMonth, Interval: 1, IntervalStart: 15, StartOfDay: "04:00:00"},
+			want: "0 4 15 * *",
+		},
+		"every 2 days": {
+			cron:    Cron{Type: adverity.CronTypeDay, Interval: 2, StartOfDay: "03:30:00"},
+			wantErr: "cannot be expressed",
+		},
+		"every 2 weeks": {
+			cron:    Cron{Type: adverity.CronTypeWeek, Interval: 2, IntervalStart: 1, StartOfDay: "03:30:00"},
+			wantErr: "cannot be expressed",
+		},
+		"every 5 hours": {
+			cron:    Cron{Type: adverity.CronTypeHour, Interval: 5, StartOfDay: "00:00:00"},
+			wantErr: "cannot be expressed",
+		},
+		"start of day with seconds": {
+			cron:    Cron{Type: adverity.CronTypeDay, Interval: 1, StartOfDay: "03:33:33"},
+			wantErr: "seconds",
+		},
+		"invalid start of day": {
+			cron:    Cron{Type: adverity.CronTypeDay, Interval: 1, StartOfDay: "3:30"},
+			wantErr: "expected HH:MM:SS",
+		},
+		"invalid weekday": {
+			cron:    Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 0, StartOfDay: "03:30:00"},
+			wantErr: "invalid weekday",
+		},
+		"unsupported cron type": {
+			cron:    Cron{Type: "year", Interval: 1, StartOfDay: "03:30:00"},
+			wantErr: "unsupported cron type",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FormatCronExpression(testCase.cron)
+			if testCase.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", testCase.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.want {
+				t.Errorf("got %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestCronExpressionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"0 * * * *",
+		"10 */2 * * *",
+		"20 1/4 * * *",
+		"0 3 * * *",
+		"30 3 * * 1-5",
+		"0 6 * * 3",
+		"0 6 * * 0",
+		"0 4 28 * *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			t.Parallel()
+
+			cron, err := ParseCronExpression(expr)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %s", err)
+			}
+			got, err := FormatCronExpression(cron)
+			if err != nil {
+				t.Fatalf("unexpected format error: %s", err)
+			}
+			if got != expr {
+				t.Errorf("got %q, want %q", got, expr)
+			}
+		})
+	}
+}
+
+func TestCronFromSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		schedule adverity.Schedule
+		want     Cron
+		wantErr  bool
+	}{
+		"preset": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_6_HOURS")},
+			want:     Cron{Type: adverity.CronTypeHour, Interval: 6, StartOfDay: "00:00:00"},
+		},
+		"preset with start of day": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_DAY"), CronStartOfDay: ptr("03:30:00")},
+			want:     Cron{Type: adverity.CronTypeDay, Interval: 1, StartOfDay: "03:30:00"},
+		},
+		"weekly preset defaults to monday": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_WEEK")},
+			want:     Cron{Type: adverity.CronTypeWeek, Interval: 1, IntervalStart: 1, StartOfDay: "00:00:00"},
+		},
+		"cron type takes precedence over preset": {
+			schedule: adverity.Schedule{
+				CronPreset:        ptr("CRON_EVERY_DAY"),
+				CronType:          ptr(adverity.CronTypeMonth),
+				CronInterval:      ptr(int64(1)),
+				CronIntervalStart: ptr(int64(15)),
+				CronStartOfDay:    ptr("04:00:00"),
+			},
+			want: Cron{Type: adverity.CronTypeMonth, Interval: 1, IntervalStart: 15, StartOfDay: "04:00:00"},
+		},
+		"unknown preset": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_FORTNIGHT")},
+			wantErr:  true,
+		},
+		"neither preset nor type": {
+			schedule: adverity.Schedule{},
+			wantErr:  true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := CronFromSchedule(testCase.schedule)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.want {
+				t.Errorf("got %+v, want %+v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestEquivalentCronExpressions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"identical":            {a: "30 3 * * *", b: "30 3 * * *", want: true},
+		"weekday names":        {a: "30 3 * * 1-5", b: "30 3 * * MON-FRI", want: true},
+		"sunday as 0 and 7":    {a: "0 6 * * 0", b: "0 6 * * 7", want: true},
+		"leading zeros":        {a: "05 03 * * *", b: "5 3 * * *", want: true},
+		"hourly without start": {a: "0 */6 * * *", b: "0 0/6 * * *", want: true},
+		"different time":       {a: "30 3 * * *", b: "30 4 * * *", want: false},
+		"invalid expression":   {a: "30 3 * * *", b: "invalid", want: false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := EquivalentCronExpressions(testCase.a, testCase.b); got != testCase.want {
+				t.Errorf("got %t, want %t", got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+// Delta types of a custom time range, i.e. the calendar unit of delta_interval and delta_interval_start.
+// Adverity does not describe the fields of schedules in the OPTIONS metadata, so these codes are
+// assumptions which are not confirmed by the API.
+const (
+	deltaTypeDay   int64 = 1
+	deltaTypeWeek  int64 = 2
+	deltaTypeMonth int64 = 3
+	deltaTypeYear  int64 = 4
+)
+
+// DateRange is an inclusive range of days.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// FetchRange returns the days a run of the schedule at the given moment would fetch.
+//
+// The day of the run starts at the delta start of day and is moved back by the offset days.
+// The range then covers delta interval units (days, ISO weeks, months or years) ending delta
+// interval start units before the unit of that day, where 0 fetches the current unit up to that day.
+// Only custom time ranges are supported, since the delta fields implied by the other time range
+// presets are not known. Custom time ranges can additionally pin the start and end of the range
+// with fixed dates.
+func FetchRange(s adverity.Schedule, at time.Time) (DateRange, error) {
+	at = at.UTC()
+
+	notBefore, err := NotBefore(s)
+	if err != nil {
+		return DateRange{}, err
+	}
+	if at.Before(notBefore) {
+		return DateRange{}, fmt.Errorf("the schedule does not run before %s", notBefore.Format(time.RFC3339))
+	}
+
+	if s.TimeRangePreset != nil && *s.TimeRangePreset != adverity.TimeRangePresetCustom {
+		return DateRange{}, fmt.Errorf("unsupported time range preset %d, only custom time ranges (%d) can be computed", *s.TimeRangePreset, adverity.TimeRangePresetCustom)
+	}
+
+	deltaType, interval, start := deltaTypeDay, int64(1), int64(0)
+	if s.DeltaType != nil {
+		deltaType = *s.DeltaType
+	}
+	if s.DeltaInterval != nil {
+		interval = *s.DeltaInterval
+	}
+	if s.DeltaIntervalStart != nil {
+		start = *s.DeltaIntervalStart
+	}
+	if interval < 1 {
+		return DateRange{}, fmt.Errorf("invalid delta interval %d, expected at least 1", interval)
+	}
+	if start < 0 {
+		return DateRange{}, fmt.Errorf("invalid delta interval start %d, expected at least 0", start)
+	}
+
+	if s.DeltaStartOfDay != nil {
+		startOfDay, err := parseTimeOfDay(*s.DeltaStartOfDay)
+		if err != nil {
+			return DateRange{}, err
+		}
+		at = at.Add(-startOfDay)
+	}
+	day := truncateDay(at)
+	if s.OffsetDays != nil {
+		day = day.AddDate(0, 0, -int(*s.OffsetDays))
+	}
+
+	rangeStart, err := unitStart(day, deltaType, start+interval-1)
+	if err != nil {
+		return DateRange{}, err
+	}
+	rangeEnd := day
+	if start > 0 {
+		next, _ := unitStart(day, deltaType, start-1)
+		rangeEnd = next.AddDate(0, 0, -1)
+	}
+
+	if s.FixedStart != nil {
+		if rangeStart, err = time.Parse(dateLayout, *s.FixedStart); err != nil {
+			return DateRange{}, fmt.Errorf("invalid fixed start %q, expected YYYY-MM-DD", *s.FixedStart)
+		}
+	}
+	if s.FixedEnd != nil {
+		if rangeEnd, err = time.Parse(dateLayout, *s.FixedEnd); err != nil {
+			return DateRange{}, fmt.Errorf("invalid fixed end %q, expected YYYY-MM-DD", *s.FixedEnd)
+		}
+	}
+	if rangeEnd.Before(rangeStart) {
+		return DateRange{}, fmt.Errorf("the range ends (%s) before it starts (%s)", rangeEnd.Format(dateLayout), rangeStart.Format(dateLayout))
+	}
+
+	return DateRange{Start: rangeStart, End: rangeEnd}, nil
+}
+
+// unitStart returns the first day of the unit which is back units before the unit of day.
+func unitStart(day time.Time, deltaType int64, back int64) (time.Time, error) {
+	n := int(back)
+	switch deltaType {
+	case deltaTypeDay:
+		return day.AddDate(0, 0, -n), nil
+	case deltaTypeWeek:
+		return startOfWeek(day).AddDate(0, 0, -7*n), nil
+	case deltaTypeMonth:
+		return time.Date(day.Year(), day.Month()-time.Month(n), 1, 0, 0, 0, 0, time.UTC), nil
+	case deltaTypeYear:
+		return time.Date(day.Year()-n, time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported delta type %d", deltaType)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+// custom returns a schedule with a custom time range of interval units ending start units before the unit of the run.
+func custom(deltaType, interval, start int64) adverity.Schedule {
+	return adverity.Schedule{TimeRangePreset: ptr(adverity.TimeRangePresetCustom), DeltaType: &deltaType, DeltaInterval: &interval, DeltaIntervalStart: &start}
+}
+
+func withDeltaStartOfDay(s adverity.Schedule, startOfDay string) adverity.Schedule {
+	s.DeltaStartOfDay = &startOfDay
+	return s
+}
+
+func withOffsetDays(s adverity.Schedule, days int64) adverity.Schedule {
+	s.OffsetDays = &days
+	return s
+}
+
+func TestFetchRange(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	at := time.Date(2025, time.March, 5, 3, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		schedule  adverity.Schedule
+		wantStart string
+		wantEnd   string
+	}{
+		"default is the day of the run": {
+			schedule:  adverity.Schedule{},
+			wantStart: "2025-03-05",
+			wantEnd:   "2025-03-05",
+		},
+		"yesterday": {
+			schedule:  custom(deltaTypeDay, 1, 1),
+			wantStart: "2025-03-04",
+			wantEnd:   "2025-03-04",
+		},
+		"last 7 days": {
+			schedule:  custom(deltaTypeDay, 7, 1),
+			wantStart: "2025-02-26",
+			wantEnd:   "2025-03-04",
+		},
+		"this month": {
+			schedule:  custom(deltaTypeMonth, 1, 0),
+			wantStart: "2025-03-01",
+			wantEnd:   "2025-03-05",
+		},
+		"last month": {
+			schedule:  custom(deltaTypeMonth, 1, 1),
+			wantStart: "2025-02-01",
+			wantEnd:   "2025-02-28",
+		},
+		"last year": {
+			schedule:  custom(deltaTypeYear, 1, 1),
+			wantStart: "2024-01-01",
+			wantEnd:   "2024-12-31",
+		},
+		"custom previous 2 weeks": {
+			schedule:  adverity.Schedule{TimeRangePreset: ptr(int64(0)), DeltaType: ptr(deltaTypeWeek), DeltaInterval: ptr(int64(2)), DeltaIntervalStart: ptr(int64(1))},
+			wantStart: "2025-02-17",
+			wantEnd:   "2025-03-02",
+		},
+		"custom fixed start": {
+			schedule:  adverity.Schedule{TimeRangePreset: ptr(int64(0)), FixedStart: ptr("2025-01-01")},
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-03-05",
+		},
+		"custom fixed start and end": {
+			schedule:  adverity.Schedule{TimeRangePreset: ptr(int64(0)), FixedStart: ptr("2025-01-01"), FixedEnd: ptr("2025-01-31")},
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-01-31",
+		},
+		"delta start of day moves the run to the previous day": {
+			schedule:  withDeltaStartOfDay(custom(deltaTypeDay, 1, 1), "06:00:00"),
+			wantStart: "2025-03-03",
+			wantEnd:   "2025-03-03",
+		},
+		"offset days": {
+			schedule:  withOffsetDays(custom(deltaTypeMonth, 1, 1), 5),
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-01-31",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FetchRange(testCase.schedule, at)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if start := got.Start.Format(dateLayout); start != testCase.wantStart {
+				t.Errorf("got start %s, want %s", start, testCase.wantStart)
+			}
+			if end := got.End.Format(dateLayout); end != testCase.wantEnd {
+				t.Errorf("got end %s, want %s", end, testCase.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFetchRange_Invalid(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, time.March, 5, 3, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		schedule adverity.Schedule
+		wantErr  string
+	}{
+		"before not before": {
+			schedule: adverity.Schedule{NotBeforeDate: ptr("2025-03-05"), NotBeforeTime: ptr("04:00:00")},
+			wantErr:  "does not run before 2025-03-05T04:00:00Z",
+		},
+		"preset": {
+			schedule: adverity.Schedule{TimeRangePreset: ptr(int64(2))},
+			wantErr:  "unsupported time range preset 2",
+		},
+		"unknown delta type": {
+			schedule: adverity.Schedule{DeltaType: ptr(int64(9))},
+			wantErr:  "unsupported delta type",
+		},
+		"fixed end before start": {
+			schedule: adverity.Schedule{FixedStart: ptr("2025-02-01"), FixedEnd: ptr("2025-01-01")},
+			wantErr:  "ends (2025-01-01) before it starts (2025-02-01)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := FetchRange(testCase.schedule, at)
+			if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+const dateLayout = "2006-01-02"
+
+// maxRunGap limits the days searched for the next run. Some monthly schedules never run, e.g. on the 31st
+// every 12 months counted from April, and the gap between runs of a schedule which runs on the 29th of
+// February every 12 months is at most 8 years.
+const maxRunGap = 10 * 366
+
+// epoch anchors intervals greater than 1 for schedules without a not before date.
+var epoch = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// NextRuns returns the next count run times of a schedule at or after from, in UTC.
+//
+// Intervals greater than 1 (e.g. every 2 days) are counted from the not before date of the
+// schedule, or from 1970-01-01 if it has none. Hourly intervals restart at every day, and
+// monthly schedules skip months which don't have the configured day. It returns an error if the schedule
+// does not run within 10 years after from or the previous run.
+func NextRuns(s adverity.Schedule, from time.Time, count int) ([]time.Time, error) {
+	cron, err := CronFromSchedule(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCron(cron); err != nil {
+		return nil, err
+	}
+	startOfDay, err := parseTimeOfDay(cron.StartOfDay)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := epoch
+	from = from.UTC()
+	notBefore, err := NotBefore(s)
+	if err != nil {
+		return nil, err
+	}
+	if !notBefore.IsZero() {
+		anchor = truncateDay(notBefore)
+		if from.Before(notBefore) {
+			from = notBefore
+		}
+	}
+
+	runs := make([]time.Time, 0, count)
+	searched := 0
+	for day := truncateDay(from); len(runs) < count; day = day.AddDate(0, 0, 1) {
+		if searched++; searched > maxRunGap {
+			if len(runs) == 0 {
+				return nil, fmt.Errorf("the schedule does not run within 10 years after %s", from.Format(time.RFC3339))
+			}
+			return nil, fmt.Errorf("the schedule does not run within 10 years after %s, found only %d of %d runs",
+				runs[len(runs)-1].Format(time.RFC3339), len(runs), count)
+		}
+		for _, run := range runsOnDay(cron, startOfDay, anchor, day) {
+			if !run.Before(from) && len(runs) < count {
+				runs = append(runs, run)
+				searched = 0
+			}
+		}
+	}
+
+	return runs, nil
+}
+
+// NotBefore returns the moment before which a schedule does not run, or the zero time if it has none.
+func NotBefore(s adverity.Schedule) (time.Time, error) {
+	if s.NotBeforeDate == nil {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(dateLayout, *s.NotBeforeDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid not before date %q, expected YYYY-MM-DD", *s.NotBeforeDate)
+	}
+	if s.NotBeforeTime != nil {
+		offset, err := parseTimeOfDay(*s.NotBeforeTime)
+		if err != nil {
+			return time.Time{}, err
+		}
+		date = date.Add(offset)
+	}
+
+	return date, nil
+}
+
+func runsOnDay(c Cron, startOfDay time.Duration, anchor, day time.Time) []time.Time {
+	switch c.Type {
+	case adverity.CronTypeHour:
+		var runs []time.Time
+		minutes := startOfDay % time.Hour // only minutes and seconds apply to hourly schedules
+		for hour := c.IntervalStart; hour < 24; hour += c.Interval {
+			runs = append(runs, day.Add(time.Duration(hour)*time.Hour+minutes))
+		}
+		return runs
+	case adverity.CronTypeDay:
+		if mod(daysBetween(anchor, day), c.Interval) != 0 {
+			return nil
+		}
+	case adverity.CronTypeWeekday:
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			return nil
+		}
+	case adverity.CronTypeWeek:
+		if isoWeekday(day) != c.IntervalStart || mod(daysBetween(startOfWeek(anchor), day)/7, c.Interval) != 0 {
+			return nil
+		}
+	case adverity.CronTypeMonth:
+		months := int64(day.Year()-anchor.Year())*12 + int64(day.Month()-anchor.Month())
+		if int64(day.Day()) != c.IntervalStart || mod(months, c.Interval) != 0 {
+			return nil
+		}
+	}
+
+	return []time.Time{day.Add(startOfDay)}
+}
+
+func validateCron(c Cron) error {
+	if c.Interval < 1 {
+		return fmt.Errorf("invalid cron interval %d, expected at least 1", c.Interval)
+	}
+
+	switch c.Type {
+	case adverity.CronTypeHour:
+		if c.IntervalStart < 0 || c.IntervalStart > 23 {
+			return fmt.Errorf("invalid hour %d, expected 0 to 23", c.IntervalStart)
+		}
+	case adverity.CronTypeDay, adverity.CronTypeWeekday:
+	case adverity.CronTypeWeek:
+		if c.IntervalStart < 1 || c.IntervalStart > 7 {
+			return fmt.Errorf("invalid weekday %d, expected 1 (Monday) to 7 (Sunday)", c.IntervalStart)
+		}
+	case adverity.CronTypeMonth:
+		if c.IntervalStart < 1 || c.IntervalStart > 31 {
+			return fmt.Errorf("invalid day of month %d", c.IntervalStart)
+		}
+	default:
+		return fmt.Errorf("unsupported cron type %q", c.Type)
+	}
+
+	return nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse(time.TimeOnly, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM:SS", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int64 {
+	return int64(truncateDay(to).Sub(truncateDay(from)).Hours() / 24)
+}
+
+func isoWeekday(t time.Time) int64 {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int64(t.Weekday())
+}
+
+func startOfWeek(t time.Time) time.Time {
+	return truncateDay(t).AddDate(0, 0, -int(isoWeekday(t)-1))
+}
+
+func mod(a, b int64) int64 {
+	return ((a % b) + b) % b
+}
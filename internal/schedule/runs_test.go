@@ -0,0 +1,144 @@
+// Copyright codewolf.dev 2025, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schedule
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-adverity/internal/adverity"
+)
+
+func TestNextRuns(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	from := time.Date(2025, time.January, 29, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		schedule adverity.Schedule
+		count    int
+		want     []string
+	}{
+		"every 6 hours preset": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_6_HOURS"), CronStartOfDay: ptr("00:15:00")},
+			count:    3,
+			want:     []string{"2025-01-29T12:15:00Z", "2025-01-29T18:15:00Z", "2025-01-30T00:15:00Z"},
+		},
+		"every 12 hours starting at 5 ignores the hour of the start of day": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeHour), CronInterval: ptr(int64(12)), CronIntervalStart: ptr(int64(5)), CronStartOfDay: ptr("08:30:00")},
+			count:    2,
+			want:     []string{"2025-01-29T17:30:00Z", "2025-01-30T05:30:00Z"},
+		},
+		"every day after the start of day": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_DAY"), CronStartOfDay: ptr("03:33:33")},
+			count:    2,
+			want:     []string{"2025-01-30T03:33:33Z", "2025-01-31T03:33:33Z"},
+		},
+		"every day at from": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_DAY"), CronStartOfDay: ptr("12:00:00")},
+			count:    1,
+			want:     []string{"2025-01-29T12:00:00Z"},
+		},
+		"every 2 days counted from not before date": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeDay), CronInterval: ptr(int64(2)), CronStartOfDay: ptr("06:00:00"), NotBeforeDate: ptr("2025-01-28")},
+			count:    2,
+			want:     []string{"2025-01-30T06:00:00Z", "2025-02-01T06:00:00Z"},
+		},
+		"every weekday skips the weekend": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_WEEKDAY"), CronStartOfDay: ptr("06:00:00")},
+			count:    4,
+			want:     []string{"2025-01-30T06:00:00Z", "2025-01-31T06:00:00Z", "2025-02-03T06:00:00Z", "2025-02-04T06:00:00Z"},
+		},
+		"every week on sunday": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeWeek), CronInterval: ptr(int64(1)), CronIntervalStart: ptr(int64(7)), CronStartOfDay: ptr("06:00:00")},
+			count:    2,
+			want:     []string{"2025-02-02T06:00:00Z", "2025-02-09T06:00:00Z"},
+		},
+		"every month on the 31st skips shorter months": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeMonth), CronInterval: ptr(int64(1)), CronIntervalStart: ptr(int64(31)), CronStartOfDay: ptr("00:00:00")},
+			count:    3,
+			want:     []string{"2025-01-31T00:00:00Z", "2025-03-31T00:00:00Z", "2025-05-31T00:00:00Z"},
+		},
+		"every 12 months on february 29th runs in leap years": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeMonth), CronInterval: ptr(int64(12)), CronIntervalStart: ptr(int64(29)), CronStartOfDay: ptr("00:00:00"), NotBeforeDate: ptr("2024-02-01")},
+			count:    2,
+			want:     []string{"2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z"},
+		},
+		"not before date and time": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_HOUR"), NotBeforeDate: ptr("2025-02-01"), NotBeforeTime: ptr("10:30:00")},
+			count:    2,
+			want:     []string{"2025-02-01T11:00:00Z", "2025-02-01T12:00:00Z"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			runs, err := NextRuns(testCase.schedule, from, testCase.count)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got []string
+			for _, run := range runs {
+				got = append(got, run.Format(time.RFC3339))
+			}
+			if !slices.Equal(got, testCase.want) {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNextRuns_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		schedule adverity.Schedule
+		wantErr  string
+	}{
+		"no cron fields": {
+			schedule: adverity.Schedule{},
+			wantErr:  "neither a cron type nor a cron preset",
+		},
+		"zero interval": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeDay), CronInterval: ptr(int64(0))},
+			wantErr:  "invalid cron interval",
+		},
+		"invalid weekday": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeWeek), CronIntervalStart: ptr(int64(8))},
+			wantErr:  "invalid weekday",
+		},
+		"invalid start of day": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_DAY"), CronStartOfDay: ptr("25:00:00")},
+			wantErr:  "expected HH:MM:SS",
+		},
+		"never runs on a day the anchored months don't have": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeMonth), CronInterval: ptr(int64(12)), CronIntervalStart: ptr(int64(31)), NotBeforeDate: ptr("2025-04-01")},
+			wantErr:  "does not run within 10 years",
+		},
+		"never runs on the 30th of february": {
+			schedule: adverity.Schedule{CronType: ptr(adverity.CronTypeMonth), CronInterval: ptr(int64(12)), CronIntervalStart: ptr(int64(30)), NotBeforeDate: ptr("2025-02-01")},
+			wantErr:  "does not run within 10 years",
+		},
+		"invalid not before date": {
+			schedule: adverity.Schedule{CronPreset: ptr("CRON_EVERY_DAY"), NotBeforeDate: ptr("2025-02-31")},
+			wantErr:  "invalid not before date",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NextRuns(testCase.schedule, time.Now(), 1)
+			if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+}